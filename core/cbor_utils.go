@@ -0,0 +1,70 @@
+package core
+
+import (
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	cborMajorTypeUnsignedInt = byte(0)
+	cborMajorTypeMap         = byte(5)
+	cborMajorTypeTag         = byte(6)
+
+	cborTagEncodedData = 24 // bytestring holds encoded cbor data item
+	cborTagRational    = 30 // [numerator, denominator], e.g. unit_interval
+)
+
+// cborEncMode is used for everything that ends up in transaction body or witness set.
+// Map keys are sorted canonically (length first, then bytewise) as the ledger expects
+var cborEncMode, _ = cbor.EncOptions{
+	Sort: cbor.SortCanonical,
+}.EncMode()
+
+// appendCborHead appends cbor head (major type + argument) to the dst
+func appendCborHead(dst []byte, majorType byte, value uint64) []byte {
+	majorType <<= 5
+
+	switch {
+	case value < 24:
+		return append(dst, majorType|byte(value))
+	case value <= 0xff:
+		return append(dst, majorType|24, byte(value))
+	case value <= 0xffff:
+		return append(dst, majorType|25, byte(value>>8), byte(value))
+	case value <= 0xffffffff:
+		return append(dst, majorType|26,
+			byte(value>>24), byte(value>>16), byte(value>>8), byte(value))
+	default:
+		return append(dst, majorType|27,
+			byte(value>>56), byte(value>>48), byte(value>>40), byte(value>>32),
+			byte(value>>24), byte(value>>16), byte(value>>8), byte(value))
+	}
+}
+
+// cborOrderedMapItem is one key/value pair of cborOrderedMap
+type cborOrderedMapItem struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// cborOrderedMap is cbor map which keeps keys in the order they were added
+type cborOrderedMap []cborOrderedMapItem
+
+func (m cborOrderedMap) MarshalCBOR() ([]byte, error) {
+	result := appendCborHead(nil, cborMajorTypeMap, uint64(len(m)))
+
+	for _, item := range m {
+		keyBytes, err := cborEncMode.Marshal(item.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		valueBytes, err := cborEncMode.Marshal(item.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(append(result, keyBytes...), valueBytes...)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,357 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/igorcrevar/go-cardano-tx/core/bech32"
+)
+
+const poolIDPrefix = "pool"
+
+// certificate tags as defined in the ledger cddl
+const (
+	certStakeRegistrationTag           = 0
+	certStakeDeregistrationTag         = 1
+	certStakeDelegationTag             = 2
+	certRegistrationTag                = 7  // conway registration with explicit deposit
+	certDeregistrationTag              = 8  // conway deregistration with explicit refund
+	certStakeRegistrationDelegationTag = 11 // conway
+)
+
+// ICertificate is certificate which can be included in the transaction
+type ICertificate interface {
+	// GetCbor returns cbor of the certificate. Deposits which are not set are taken from the protocol parameters
+	GetCbor(protocolParameters ProtocolParameters) ([]byte, error)
+	// GetDeposit returns deposit paid and deposit refunded by the certificate
+	GetDeposit(protocolParameters ProtocolParameters) (deposit uint64, refund uint64)
+	// GetWitnessCredential returns credential which must witness the certificate (nil if witness is not needed)
+	GetWitnessCredential() *CardanoAddressPayload
+}
+
+// StakeRegistrationCertificate registers stake credential. If Deposit is zero shelley certificate is used
+// (deposit is implicit and witness is not needed), otherwise conway certificate with explicit deposit
+type StakeRegistrationCertificate struct {
+	StakeCredential CardanoAddressPayload
+	Deposit         uint64
+}
+
+// StakeDeregistrationCertificate deregisters stake credential and refunds the deposit. If Refund is zero
+// shelley certificate is used (refund is implicit), otherwise conway certificate with explicit refund
+type StakeDeregistrationCertificate struct {
+	StakeCredential CardanoAddressPayload
+	Refund          uint64
+}
+
+// StakeDelegationCertificate delegates stake credential to the pool
+type StakeDelegationCertificate struct {
+	StakeCredential CardanoAddressPayload
+	PoolKeyHash     [KeyHashSize]byte
+}
+
+// StakeRegistrationDelegationCertificate registers stake credential and delegates it to the pool (conway).
+// Zero Deposit is replaced with the stake address deposit from the protocol parameters
+type StakeRegistrationDelegationCertificate struct {
+	StakeCredential CardanoAddressPayload
+	PoolKeyHash     [KeyHashSize]byte
+	Deposit         uint64
+}
+
+var (
+	_ ICertificate = StakeRegistrationCertificate{}
+	_ ICertificate = StakeDeregistrationCertificate{}
+	_ ICertificate = StakeDelegationCertificate{}
+	_ ICertificate = StakeRegistrationDelegationCertificate{}
+)
+
+// NewStakeRegistrationCertificate creates registration certificate for the stake part of the (reward or base) address
+func NewStakeRegistrationCertificate(stakeAddr string) (*StakeRegistrationCertificate, error) {
+	stakeCredential, err := NewStakeCredentialFromAddress(stakeAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeRegistrationCertificate{StakeCredential: stakeCredential}, nil
+}
+
+// NewStakeDeregistrationCertificate creates deregistration certificate for the stake part of the address
+func NewStakeDeregistrationCertificate(stakeAddr string) (*StakeDeregistrationCertificate, error) {
+	stakeCredential, err := NewStakeCredentialFromAddress(stakeAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeDeregistrationCertificate{StakeCredential: stakeCredential}, nil
+}
+
+// NewStakeDelegationCertificate creates delegation certificate. poolID is bech32 (pool1...) or hex pool key hash
+func NewStakeDelegationCertificate(stakeAddr string, poolID string) (*StakeDelegationCertificate, error) {
+	stakeCredential, err := NewStakeCredentialFromAddress(stakeAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	poolKeyHash, err := GetPoolKeyHash(poolID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeDelegationCertificate{StakeCredential: stakeCredential, PoolKeyHash: poolKeyHash}, nil
+}
+
+// NewStakeRegistrationDelegationCertificate creates conway certificate which registers and delegates stake credential
+func NewStakeRegistrationDelegationCertificate(
+	stakeAddr string, poolID string,
+) (*StakeRegistrationDelegationCertificate, error) {
+	stakeCredential, err := NewStakeCredentialFromAddress(stakeAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	poolKeyHash, err := GetPoolKeyHash(poolID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeRegistrationDelegationCertificate{StakeCredential: stakeCredential, PoolKeyHash: poolKeyHash}, nil
+}
+
+func (c StakeRegistrationCertificate) GetCbor(_ ProtocolParameters) ([]byte, error) {
+	if c.Deposit == 0 {
+		return cbor.Marshal([]interface{}{certStakeRegistrationTag, newTxRawCredential(c.StakeCredential)})
+	}
+
+	return cbor.Marshal([]interface{}{certRegistrationTag, newTxRawCredential(c.StakeCredential), c.Deposit})
+}
+
+func (c StakeRegistrationCertificate) GetDeposit(protocolParameters ProtocolParameters) (uint64, uint64) {
+	if c.Deposit == 0 {
+		return protocolParameters.StakeAddressDeposit, 0
+	}
+
+	return c.Deposit, 0
+}
+
+func (c StakeRegistrationCertificate) GetWitnessCredential() *CardanoAddressPayload {
+	if c.Deposit == 0 {
+		return nil // shelley registration certificate does not need witness
+	}
+
+	return &c.StakeCredential
+}
+
+func (c StakeDeregistrationCertificate) GetCbor(_ ProtocolParameters) ([]byte, error) {
+	if c.Refund == 0 {
+		return cbor.Marshal([]interface{}{certStakeDeregistrationTag, newTxRawCredential(c.StakeCredential)})
+	}
+
+	return cbor.Marshal([]interface{}{certDeregistrationTag, newTxRawCredential(c.StakeCredential), c.Refund})
+}
+
+func (c StakeDeregistrationCertificate) GetDeposit(protocolParameters ProtocolParameters) (uint64, uint64) {
+	if c.Refund == 0 {
+		return 0, protocolParameters.StakeAddressDeposit
+	}
+
+	return 0, c.Refund
+}
+
+func (c StakeDeregistrationCertificate) GetWitnessCredential() *CardanoAddressPayload {
+	return &c.StakeCredential
+}
+
+func (c StakeDelegationCertificate) GetCbor(_ ProtocolParameters) ([]byte, error) {
+	return cbor.Marshal([]interface{}{
+		certStakeDelegationTag, newTxRawCredential(c.StakeCredential), c.PoolKeyHash[:],
+	})
+}
+
+func (c StakeDelegationCertificate) GetDeposit(_ ProtocolParameters) (uint64, uint64) {
+	return 0, 0
+}
+
+func (c StakeDelegationCertificate) GetWitnessCredential() *CardanoAddressPayload {
+	return &c.StakeCredential
+}
+
+func (c StakeRegistrationDelegationCertificate) GetCbor(protocolParameters ProtocolParameters) ([]byte, error) {
+	deposit, _ := c.GetDeposit(protocolParameters)
+
+	return cbor.Marshal([]interface{}{
+		certStakeRegistrationDelegationTag, newTxRawCredential(c.StakeCredential), c.PoolKeyHash[:], deposit,
+	})
+}
+
+func (c StakeRegistrationDelegationCertificate) GetDeposit(protocolParameters ProtocolParameters) (uint64, uint64) {
+	if c.Deposit == 0 {
+		return protocolParameters.StakeAddressDeposit, 0
+	}
+
+	return c.Deposit, 0
+}
+
+func (c StakeRegistrationDelegationCertificate) GetWitnessCredential() *CardanoAddressPayload {
+	return &c.StakeCredential
+}
+
+// NewStakeCredentialFromAddress returns stake credential from the reward or base address
+func NewStakeCredentialFromAddress(addr string) (CardanoAddressPayload, error) {
+	cardanoAddr, err := NewCardanoAddressFromString(addr)
+	if err != nil {
+		return CardanoAddressPayload{}, err
+	}
+
+	if stake := cardanoAddr.GetInfo().Stake; stake != nil {
+		return *stake, nil
+	}
+
+	return CardanoAddressPayload{}, fmt.Errorf("address %s does not have stake credential", addr)
+}
+
+// NewStakeCredentialFromKey returns stake credential of the stake verification key
+func NewStakeCredentialFromKey(stakeVerificationKey []byte) (CardanoAddressPayload, error) {
+	keyHash, err := GetKeyHashBytes(stakeVerificationKey)
+	if err != nil {
+		return CardanoAddressPayload{}, err
+	}
+
+	return CardanoAddressPayload{Payload: [KeyHashSize]byte(keyHash)}, nil
+}
+
+// GetPoolKeyHash returns pool key hash from the bech32 pool id (pool1...) or hex key hash
+func GetPoolKeyHash(poolID string) ([KeyHashSize]byte, error) {
+	var (
+		keyHash []byte
+		err     error
+	)
+
+	if !strings.HasPrefix(poolID, poolIDPrefix) {
+		keyHash, err = hex.DecodeString(poolID)
+	} else {
+		var prefix string
+
+		prefix, keyHash, err = bech32.DecodeToBase256(poolID)
+		if err == nil && prefix != poolIDPrefix {
+			err = fmt.Errorf("invalid prefix %s", prefix)
+		}
+	}
+
+	if err != nil {
+		return [KeyHashSize]byte{}, fmt.Errorf("invalid pool id %s: %w", poolID, err)
+	} else if len(keyHash) != KeyHashSize {
+		return [KeyHashSize]byte{}, fmt.Errorf("invalid pool id %s: invalid length %d", poolID, len(keyHash))
+	}
+
+	return [KeyHashSize]byte(keyHash), nil
+}
+
+// txRawCredential is credential as defined in the ledger cddl: [0, addr_keyhash] or [1, script_hash]
+type txRawCredential struct {
+	_        struct{} `cbor:",toarray"`
+	IsScript uint64
+	Hash     []byte
+}
+
+func newTxRawCredential(credential CardanoAddressPayload) txRawCredential {
+	result := txRawCredential{Hash: credential.Payload[:]}
+	if credential.IsScript {
+		result.IsScript = 1
+	}
+
+	return result
+}
+
+type txCertificate struct {
+	certificate  ICertificate
+	policyScript IPolicyScript // not nil if the certificate is witnessed by the script credential
+}
+
+// AddCertificates adds certificates. Deposits and refunds of the certificates are included in Balance
+func (b *TxBuilder) AddCertificates(certificates ...ICertificate) *TxBuilder {
+	for _, cert := range certificates {
+		b.certificates = append(b.certificates, txCertificate{certificate: cert})
+	}
+
+	return b
+}
+
+// AddCertificateWithScript adds certificate of the script hash credential.
+// Native script is attached to the transaction and its signers are included in the fee estimation
+func (b *TxBuilder) AddCertificateWithScript(policyScript IPolicyScript, certificate ICertificate) *TxBuilder {
+	b.certificates = append(b.certificates, txCertificate{
+		certificate:  certificate,
+		policyScript: policyScript,
+	})
+
+	return b
+}
+
+// getCertificates returns cbor of the certificates and adds native scripts of the script certificates into the scripts
+func (b *TxBuilder) getCertificates(scripts map[string][]byte) ([]cbor.RawMessage, error) {
+	if len(b.certificates) == 0 {
+		return nil, nil
+	}
+
+	protocolParameters, err := b.getProtocolParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]cbor.RawMessage, len(b.certificates))
+
+	for i, cert := range b.certificates {
+		if cert.certificate == nil {
+			return nil, errors.New("certificate is nil")
+		}
+
+		result[i], err = cert.certificate.GetCbor(protocolParameters)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %w", i, err)
+		}
+
+		if cert.policyScript == nil {
+			continue
+		}
+
+		credential := cert.certificate.GetWitnessCredential()
+		if credential == nil || !credential.IsScript {
+			return nil, fmt.Errorf("certificate %d: credential is not script credential", i)
+		}
+
+		script, err := getNativeScriptCbor(cert.policyScript)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %w", i, err)
+		}
+
+		hash, err := getNativeScriptHash(script)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %w", i, err)
+		}
+
+		if !bytes.Equal(hash, credential.Payload[:]) {
+			return nil, fmt.Errorf("certificate %d: script hash %x does not match credential %x",
+				i, hash, credential.Payload)
+		}
+
+		if err := addNativeScriptCbor(scripts, script); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// getCertificatesDeposit returns total deposit and total refund of all the certificates
+func (b *TxBuilder) getCertificatesDeposit(protocolParameters ProtocolParameters) (deposit uint64, refund uint64) {
+	for _, cert := range b.certificates {
+		certDeposit, certRefund := cert.certificate.GetDeposit(protocolParameters)
+		deposit += certDeposit
+		refund += certRefund
+	}
+
+	return deposit, refund
+}
@@ -0,0 +1,325 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/igorcrevar/go-cardano-tx/core/bech32"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificates(t *testing.T) {
+	t.Parallel()
+
+	const poolKeyHash = "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"
+
+	wallet, err := GenerateWallet(true)
+	require.NoError(t, err)
+
+	rewardAddr, err := NewRewardAddress(TestNetNetwork, wallet.StakeVerificationKey)
+	require.NoError(t, err)
+
+	baseAddr, err := NewBaseAddress(TestNetNetwork, wallet.VerificationKey, wallet.StakeVerificationKey)
+	require.NoError(t, err)
+
+	poolKeyHashBytes, err := hex.DecodeString(poolKeyHash)
+	require.NoError(t, err)
+
+	poolID, err := bech32.EncodeFromBase256(poolIDPrefix, poolKeyHashBytes)
+	require.NoError(t, err)
+
+	pp := ProtocolParameters{StakeAddressDeposit: 2_000_000}
+
+	t.Run("stake credential", func(t *testing.T) {
+		t.Parallel()
+
+		expected, err := NewStakeCredentialFromKey(wallet.StakeVerificationKey)
+		require.NoError(t, err)
+
+		for _, addr := range []string{rewardAddr.String(), baseAddr.String()} {
+			credential, err := NewStakeCredentialFromAddress(addr)
+			require.NoError(t, err)
+			require.Equal(t, expected, credential)
+		}
+
+		_, err = NewStakeCredentialFromAddress("addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u")
+		require.ErrorContains(t, err, "does not have stake credential")
+	})
+
+	t.Run("pool key hash", func(t *testing.T) {
+		t.Parallel()
+
+		for _, id := range []string{poolID, poolKeyHash} {
+			keyHash, err := GetPoolKeyHash(id)
+			require.NoError(t, err)
+			require.Equal(t, poolKeyHash, hex.EncodeToString(keyHash[:]))
+		}
+
+		invalidPoolID, err := bech32.EncodeFromBase256(poolIDPrefix+"_test", poolKeyHashBytes)
+		require.NoError(t, err)
+
+		_, err = GetPoolKeyHash(invalidPoolID)
+		require.ErrorContains(t, err, "invalid prefix")
+
+		_, err = GetPoolKeyHash("d6b67f93")
+		require.Error(t, err)
+	})
+
+	t.Run("cbor", func(t *testing.T) {
+		t.Parallel()
+
+		stakeCredential, err := NewStakeCredentialFromAddress(rewardAddr.String())
+		require.NoError(t, err)
+
+		credentialHex := "8200581c" + hex.EncodeToString(stakeCredential.Payload[:])
+		scriptCredentialHex := "8201581c" + hex.EncodeToString(stakeCredential.Payload[:])
+		poolHex := "581c" + poolKeyHash
+
+		registration, err := NewStakeRegistrationCertificate(rewardAddr.String())
+		require.NoError(t, err)
+
+		deregistration, err := NewStakeDeregistrationCertificate(baseAddr.String())
+		require.NoError(t, err)
+
+		delegation, err := NewStakeDelegationCertificate(rewardAddr.String(), poolID)
+		require.NoError(t, err)
+
+		registrationDelegation, err := NewStakeRegistrationDelegationCertificate(rewardAddr.String(), poolKeyHash)
+		require.NoError(t, err)
+
+		cases := []struct {
+			cert    ICertificate
+			cbor    string
+			deposit uint64
+			refund  uint64
+			witness bool
+		}{
+			{*registration, "82" + "00" + credentialHex, 2_000_000, 0, false},
+			{StakeRegistrationCertificate{stakeCredential, 3_000_000}, "83" + "07" + credentialHex + "1a002dc6c0", 3_000_000, 0, true},
+			{*deregistration, "82" + "01" + credentialHex, 0, 2_000_000, true},
+			{StakeDeregistrationCertificate{stakeCredential, 3_000_000}, "83" + "08" + credentialHex + "1a002dc6c0", 0, 3_000_000, true},
+			{*delegation, "83" + "02" + credentialHex + poolHex, 0, 0, true},
+			{StakeDelegationCertificate{CardanoAddressPayload{stakeCredential.Payload, true}, delegation.PoolKeyHash},
+				"83" + "02" + scriptCredentialHex + poolHex, 0, 0, true},
+			{*registrationDelegation, "84" + "0b" + credentialHex + poolHex + "1a001e8480", 2_000_000, 0, true},
+		}
+
+		for _, c := range cases {
+			bytes, err := c.cert.GetCbor(pp)
+			require.NoError(t, err)
+			require.Equal(t, c.cbor, hex.EncodeToString(bytes))
+
+			deposit, refund := c.cert.GetDeposit(pp)
+			require.Equal(t, c.deposit, deposit)
+			require.Equal(t, c.refund, refund)
+			require.Equal(t, c.witness, c.cert.GetWitnessCredential() != nil)
+		}
+	})
+}
+
+func TestTxBuilder_Certificates(t *testing.T) {
+	t.Parallel()
+
+	const (
+		poolKeyHash = "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"
+		utxoAmount  = uint64(10_000_000)
+	)
+
+	wallet, err := GenerateWallet(true)
+	require.NoError(t, err)
+
+	rewardAddr, err := NewRewardAddress(TestNetNetwork, wallet.StakeVerificationKey)
+	require.NoError(t, err)
+
+	baseAddr, err := NewBaseAddress(TestNetNetwork, wallet.VerificationKey, wallet.StakeVerificationKey)
+	require.NoError(t, err)
+
+	utxo := Utxo{
+		Hash:   "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f",
+		Index:  0,
+		Amount: utxoAmount,
+	}
+
+	var pp ProtocolParameters
+
+	require.NoError(t, json.Unmarshal(protocolParameters, &pp))
+
+	type decodedTx struct {
+		_          struct{} `cbor:",toarray"`
+		Body       map[int]cbor.RawMessage
+		WitnessSet map[int]cbor.RawMessage
+		IsValid    bool
+		Aux        cbor.RawMessage
+	}
+
+	build := func(t *testing.T, certificates ...ICertificate) (fee uint64, changeAmount uint64, txRaw []byte) {
+		t.Helper()
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		fee, err = builder.SetProtocolParameters(protocolParameters).
+			AddUtxos(utxo).
+			AddCertificates(certificates...).
+			Balance(baseAddr.String())
+		require.NoError(t, err)
+
+		txRaw, _, err = builder.Build()
+		require.NoError(t, err)
+
+		require.Len(t, builder.outputs, 1)
+
+		return fee, builder.outputs[0].Amount, txRaw
+	}
+
+	t.Run("registration and delegation", func(t *testing.T) {
+		t.Parallel()
+
+		registration, err := NewStakeRegistrationCertificate(rewardAddr.String())
+		require.NoError(t, err)
+
+		delegation, err := NewStakeDelegationCertificate(rewardAddr.String(), poolKeyHash)
+		require.NoError(t, err)
+
+		fee, change, txRaw := build(t, registration, delegation)
+		require.Equal(t, utxoAmount-pp.StakeAddressDeposit-fee, change)
+
+		var (
+			tx           decodedTx
+			certificates []cbor.RawMessage
+		)
+
+		require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+		require.NoError(t, cbor.Unmarshal(tx.Body[4], &certificates))
+		require.Len(t, certificates, 2)
+
+		// payment key and stake key (delegation) witnesses
+		expectedFee, err := CalculateMinFee(txRaw, pp, 2, 0)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, fee, expectedFee)
+		require.LessOrEqual(t, fee, expectedFee+10*44)
+
+		txHash, err := GetTxHash(txRaw)
+		require.NoError(t, err)
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		txSigned, err := builder.SignTx(txRaw, []ITxSigner{wallet, wallet.GetStakeSigner()})
+		require.NoError(t, err)
+
+		signedHash, err := GetTxHash(txSigned)
+		require.NoError(t, err)
+		require.Equal(t, txHash, signedHash)
+
+		var signedTx decodedTx
+
+		require.NoError(t, cbor.Unmarshal(txSigned, &signedTx))
+		require.Contains(t, signedTx.WitnessSet, 0)
+	})
+
+	t.Run("deregistration refund", func(t *testing.T) {
+		t.Parallel()
+
+		deregistration, err := NewStakeDeregistrationCertificate(rewardAddr.String())
+		require.NoError(t, err)
+
+		fee, change, _ := build(t, deregistration)
+		require.Equal(t, utxoAmount+pp.StakeAddressDeposit-fee, change)
+	})
+
+	t.Run("conway registration with delegation", func(t *testing.T) {
+		t.Parallel()
+
+		registrationDelegation, err := NewStakeRegistrationDelegationCertificate(rewardAddr.String(), poolKeyHash)
+		require.NoError(t, err)
+
+		registrationDelegation.Deposit = 3_000_000
+
+		fee, change, _ := build(t, registrationDelegation)
+		require.Equal(t, utxoAmount-3_000_000-fee, change)
+	})
+
+	t.Run("script credential", func(t *testing.T) {
+		t.Parallel()
+
+		policyScript := NewPolicyScript([]string{
+			"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21",
+			"cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41",
+		}, 2)
+
+		policyScriptBytes, err := policyScript.MarshalCBOR()
+		require.NoError(t, err)
+
+		scriptHash, err := policyScript.GetPolicyIDBytes()
+		require.NoError(t, err)
+
+		scriptCredential := CardanoAddressPayload{Payload: [KeyHashSize]byte(scriptHash), IsScript: true}
+		delegation := StakeDelegationCertificate{StakeCredential: scriptCredential}
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		fee, err := builder.SetProtocolParameters(protocolParameters).
+			AddUtxos(utxo).
+			AddCertificateWithScript(policyScript, delegation).
+			Balance(baseAddr.String())
+		require.NoError(t, err)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		var (
+			tx      decodedTx
+			scripts []cbor.RawMessage
+		)
+
+		require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+		require.NoError(t, cbor.Unmarshal(tx.WitnessSet[nativeScriptsKey], &scripts))
+		require.Equal(t, []cbor.RawMessage{policyScriptBytes}, scripts)
+
+		// payment key and policy script signers
+		expectedFee, err := CalculateMinFee(txRaw, pp, 1+policyScript.GetCount(), 0)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, fee, expectedFee)
+		require.LessOrEqual(t, fee, expectedFee+10*44)
+
+		keyDelegation, err := NewStakeDelegationCertificate(rewardAddr.String(), poolKeyHash)
+		require.NoError(t, err)
+
+		otherScript := NewPolicyScript([]string{poolKeyHash}, 1)
+
+		for _, c := range []struct {
+			policyScript IPolicyScript
+			certificate  ICertificate
+			err          string
+		}{
+			{policyScript, keyDelegation, "is not script credential"},
+			{otherScript, delegation, "does not match"},
+		} {
+			builder, err := NewTxBuilder("")
+			require.NoError(t, err)
+
+			_, _, err = builder.SetProtocolParameters(protocolParameters).
+				AddUtxos(utxo).
+				AddCertificateWithScript(c.policyScript, c.certificate).
+				AddOutputs(NewTxOutput(baseAddr.String(), 1_000_000)).SetFee(200_000).Build()
+			require.ErrorContains(t, err, c.err)
+		}
+	})
+
+	t.Run("not enough funds for deposit", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		_, err = builder.SetProtocolParameters(protocolParameters).
+			AddUtxos(Utxo{Hash: utxo.Hash, Amount: 1_500_000}).
+			AddCertificates(StakeRegistrationCertificate{Deposit: 2_000_000}).
+			Balance(baseAddr.String())
+		require.ErrorIs(t, err, ErrBalanceNotEnoughFunds)
+	})
+}
@@ -29,7 +29,9 @@ func NewCliUtils(cardanoCliBinary string) CliUtils {
 	}
 }
 
-// GetPolicyScriptAddress get address for policy script
+// GetPolicyScriptAddress get address for policy script.
+//
+// Deprecated: use PolicyScript.GetAddress which does not need cardano-cli
 func (cu CliUtils) GetPolicyScriptAddress(
 	testNetMagic uint, policyScript *PolicyScript, policyScriptStake ...*PolicyScript,
 ) (string, error) {
@@ -77,7 +79,9 @@ func (cu CliUtils) GetPolicyScriptAddress(
 	return strings.Trim(response, "\n"), nil
 }
 
-// GetPolicyID returns policy id
+// GetPolicyID returns policy id.
+//
+// Deprecated: use PolicyScript.GetPolicyID which does not need cardano-cli
 func (cu CliUtils) GetPolicyID(policyScript any) (string, error) {
 	baseDirectory, err := os.MkdirTemp("", "ps-policy-id")
 	if err != nil {
@@ -0,0 +1,416 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// txInputMaxCborSize is the largest possible size of one serialized input: [bytes .size 32, uint32]
+const txInputMaxCborSize = 1 + 2 + 32 + 5
+
+var ErrCoinSelectionMaxInputs = errors.New("coin selection: maximum number of inputs reached")
+
+// ICoinSelector chooses utxos for the transaction. For lovelace and every token of exactSum and atLeastSum
+// the quantity of chosen utxos must be either equal to the one in exactSum or greater or equal to the one in atLeastSum
+type ICoinSelector interface {
+	SelectCoins(utxos []Utxo, exactSum, atLeastSum Value) ([]Utxo, error)
+}
+
+// GetMaxInputsCount returns how many inputs can be added to the transaction so it stays under MaxTxSize.
+// reservedTxSize is the size of everything else in the transaction (outputs, witnesses, metadata, ...)
+func GetMaxInputsCount(protocolParameters ProtocolParameters, reservedTxSize uint64) int {
+	if protocolParameters.MaxTxSize <= reservedTxSize {
+		return 0
+	}
+
+	return int((protocolParameters.MaxTxSize - reservedTxSize) / txInputMaxCborSize)
+}
+
+type sequentialCoinSelector struct {
+	maxInputs int
+}
+
+var _ ICoinSelector = (*sequentialCoinSelector)(nil)
+
+// NewSequentialCoinSelector creates selector which takes utxos in the order returned by the provider.
+// maxInputs equal to zero means there is no limit
+func NewSequentialCoinSelector(maxInputs int) ICoinSelector {
+	return &sequentialCoinSelector{maxInputs: maxInputs}
+}
+
+func (s *sequentialCoinSelector) SelectCoins(utxos []Utxo, exactSum, atLeastSum Value) ([]Utxo, error) {
+	candidates, err := newCoinSelectionUtxos(utxos)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		assets     = getSelectionAssets(exactSum, atLeastSum)
+		currentSum Value
+		chosen     []Utxo
+	)
+
+	for _, utxo := range candidates {
+		if isMaxInputsReached(len(chosen), s.maxInputs) {
+			return nil, ErrCoinSelectionMaxInputs
+		}
+
+		if currentSum, err = currentSum.Add(utxo.value); err != nil {
+			return nil, err
+		}
+
+		chosen = append(chosen, utxo.Utxo)
+
+		if _, isOk := getNotEnoughAsset(currentSum, assets, exactSum, atLeastSum); isOk {
+			return chosen, nil
+		}
+	}
+
+	return nil, newNotEnoughFundsError(currentSum, assets, exactSum, atLeastSum)
+}
+
+type largestFirstCoinSelector struct {
+	maxInputs   int
+	preferClean bool
+}
+
+var _ ICoinSelector = (*largestFirstCoinSelector)(nil)
+
+// NewLargestFirstCoinSelector creates selector which, token by token, takes utxos
+// with the largest amount of the token first (CIP-2 largest-first).
+// maxInputs equal to zero means there is no limit
+func NewLargestFirstCoinSelector(maxInputs int) ICoinSelector {
+	return &largestFirstCoinSelector{maxInputs: maxInputs}
+}
+
+// NewMultiAssetCoinSelector creates largest-first selector which prefers utxos without tokens
+// that are not requested. Utxos with fewer unrelated tokens are always taken first
+// so pure ada payments do not consume token holding utxos.
+// maxInputs equal to zero means there is no limit
+func NewMultiAssetCoinSelector(maxInputs int) ICoinSelector {
+	return &largestFirstCoinSelector{maxInputs: maxInputs, preferClean: true}
+}
+
+func (s *largestFirstCoinSelector) SelectCoins(utxos []Utxo, exactSum, atLeastSum Value) ([]Utxo, error) {
+	available, err := newCoinSelectionUtxos(utxos)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		assets     = getSelectionAssets(exactSum, atLeastSum)
+		currentSum Value
+		chosen     []Utxo
+	)
+
+	for _, asset := range assets {
+		candidates := append([]coinSelectionUtxo(nil), available...)
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if s.preferClean {
+				unrelatedI := getUnrelatedAssetsCount(candidates[i].value, assets)
+				unrelatedJ := getUnrelatedAssetsCount(candidates[j].value, assets)
+
+				if unrelatedI != unrelatedJ {
+					return unrelatedI < unrelatedJ
+				}
+			}
+
+			return asset.getQuantity(candidates[i].value) > asset.getQuantity(candidates[j].value)
+		})
+
+		for _, utxo := range candidates {
+			if isAssetSumEnough(currentSum, asset, exactSum, atLeastSum) {
+				break
+			}
+
+			if asset.getQuantity(utxo.value) == 0 {
+				continue
+			}
+
+			if isMaxInputsReached(len(chosen), s.maxInputs) {
+				return nil, ErrCoinSelectionMaxInputs
+			}
+
+			if currentSum, err = currentSum.Add(utxo.value); err != nil {
+				return nil, err
+			}
+
+			chosen = append(chosen, utxo.Utxo)
+			available = removeCoinSelectionUtxo(available, utxo)
+		}
+	}
+
+	if _, isOk := getNotEnoughAsset(currentSum, assets, exactSum, atLeastSum); !isOk {
+		return nil, newNotEnoughFundsError(currentSum, assets, exactSum, atLeastSum)
+	}
+
+	return chosen, nil
+}
+
+type randomImproveCoinSelector struct {
+	maxInputs int
+	rnd       *rand.Rand
+}
+
+var _ ICoinSelector = (*randomImproveCoinSelector)(nil)
+
+// NewRandomImproveCoinSelector creates CIP-2 random-improve selector. Utxos are first selected randomly
+// until the target is reached and then the selection is improved towards twice the target
+// (but never more than three times the target) so the change outputs resemble the payments.
+// maxInputs equal to zero means there is no limit. If rnd is nil, new time seeded source is used
+func NewRandomImproveCoinSelector(maxInputs int, rnd *rand.Rand) ICoinSelector {
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec
+	}
+
+	return &randomImproveCoinSelector{maxInputs: maxInputs, rnd: rnd}
+}
+
+func (s *randomImproveCoinSelector) SelectCoins(utxos []Utxo, exactSum, atLeastSum Value) ([]Utxo, error) {
+	candidates, err := newCoinSelectionUtxos(utxos)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		available  = make([]coinSelectionUtxo, len(candidates))
+		assets     = getSelectionAssets(exactSum, atLeastSum)
+		currentSum Value
+		chosen     []Utxo
+	)
+
+	for i, idx := range s.rnd.Perm(len(candidates)) {
+		available[i] = candidates[idx]
+	}
+
+	// phase 1: random selection until every target is reached
+	for _, asset := range assets {
+		for i := 0; i < len(available) && !isAssetSumEnough(currentSum, asset, exactSum, atLeastSum); {
+			if asset.getQuantity(available[i].value) == 0 {
+				i++
+
+				continue
+			}
+
+			if isMaxInputsReached(len(chosen), s.maxInputs) {
+				return nil, ErrCoinSelectionMaxInputs
+			}
+
+			if currentSum, err = currentSum.Add(available[i].value); err != nil {
+				return nil, err
+			}
+
+			chosen = append(chosen, available[i].Utxo)
+			available = append(available[:i], available[i+1:]...)
+		}
+	}
+
+	if _, isOk := getNotEnoughAsset(currentSum, assets, exactSum, atLeastSum); !isOk {
+		return nil, newNotEnoughFundsError(currentSum, assets, exactSum, atLeastSum)
+	}
+
+	// phase 2: improvement (in the reverse order) towards the ideal target (2x) without crossing upper limit (3x).
+	// Only at least targets are improved, exact sums must stay exact
+	for i := len(assets) - 1; i >= 0; i-- {
+		asset := assets[i]
+		target := asset.getQuantity(atLeastSum)
+
+		if target == 0 || asset.getQuantity(currentSum) < target {
+			continue
+		}
+
+		ideal, upper := saturatedMul(target, 2), saturatedMul(target, 3)
+
+		for j := 0; j < len(available); {
+			if isMaxInputsReached(len(chosen), s.maxInputs) {
+				break
+			}
+
+			utxo := available[j]
+			amount := asset.getQuantity(utxo.value)
+			current := asset.getQuantity(currentSum)
+
+			newSum, err := currentSum.Add(utxo.value)
+			if err != nil || amount == 0 {
+				j++
+
+				continue
+			}
+
+			newAmount := asset.getQuantity(newSum)
+
+			if newAmount > upper || absDiff(newAmount, ideal) >= absDiff(current, ideal) {
+				j++
+
+				continue
+			}
+
+			if _, isOk := getNotEnoughAsset(newSum, assets, exactSum, atLeastSum); !isOk {
+				j++
+
+				continue
+			}
+
+			currentSum = newSum
+			chosen = append(chosen, utxo.Utxo)
+			available = append(available[:j], available[j+1:]...)
+		}
+	}
+
+	return chosen, nil
+}
+
+// coinSelectionAsset identifies lovelace (empty policy id) or a native token inside the Value
+type coinSelectionAsset struct {
+	policyID string
+	name     string
+}
+
+func (a coinSelectionAsset) isLovelace() bool {
+	return a.policyID == ""
+}
+
+func (a coinSelectionAsset) getQuantity(value Value) uint64 {
+	if a.isLovelace() {
+		return value.Coin
+	}
+
+	return value.GetAsset(a.policyID, a.name)
+}
+
+func (a coinSelectionAsset) String() string {
+	if a.isLovelace() {
+		return AdaTokenName
+	}
+
+	return NewTokenAmount(a.policyID, a.name, 0).TokenName()
+}
+
+type coinSelectionUtxo struct {
+	Utxo
+	value Value
+}
+
+func newCoinSelectionUtxos(utxos []Utxo) ([]coinSelectionUtxo, error) {
+	result := make([]coinSelectionUtxo, len(utxos))
+
+	for i, utxo := range utxos {
+		value, err := utxo.GetValue()
+		if err != nil {
+			return nil, fmt.Errorf("utxo %s#%d: %w", utxo.Hash, utxo.Index, err)
+		}
+
+		result[i] = coinSelectionUtxo{Utxo: utxo, value: value}
+	}
+
+	return result, nil
+}
+
+// getSelectionAssets returns requested native tokens first and lovelace last,
+// because utxos selected for the tokens already contribute some lovelace
+func getSelectionAssets(exactSum, atLeastSum Value) []coinSelectionAsset {
+	var (
+		result []coinSelectionAsset
+		seen   = map[coinSelectionAsset]bool{}
+	)
+
+	for _, token := range append(exactSum.GetTokens(), atLeastSum.GetTokens()...) {
+		asset := coinSelectionAsset{policyID: token.PolicyID, name: token.Name}
+		if !seen[asset] {
+			seen[asset] = true
+			result = append(result, asset)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].policyID != result[j].policyID {
+			return result[i].policyID < result[j].policyID
+		}
+
+		return result[i].name < result[j].name
+	})
+
+	return append(result, coinSelectionAsset{})
+}
+
+func getUnrelatedAssetsCount(value Value, assets []coinSelectionAsset) int {
+	cnt := 0
+
+	for _, token := range value.GetTokens() {
+		isRelated := false
+
+		for _, asset := range assets {
+			if token.PolicyID == asset.policyID && token.Name == asset.name {
+				isRelated = true
+
+				break
+			}
+		}
+
+		if !isRelated {
+			cnt++
+		}
+	}
+
+	return cnt
+}
+
+func isAssetSumEnough(sum Value, asset coinSelectionAsset, exactSum, atLeastSum Value) bool {
+	current, exact := asset.getQuantity(sum), asset.getQuantity(exactSum)
+
+	return (exact > 0 && current == exact) || current >= asset.getQuantity(atLeastSum)
+}
+
+func getNotEnoughAsset(
+	sum Value, assets []coinSelectionAsset, exactSum, atLeastSum Value,
+) (coinSelectionAsset, bool) {
+	for _, asset := range assets {
+		if !isAssetSumEnough(sum, asset, exactSum, atLeastSum) {
+			return asset, false
+		}
+	}
+
+	return coinSelectionAsset{}, true
+}
+
+func newNotEnoughFundsError(sum Value, assets []coinSelectionAsset, exactSum, atLeastSum Value) error {
+	asset, _ := getNotEnoughAsset(sum, assets, exactSum, atLeastSum)
+
+	return fmt.Errorf("not enough funds for the transaction: %s (available, exact, at least) = (%d, %d, %d)",
+		asset, asset.getQuantity(sum), asset.getQuantity(exactSum), asset.getQuantity(atLeastSum))
+}
+
+func isMaxInputsReached(cnt int, maxInputs int) bool {
+	return maxInputs > 0 && cnt >= maxInputs
+}
+
+func removeCoinSelectionUtxo(utxos []coinSelectionUtxo, utxo coinSelectionUtxo) []coinSelectionUtxo {
+	for i, x := range utxos {
+		if x.Hash == utxo.Hash && x.Index == utxo.Index {
+			return append(utxos[:i], utxos[i+1:]...)
+		}
+	}
+
+	return utxos
+}
+
+func absDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+
+	return b - a
+}
+
+func saturatedMul(a, b uint64) uint64 {
+	if a != 0 && b > math.MaxUint64/a {
+		return math.MaxUint64
+	}
+
+	return a * b
+}
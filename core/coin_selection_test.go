@@ -0,0 +1,163 @@
+package core
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoinSelection(t *testing.T) {
+	t.Parallel()
+
+	const policyID = "29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8"
+
+	var (
+		tokenA  = NewTokenAmount(policyID, "A", 0)
+		tokenB  = NewTokenAmount(policyID, "B", 0)
+		withTkn = func(token TokenAmount, amount uint64) TokenAmount {
+			token.Amount = amount
+
+			return token
+		}
+		utxos = []Utxo{
+			{Hash: "01", Index: 0, Amount: 1_000_000},
+			{Hash: "02", Index: 0, Amount: 5_000_000, Tokens: []TokenAmount{withTkn(tokenB, 10)}},
+			{Hash: "03", Index: 1, Amount: 3_000_000},
+			{Hash: "04", Index: 2, Amount: 2_000_000, Tokens: []TokenAmount{withTkn(tokenA, 100)}},
+			{Hash: "05", Index: 0, Amount: 4_000_000},
+		}
+		getHashes = func(utxos []Utxo) (result []string) {
+			for _, x := range utxos {
+				result = append(result, x.Hash)
+			}
+
+			return result
+		}
+		ada = func(amount uint64) Value {
+			return Value{Coin: amount}
+		}
+	)
+
+	t.Run("sequential", func(t *testing.T) {
+		t.Parallel()
+
+		chosen, err := NewSequentialCoinSelector(0).SelectCoins(
+			utxos, Value{}, ada(6_000_000))
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"01", "02"}, getHashes(chosen))
+
+		chosen, err = NewSequentialCoinSelector(0).SelectCoins(
+			utxos, ada(9_000_000), ada(10_000_000))
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"01", "02", "03"}, getHashes(chosen))
+
+		_, err = NewSequentialCoinSelector(2).SelectCoins(
+			utxos, Value{}, ada(8_000_000))
+
+		require.ErrorIs(t, err, ErrCoinSelectionMaxInputs)
+	})
+
+	t.Run("largest first", func(t *testing.T) {
+		t.Parallel()
+
+		chosen, err := NewLargestFirstCoinSelector(0).SelectCoins(
+			utxos, Value{}, ada(8_000_000))
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"02", "05"}, getHashes(chosen))
+
+		chosen, err = NewLargestFirstCoinSelector(2).SelectCoins(
+			utxos, Value{}, mustNewValue(t, 6_000_000, withTkn(tokenA, 50)))
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"04", "02"}, getHashes(chosen))
+
+		_, err = NewLargestFirstCoinSelector(1).SelectCoins(
+			utxos, Value{}, ada(8_000_000))
+
+		require.ErrorIs(t, err, ErrCoinSelectionMaxInputs)
+
+		_, err = NewLargestFirstCoinSelector(0).SelectCoins(
+			utxos, Value{}, ada(16_000_000))
+
+		require.ErrorContains(t, err, "not enough funds for the transaction")
+	})
+
+	t.Run("multi asset", func(t *testing.T) {
+		t.Parallel()
+
+		chosen, err := NewMultiAssetCoinSelector(0).SelectCoins(
+			utxos, Value{}, ada(8_000_000))
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"05", "03", "01"}, getHashes(chosen))
+
+		chosen, err = NewMultiAssetCoinSelector(0).SelectCoins(
+			utxos, Value{}, mustNewValue(t, 6_000_000, withTkn(tokenA, 50)))
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"04", "05"}, getHashes(chosen))
+	})
+
+	t.Run("random improve", func(t *testing.T) {
+		t.Parallel()
+
+		for seed := int64(0); seed < 20; seed++ {
+			chosen, err := NewRandomImproveCoinSelector(0, rand.New(rand.NewSource(seed))).SelectCoins(
+				utxos, Value{}, ada(4_000_000))
+			require.NoError(t, err)
+
+			sum, err := GetUtxosValue(chosen)
+			require.NoError(t, err)
+
+			assert.GreaterOrEqual(t, sum.Coin, uint64(4_000_000))
+			assert.LessOrEqual(t, sum.Coin, uint64(12_000_000))
+		}
+
+		_, err := NewRandomImproveCoinSelector(0, nil).SelectCoins(
+			utxos, Value{}, mustNewValue(t, 0, withTkn(tokenA, 101)))
+
+		require.ErrorContains(t, err, "not enough funds for the transaction")
+
+		_, err = NewRandomImproveCoinSelector(1, nil).SelectCoins(
+			utxos, Value{}, ada(14_000_000))
+
+		require.ErrorIs(t, err, ErrCoinSelectionMaxInputs)
+	})
+
+	t.Run("not enough funds names the token", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewSequentialCoinSelector(0).SelectCoins(
+			utxos, Value{}, mustNewValue(t, 0, withTkn(tokenB, 11)))
+
+		require.ErrorContains(t, err, "not enough funds for the transaction: "+tokenB.TokenName())
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewSequentialCoinSelector(0).SelectCoins([]Utxo{
+			{Hash: "01", Index: 0, Amount: 1},
+			{Hash: "02", Index: 0, Amount: math.MaxUint64},
+		}, Value{}, ada(math.MaxUint64))
+		require.ErrorIs(t, err, ErrValueOverflow)
+
+		_, err = NewLargestFirstCoinSelector(0).SelectCoins([]Utxo{
+			{Hash: "01", Index: 0, Amount: 1, Tokens: []TokenAmount{withTkn(tokenA, math.MaxUint64), withTkn(tokenA, 1)}},
+		}, Value{}, ada(1))
+		require.ErrorIs(t, err, ErrValueOverflow)
+	})
+
+	t.Run("max inputs count", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, 400, GetMaxInputsCount(ProtocolParameters{MaxTxSize: 16_384}, 384))
+		require.Equal(t, 0, GetMaxInputsCount(ProtocolParameters{MaxTxSize: 16_384}, 16_384))
+	})
+}
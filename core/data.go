@@ -3,8 +3,11 @@ package core
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
@@ -14,10 +17,11 @@ const (
 
 type TokenAmount struct {
 	PolicyID string `json:"pid"`
-	Name     string `json:"nam"` // name must not be hex encoded
+	Name     string `json:"nam"` // raw bytes of the asset name (not hex encoded, not necessarily valid utf-8)
 	Amount   uint64 `json:"val"`
 }
 
+// NewTokenAmount creates token amount. name must be raw bytes of the asset name (not hex encoded)
 func NewTokenAmount(policyID string, name string, amount uint64) TokenAmount {
 	return TokenAmount{
 		PolicyID: policyID,
@@ -26,6 +30,26 @@ func NewTokenAmount(policyID string, name string, amount uint64) TokenAmount {
 	}
 }
 
+// NewTokenAmountWithHexName creates token amount from the hex encoded asset name
+// (as returned by cardano-cli, ogmios and blockfrost)
+func NewTokenAmountWithHexName(policyID string, hexName string, amount uint64) (TokenAmount, error) {
+	name, err := hex.DecodeString(hexName)
+	if err != nil {
+		return TokenAmount{}, fmt.Errorf("invalid hex asset name %s: %w", hexName, err)
+	}
+
+	return NewTokenAmount(policyID, string(name), amount), nil
+}
+
+// NewTokenAmountWithUTF8Name creates token amount from the human readable asset name
+func NewTokenAmountWithUTF8Name(policyID string, name string, amount uint64) (TokenAmount, error) {
+	if !utf8.ValidString(name) {
+		return TokenAmount{}, fmt.Errorf("asset name is not valid utf-8: %s", hex.EncodeToString([]byte(name)))
+	}
+
+	return NewTokenAmount(policyID, name, amount), nil
+}
+
 func NewTokenAmountWithFullName(name string, amount uint64, isNameEncoded bool) (TokenAmount, error) {
 	parts := strings.Split(name, ".")
 	if len(parts) != 2 {
@@ -33,36 +57,106 @@ func NewTokenAmountWithFullName(name string, amount uint64, isNameEncoded bool)
 	}
 
 	if !isNameEncoded {
-		name = parts[1]
-	} else {
-		decodedName, err := hex.DecodeString(parts[1])
-		if err != nil {
-			return TokenAmount{}, fmt.Errorf("invalid full token name: %s", name)
-		}
+		return NewTokenAmount(parts[0], parts[1], amount), nil
+	}
 
-		name = string(decodedName)
+	token, err := NewTokenAmountWithHexName(parts[0], parts[1], amount)
+	if err != nil {
+		return TokenAmount{}, fmt.Errorf("invalid full token name: %s", name)
 	}
 
-	return TokenAmount{
-		PolicyID: parts[0],
-		Name:     name,
-		Amount:   amount,
-	}, nil
+	return token, nil
+}
+
+// HexName returns hex encoded asset name
+func (tt TokenAmount) HexName() string {
+	return hex.EncodeToString([]byte(tt.Name))
+}
+
+// DisplayName returns asset name if it is printable utf-8 string, otherwise hex encoded asset name
+func (tt TokenAmount) DisplayName() string {
+	if !utf8.ValidString(tt.Name) {
+		return tt.HexName()
+	}
+
+	for _, r := range tt.Name {
+		if !unicode.IsPrint(r) {
+			return tt.HexName()
+		}
+	}
+
+	return tt.Name
 }
 
 func (tt TokenAmount) TokenName() string {
-	return fmt.Sprintf("%s.%s", tt.PolicyID, hex.EncodeToString([]byte(tt.Name)))
+	return fmt.Sprintf("%s.%s", tt.PolicyID, tt.HexName())
 }
 
 func (tt TokenAmount) String() string {
-	return fmt.Sprintf("%d %s.%s", tt.Amount, tt.PolicyID, hex.EncodeToString([]byte(tt.Name)))
+	return fmt.Sprintf("%d %s.%s", tt.Amount, tt.PolicyID, tt.HexName())
+}
+
+type tokenAmountJSON struct {
+	PolicyID string  `json:"pid"`
+	Name     *string `json:"nam,omitempty"`
+	HexName  *string `json:"namHex,omitempty"`
+	Amount   uint64  `json:"val"`
+}
+
+// MarshalJSON encodes the asset name as "nam" if it is valid utf-8, otherwise as hex encoded "namHex",
+// because json strings can not hold arbitrary bytes
+func (tt TokenAmount) MarshalJSON() ([]byte, error) {
+	result := tokenAmountJSON{
+		PolicyID: tt.PolicyID,
+		Amount:   tt.Amount,
+	}
+
+	if utf8.ValidString(tt.Name) {
+		result.Name = &tt.Name
+	} else {
+		hexName := tt.HexName()
+		result.HexName = &hexName
+	}
+
+	return json.Marshal(result)
+}
+
+func (tt *TokenAmount) UnmarshalJSON(data []byte) error {
+	var raw tokenAmountJSON
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch {
+	case raw.HexName != nil:
+		token, err := NewTokenAmountWithHexName(raw.PolicyID, *raw.HexName, raw.Amount)
+		if err != nil {
+			return err
+		}
+
+		*tt = token
+	case raw.Name != nil:
+		*tt = NewTokenAmount(raw.PolicyID, *raw.Name, raw.Amount)
+	default:
+		*tt = NewTokenAmount(raw.PolicyID, "", raw.Amount)
+	}
+
+	return nil
 }
 
 type Utxo struct {
-	Hash   string        `json:"hsh"`
-	Index  uint32        `json:"ind"`
-	Amount uint64        `json:"amount"`
-	Tokens []TokenAmount `json:"tokens,omitempty"`
+	Hash  string `json:"hsh"`
+	Index uint32 `json:"ind"`
+	// Value of the utxo. If it is zero, Amount and Tokens are used instead
+	Value Value `json:"value"`
+	// Deprecated: Amount is kept for compatibility, use Value (providers set both)
+	Amount uint64 `json:"amount"`
+	// Deprecated: Tokens are kept for compatibility, use Value (providers set both)
+	Tokens          []TokenAmount    `json:"tokens,omitempty"`
+	DatumHash       string           `json:"datumHash,omitempty"`
+	InlineDatum     []byte           `json:"inlineDatum,omitempty"`
+	ReferenceScript *ReferenceScript `json:"refScript,omitempty"`
 }
 
 type QueryTipData struct {
@@ -94,6 +188,11 @@ type IUTxORetriever interface {
 	GetUtxos(ctx context.Context, addr string) ([]Utxo, error)
 }
 
+type IRewardsRetriever interface {
+	// GetRewardsBalance returns rewards available for the withdrawal from the reward (stake) address
+	GetRewardsBalance(ctx context.Context, rewardAddress string) (uint64, error)
+}
+
 type ITxProvider interface {
 	ITxSubmitter
 	ITxDataRetriever
@@ -101,6 +200,11 @@ type ITxProvider interface {
 	Dispose()
 }
 
+type ITxEvaluator interface {
+	// EvaluateTx executes plutus scripts of the transaction (cbor) and returns execution units of every redeemer
+	EvaluateTx(ctx context.Context, txRaw []byte) (map[RedeemerPointer]ExUnits, error)
+}
+
 type ITxSigner interface {
 	SignTransaction([]byte) ([]byte, error)
 	GetTransactionVerificationKey() []byte
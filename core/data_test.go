@@ -0,0 +1,62 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenAmount_Names(t *testing.T) {
+	t.Parallel()
+
+	const policyID = "29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8"
+
+	token, err := NewTokenAmountWithHexName(policyID, "000de1404e616d69", 1)
+	require.NoError(t, err)
+	require.Equal(t, "\x00\x0d\xe1\x40Nami", token.Name)
+	require.Equal(t, "000de1404e616d69", token.HexName())
+	require.Equal(t, "000de1404e616d69", token.DisplayName())
+
+	_, err = NewTokenAmountWithHexName(policyID, "Route3", 1)
+	require.Error(t, err)
+
+	token, err = NewTokenAmountWithUTF8Name(policyID, "Route3", 1)
+	require.NoError(t, err)
+	require.Equal(t, "526f75746533", token.HexName())
+	require.Equal(t, "Route3", token.DisplayName())
+
+	_, err = NewTokenAmountWithUTF8Name(policyID, "\xe1\x40", 1)
+	require.ErrorContains(t, err, "e140")
+
+	token, err = NewTokenAmountWithHexName(policyID, "0a0b", 1)
+	require.NoError(t, err)
+	require.Equal(t, "0a0b", token.DisplayName()) // valid utf-8 but not printable
+}
+
+func TestTokenAmount_JSON(t *testing.T) {
+	t.Parallel()
+
+	const policyID = "29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8"
+
+	tokens := []TokenAmount{
+		NewTokenAmount(policyID, "Route3", 10),
+		NewTokenAmount(policyID, "\x00\x0d\xe1\x40Nami", 1),
+		NewTokenAmount(policyID, "", 3),
+	}
+
+	bytes, err := json.Marshal(tokens)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"pid":"`+policyID+`","nam":"Route3","val":10},
+		{"pid":"`+policyID+`","namHex":"000de1404e616d69","val":1},
+		{"pid":"`+policyID+`","nam":"","val":3}
+	]`, string(bytes))
+
+	var result []TokenAmount
+
+	require.NoError(t, json.Unmarshal(bytes, &result))
+	require.Equal(t, tokens, result)
+
+	require.Error(t, json.Unmarshal([]byte(`{"pid":"`+policyID+`","namHex":"zz","val":1}`), &result[0]))
+}
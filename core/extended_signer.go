@@ -0,0 +1,96 @@
+package core
+
+import (
+	"crypto/sha512"
+	"fmt"
+
+	"filippo.io/edwards25519"
+)
+
+// ExtendedSigner signs transactions with the BIP32-Ed25519 extended key (kL‖kR) for which the ed25519 seed
+// does not exist (keys derived from the mnemonic, exported by cardano-address or cardano-cli)
+type ExtendedSigner struct {
+	SigningKey      []byte `json:"xskey"`
+	VerificationKey []byte `json:"vkey"`
+}
+
+var _ ITxSigner = (*ExtendedSigner)(nil)
+
+// NewExtendedSigner creates signer from the extended signing key: kL‖kR, kL‖kR‖chainCode (cardano-address)
+// or kL‖kR‖publicKey‖chainCode (cardano-cli)
+func NewExtendedSigner(extendedSigningKey []byte) (*ExtendedSigner, error) {
+	signingKey, err := getExtendedSigningKey(extendedSigningKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExtendedSigner{
+		SigningKey:      signingKey,
+		VerificationKey: GetVerificationKeyFromSigningKey(signingKey),
+	}, nil
+}
+
+func (s ExtendedSigner) SignTransaction(txRaw []byte) ([]byte, error) {
+	return signMessageExtended(s.SigningKey, s.VerificationKey, txRaw)
+}
+
+func (s ExtendedSigner) GetTransactionVerificationKey() []byte {
+	return s.VerificationKey
+}
+
+// getExtendedSigningKey returns kL‖kR part of the extended signing key
+func getExtendedSigningKey(key []byte) ([]byte, error) {
+	switch len(key) {
+	case ExtendedKeySize, ExtendedKeySize + ChainCodeSize, ExtendedKeySize + KeySize + ChainCodeSize:
+		return key[:ExtendedKeySize], nil
+	default:
+		return nil, fmt.Errorf("invalid extended signing key size: %d", len(key))
+	}
+}
+
+// signMessageExtended creates ed25519 signature with the extended key: kL is the scalar
+// and kR is used instead of the seed hash for the nonce
+func signMessageExtended(extendedKey, verificationKey, message []byte) ([]byte, error) {
+	extendedKey, err := getExtendedSigningKey(extendedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	kL, err := getExtendedKeyScalar(extendedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceHash := sha512.New()
+	nonceHash.Write(extendedKey[KeySize:])
+	nonceHash.Write(message)
+
+	r, err := edwards25519.NewScalar().SetUniformBytes(nonceHash.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	rPoint := new(edwards25519.Point).ScalarBaseMult(r).Bytes()
+
+	challengeHash := sha512.New()
+	challengeHash.Write(rPoint)
+	challengeHash.Write(verificationKey)
+	challengeHash.Write(message)
+
+	h, err := edwards25519.NewScalar().SetUniformBytes(challengeHash.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	s := edwards25519.NewScalar().MultiplyAdd(h, kL, r)
+
+	return append(rPoint, s.Bytes()...), nil
+}
+
+// getExtendedKeyScalar returns kL of the extended key reduced modulo the group order
+func getExtendedKeyScalar(extendedKey []byte) (*edwards25519.Scalar, error) {
+	wide := make([]byte, 64)
+	copy(wide, extendedKey[:KeySize])
+
+	return edwards25519.NewScalar().SetUniformBytes(wide)
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"crypto/ed25519"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtendedSigner(t *testing.T) {
+	t.Parallel()
+
+	masterKey, err := NewMasterKeyFromMnemonic(
+		"test walk nut penalty hip pave soap entry language right filter choice", "")
+	require.NoError(t, err)
+
+	hdKey, err := masterKey.DeriveAccountKey(0, RoleExternal, 0)
+	require.NoError(t, err)
+
+	verificationKey := hdKey.GetVerificationKey()
+	message := []byte("transaction hash")
+
+	wallet, err := masterKey.DeriveWallet(0, 0)
+	require.NoError(t, err)
+
+	expectedSignature, err := wallet.SignTransaction(message)
+	require.NoError(t, err)
+
+	// kL‖kR, cardano-address (kL‖kR‖chainCode) and cardano-cli (kL‖kR‖publicKey‖chainCode) formats
+	for _, key := range [][]byte{
+		hdKey.PrivateKey,
+		append(append([]byte{}, hdKey.PrivateKey...), hdKey.ChainCode...),
+		append(append(append([]byte{}, hdKey.PrivateKey...), verificationKey...), hdKey.ChainCode...),
+	} {
+		signer, err := NewExtendedSigner(key)
+		require.NoError(t, err)
+		require.Equal(t, verificationKey, signer.GetTransactionVerificationKey())
+
+		signature, err := signer.SignTransaction(message)
+		require.NoError(t, err)
+		require.Equal(t, expectedSignature, signature)
+		require.True(t, ed25519.Verify(verificationKey, message, signature))
+
+		// extended keys are not truncated anymore
+		require.Equal(t, key, PadKeyToSize(key))
+		require.Equal(t, verificationKey, GetVerificationKeyFromSigningKey(key))
+	}
+
+	_, err = NewExtendedSigner(hdKey.PrivateKey[:KeySize+1])
+	require.Error(t, err)
+
+	t.Run("text envelope", func(t *testing.T) {
+		t.Parallel()
+
+		cliKey := append(append(append([]byte{}, hdKey.PrivateKey...), verificationKey...), hdKey.ChainCode...)
+
+		key, err := NewKeyFromBytes(PaymentExtendedSigningKeyShelley, PaymentSigningKeyShelleyDesc, cliKey)
+		require.NoError(t, err)
+
+		filePath := filepath.Join(t.TempDir(), "payment.skey")
+
+		require.NoError(t, key.WriteToFile(filePath))
+
+		loadedKey, err := NewKey(filePath)
+		require.NoError(t, err)
+
+		keyBytes, err := loadedKey.GetKeyBytes()
+		require.NoError(t, err)
+		require.Equal(t, cliKey, keyBytes)
+
+		signer, err := loadedKey.GetSigner()
+		require.NoError(t, err)
+		require.Equal(t, verificationKey, signer.GetTransactionVerificationKey())
+
+		signature, err := signer.SignTransaction(message)
+		require.NoError(t, err)
+		require.Equal(t, expectedSignature, signature)
+
+		bech32Key, err := loadedKey.Bech32()
+		require.NoError(t, err)
+		require.Regexp(t, "^addr_xsk1", bech32Key)
+
+		bech32KeyBytes, err := GetKeyBytes(bech32Key)
+		require.NoError(t, err)
+		require.Equal(t, cliKey, bech32KeyBytes)
+
+		// regular signing key
+		seed, seedVerificationKey, err := GenerateKeyPair()
+		require.NoError(t, err)
+
+		seedKey, err := NewKeyFromBytes(PaymentSigningKeyShelley, PaymentSigningKeyShelleyDesc, seed)
+		require.NoError(t, err)
+
+		seedSigner, err := seedKey.GetSigner()
+		require.NoError(t, err)
+		require.Equal(t, seedVerificationKey, seedSigner.GetTransactionVerificationKey())
+
+		verificationKeyEnvelope, err := NewKeyFromBytes(
+			PaymentVerificationKeyShelley, PaymentVerificationKeyShelleyDesc, seedVerificationKey)
+		require.NoError(t, err)
+
+		_, err = verificationKeyEnvelope.GetSigner()
+		require.Error(t, err)
+	})
+	t.Run("extended verification key", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := NewKeyFromBytes(PaymentExtendedVerificationKeyShelley, PaymentVerificationKeyShelleyDesc,
+			append(append([]byte{}, verificationKey...), hdKey.ChainCode...))
+		require.NoError(t, err)
+
+		keyBytes, err := key.GetKeyBytes()
+		require.NoError(t, err)
+		require.Len(t, keyBytes, KeySize+ChainCodeSize)
+
+		// wallet with the chain code in the verification key would have wrong key hash and address
+		_, err = NewWalletWithValidation(keyBytes, hdKey.PrivateKey)
+		require.ErrorContains(t, err, "invalid verification key size: 64")
+
+		keyVerificationKey, err := key.GetVerificationKey()
+		require.NoError(t, err)
+		require.Equal(t, verificationKey, keyVerificationKey)
+
+		keyWallet, err := NewWalletWithValidation(keyVerificationKey, hdKey.PrivateKey)
+		require.NoError(t, err)
+		require.Equal(t, NewWallet(keyVerificationKey, hdKey.PrivateKey), keyWallet)
+
+		expectedKeyHash, err := GetKeyHash(wallet.VerificationKey)
+		require.NoError(t, err)
+
+		keyHash, err := GetKeyHash(keyWallet.GetTransactionVerificationKey())
+		require.NoError(t, err)
+		require.Equal(t, expectedKeyHash, keyHash)
+
+		_, err = NewWalletWithValidation(verificationKey, hdKey.PrivateKey[:KeySize+1])
+		require.ErrorContains(t, err, "invalid extended signing key size: 33")
+
+		_, err = NewStakeWalletWithValidation(verificationKey, hdKey.PrivateKey, keyBytes, hdKey.PrivateKey)
+		require.ErrorContains(t, err, "stake key: invalid verification key size: 64")
+
+		signingKey, err := NewKeyFromBytes(PaymentExtendedSigningKeyShelley, PaymentSigningKeyShelleyDesc, hdKey.PrivateKey)
+		require.NoError(t, err)
+
+		_, err = signingKey.GetVerificationKey()
+		require.Error(t, err)
+	})
+}
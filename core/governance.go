@@ -0,0 +1,230 @@
+package core
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	AnchorDataHashSize = 32
+	maxAnchorURLLength = 128
+)
+
+// conway governance certificate tags as defined in the ledger cddl
+const (
+	certVoteDelegationTag   = 9
+	certDRepRegistrationTag = 16
+	certDRepRetirementTag   = 17
+	certDRepUpdateTag       = 18
+)
+
+type DRepType uint64
+
+const (
+	DRepKeyHash            DRepType = 0
+	DRepScriptHash         DRepType = 1
+	DRepAlwaysAbstain      DRepType = 2
+	DRepAlwaysNoConfidence DRepType = 3
+)
+
+// DRep is delegated representative to which the voting power of the stake is delegated
+type DRep struct {
+	Type DRepType
+	Hash [KeyHashSize]byte // not used for always abstain and always no confidence
+}
+
+// NewDRep creates DRep from the key hash or script hash credential
+func NewDRep(credential CardanoAddressPayload) DRep {
+	if credential.IsScript {
+		return DRep{Type: DRepScriptHash, Hash: credential.Payload}
+	}
+
+	return DRep{Type: DRepKeyHash, Hash: credential.Payload}
+}
+
+func NewAlwaysAbstainDRep() DRep {
+	return DRep{Type: DRepAlwaysAbstain}
+}
+
+func NewAlwaysNoConfidenceDRep() DRep {
+	return DRep{Type: DRepAlwaysNoConfidence}
+}
+
+// MarshalCBOR serializes drep from the ledger cddl: [0, addr_keyhash] / [1, script_hash] / [2] / [3]
+func (d DRep) MarshalCBOR() ([]byte, error) {
+	switch d.Type {
+	case DRepKeyHash, DRepScriptHash:
+		return cbor.Marshal([]interface{}{d.Type, d.Hash[:]})
+	case DRepAlwaysAbstain, DRepAlwaysNoConfidence:
+		return cbor.Marshal([]interface{}{d.Type})
+	default:
+		return nil, fmt.Errorf("invalid drep type: %d", d.Type)
+	}
+}
+
+// NewDRepCredentialFromKey returns DRep credential of the DRep verification key
+func NewDRepCredentialFromKey(drepVerificationKey []byte) (CardanoAddressPayload, error) {
+	return NewStakeCredentialFromKey(drepVerificationKey)
+}
+
+// Anchor points to the off chain metadata (of the DRep, vote or proposal) and contains its hash
+type Anchor struct {
+	URL      string `json:"url"`
+	DataHash string `json:"dataHash"`
+}
+
+// NewAnchor creates anchor with blake2b-256 hash of the metadata available on the url
+func NewAnchor(url string, data []byte) Anchor {
+	dataHash := blake2b.Sum256(data)
+
+	return Anchor{
+		URL:      url,
+		DataHash: hex.EncodeToString(dataHash[:]),
+	}
+}
+
+// MarshalCBOR serializes anchor from the ledger cddl: [anchor_url, anchor_data_hash]
+func (a Anchor) MarshalCBOR() ([]byte, error) {
+	if len(a.URL) > maxAnchorURLLength {
+		return nil, fmt.Errorf("anchor url is longer than %d", maxAnchorURLLength)
+	}
+
+	dataHash, err := hex.DecodeString(a.DataHash)
+	if err != nil || len(dataHash) != AnchorDataHashSize {
+		return nil, fmt.Errorf("invalid anchor data hash: %s", a.DataHash)
+	}
+
+	return cbor.Marshal([]interface{}{a.URL, dataHash})
+}
+
+// GovActionID identifies governance action by the transaction which proposed it and the index of the proposal
+type GovActionID struct {
+	TxHash string `json:"txHash"`
+	Index  uint32 `json:"index"`
+}
+
+func NewGovActionID(txHash string, index uint32) GovActionID {
+	return GovActionID{
+		TxHash: txHash,
+		Index:  index,
+	}
+}
+
+// MarshalCBOR serializes gov_action_id from the ledger cddl: [transaction_id, gov_action_index]
+func (id GovActionID) MarshalCBOR() ([]byte, error) {
+	txHash, err := hex.DecodeString(id.TxHash)
+	if err != nil || len(txHash) != 32 {
+		return nil, fmt.Errorf("invalid governance action transaction hash: %s", id.TxHash)
+	}
+
+	return cbor.Marshal([]interface{}{txHash, id.Index})
+}
+
+// VoteDelegationCertificate delegates voting power of the stake credential to the DRep
+type VoteDelegationCertificate struct {
+	StakeCredential CardanoAddressPayload
+	DRep            DRep
+}
+
+// DRepRegistrationCertificate registers DRep. Zero Deposit is replaced with the DRep deposit
+// from the protocol parameters
+type DRepRegistrationCertificate struct {
+	DRepCredential CardanoAddressPayload
+	Deposit        uint64
+	Anchor         *Anchor
+}
+
+// DRepUpdateCertificate updates metadata anchor of the DRep
+type DRepUpdateCertificate struct {
+	DRepCredential CardanoAddressPayload
+	Anchor         *Anchor
+}
+
+// DRepRetirementCertificate retires DRep. Refund must be equal to the deposit paid on the registration,
+// zero Refund is replaced with the DRep deposit from the protocol parameters
+type DRepRetirementCertificate struct {
+	DRepCredential CardanoAddressPayload
+	Refund         uint64
+}
+
+var (
+	_ ICertificate = VoteDelegationCertificate{}
+	_ ICertificate = DRepRegistrationCertificate{}
+	_ ICertificate = DRepUpdateCertificate{}
+	_ ICertificate = DRepRetirementCertificate{}
+)
+
+// NewVoteDelegationCertificate creates certificate which delegates votes of the stake part of the address to the drep
+func NewVoteDelegationCertificate(stakeAddr string, drep DRep) (*VoteDelegationCertificate, error) {
+	stakeCredential, err := NewStakeCredentialFromAddress(stakeAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VoteDelegationCertificate{StakeCredential: stakeCredential, DRep: drep}, nil
+}
+
+func (c VoteDelegationCertificate) GetCbor(_ ProtocolParameters) ([]byte, error) {
+	return cbor.Marshal([]interface{}{certVoteDelegationTag, newTxRawCredential(c.StakeCredential), c.DRep})
+}
+
+func (c VoteDelegationCertificate) GetDeposit(_ ProtocolParameters) (uint64, uint64) {
+	return 0, 0
+}
+
+func (c VoteDelegationCertificate) GetWitnessCredential() *CardanoAddressPayload {
+	return &c.StakeCredential
+}
+
+func (c DRepRegistrationCertificate) GetCbor(protocolParameters ProtocolParameters) ([]byte, error) {
+	deposit, _ := c.GetDeposit(protocolParameters)
+
+	return cbor.Marshal([]interface{}{
+		certDRepRegistrationTag, newTxRawCredential(c.DRepCredential), deposit, c.Anchor,
+	})
+}
+
+func (c DRepRegistrationCertificate) GetDeposit(protocolParameters ProtocolParameters) (uint64, uint64) {
+	if c.Deposit == 0 {
+		return protocolParameters.DRepDeposit, 0
+	}
+
+	return c.Deposit, 0
+}
+
+func (c DRepRegistrationCertificate) GetWitnessCredential() *CardanoAddressPayload {
+	return &c.DRepCredential
+}
+
+func (c DRepUpdateCertificate) GetCbor(_ ProtocolParameters) ([]byte, error) {
+	return cbor.Marshal([]interface{}{certDRepUpdateTag, newTxRawCredential(c.DRepCredential), c.Anchor})
+}
+
+func (c DRepUpdateCertificate) GetDeposit(_ ProtocolParameters) (uint64, uint64) {
+	return 0, 0
+}
+
+func (c DRepUpdateCertificate) GetWitnessCredential() *CardanoAddressPayload {
+	return &c.DRepCredential
+}
+
+func (c DRepRetirementCertificate) GetCbor(protocolParameters ProtocolParameters) ([]byte, error) {
+	_, refund := c.GetDeposit(protocolParameters)
+
+	return cbor.Marshal([]interface{}{certDRepRetirementTag, newTxRawCredential(c.DRepCredential), refund})
+}
+
+func (c DRepRetirementCertificate) GetDeposit(protocolParameters ProtocolParameters) (uint64, uint64) {
+	if c.Refund == 0 {
+		return 0, protocolParameters.DRepDeposit
+	}
+
+	return 0, c.Refund
+}
+
+func (c DRepRetirementCertificate) GetWitnessCredential() *CardanoAddressPayload {
+	return &c.DRepCredential
+}
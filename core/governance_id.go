@@ -0,0 +1,238 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/igorcrevar/go-cardano-tx/core/bech32"
+)
+
+// bech32 prefixes of the governance identifiers (CIP-129) and keys (CIP-105)
+const (
+	DRepIDPrefix          = "drep"
+	DRepScriptIDPrefix    = "drep_script" // CIP-105, deprecated by CIP-129
+	DRepKeyHashIDPrefix   = "drep_vkh"    // CIP-105, deprecated by CIP-129
+	CommitteeHotIDPrefix  = "cc_hot"
+	CommitteeColdIDPrefix = "cc_cold"
+	GovActionIDPrefix     = "gov_action"
+
+	CommitteeHotKeyHashIDPrefix  = "cc_hot_vkh"     // CIP-105, deprecated by CIP-129
+	CommitteeHotScriptIDPrefix   = "cc_hot_script"  // CIP-105, deprecated by CIP-129
+	CommitteeColdKeyHashIDPrefix = "cc_cold_vkh"    // CIP-105, deprecated by CIP-129
+	CommitteeColdScriptIDPrefix  = "cc_cold_script" // CIP-105, deprecated by CIP-129
+
+	DRepVerificationKeyPrefix          = "drep_vk"
+	DRepSigningKeyPrefix               = "drep_sk"
+	CommitteeHotVerificationKeyPrefix  = "cc_hot_vk"
+	CommitteeHotSigningKeyPrefix       = "cc_hot_sk"
+	CommitteeColdVerificationKeyPrefix = "cc_cold_vk"
+	CommitteeColdSigningKeyPrefix      = "cc_cold_sk"
+)
+
+// GovernanceKeyType is type of the governance credential (upper 4 bits of the CIP-129 header byte)
+type GovernanceKeyType byte
+
+const (
+	GovernanceKeyCommitteeHot  GovernanceKeyType = 0x0
+	GovernanceKeyCommitteeCold GovernanceKeyType = 0x1
+	GovernanceKeyDRep          GovernanceKeyType = 0x2
+)
+
+// credential type (lower 4 bits of the CIP-129 header byte)
+const (
+	govCredentialKeyHash    = 0x2
+	govCredentialScriptHash = 0x3
+)
+
+var ErrInvalidGovernanceID = errors.New("invalid governance identifier")
+
+type cip105Credential struct {
+	keyType  GovernanceKeyType
+	isScript bool
+}
+
+// cip105Prefixes maps CIP-105 prefixes to the credentials. CIP-105 identifiers contain only the hash
+var cip105Prefixes = map[string]cip105Credential{
+	DRepIDPrefix:                 {GovernanceKeyDRep, false},
+	DRepKeyHashIDPrefix:          {GovernanceKeyDRep, false},
+	DRepScriptIDPrefix:           {GovernanceKeyDRep, true},
+	CommitteeHotKeyHashIDPrefix:  {GovernanceKeyCommitteeHot, false},
+	CommitteeHotScriptIDPrefix:   {GovernanceKeyCommitteeHot, true},
+	CommitteeColdKeyHashIDPrefix: {GovernanceKeyCommitteeCold, false},
+	CommitteeColdScriptIDPrefix:  {GovernanceKeyCommitteeCold, true},
+}
+
+// GovernanceCredential is DRep or constitutional committee credential
+type GovernanceCredential struct {
+	KeyType    GovernanceKeyType
+	Credential CardanoAddressPayload
+}
+
+func NewGovernanceCredential(keyType GovernanceKeyType, credential CardanoAddressPayload) GovernanceCredential {
+	return GovernanceCredential{
+		KeyType:    keyType,
+		Credential: credential,
+	}
+}
+
+// NewGovernanceCredentialFromBech32 decodes CIP-129 identifier (drep1..., cc_hot1..., cc_cold1...).
+// CIP-105 identifiers without the header byte (drep, drep_vkh, drep_script, cc_hot_vkh, ...) are also accepted
+func NewGovernanceCredentialFromBech32(id string) (GovernanceCredential, error) {
+	prefix, data, err := bech32.DecodeToBase256(id)
+	if err != nil {
+		return GovernanceCredential{}, fmt.Errorf("%w: %s: %w", ErrInvalidGovernanceID, id, err)
+	}
+
+	if credential, exists := cip105Prefixes[prefix]; exists && len(data) == KeyHashSize {
+		return NewGovernanceCredential(credential.keyType, CardanoAddressPayload{
+			Payload:  [KeyHashSize]byte(data),
+			IsScript: credential.isScript,
+		}), nil
+	}
+
+	if len(data) != KeyHashSize+1 {
+		return GovernanceCredential{}, fmt.Errorf("%w: %s: invalid length %d", ErrInvalidGovernanceID, id, len(data))
+	}
+
+	keyType, credentialType := GovernanceKeyType(data[0]>>4), data[0]&0x0f
+	if credentialType != govCredentialKeyHash && credentialType != govCredentialScriptHash {
+		return GovernanceCredential{}, fmt.Errorf("%w: %s: invalid header %x", ErrInvalidGovernanceID, id, data[0])
+	}
+
+	if expectedPrefix, _ := keyType.getPrefix(); expectedPrefix != prefix {
+		return GovernanceCredential{}, fmt.Errorf("%w: %s: prefix %s does not match header %x",
+			ErrInvalidGovernanceID, id, prefix, data[0])
+	}
+
+	return NewGovernanceCredential(keyType, CardanoAddressPayload{
+		Payload:  [KeyHashSize]byte(data[1:]),
+		IsScript: credentialType == govCredentialScriptHash,
+	}), nil
+}
+
+// Bech32 returns CIP-129 identifier of the credential
+func (gc GovernanceCredential) Bech32() (string, error) {
+	prefix, err := gc.KeyType.getPrefix()
+	if err != nil {
+		return "", err
+	}
+
+	header := byte(gc.KeyType)<<4 | govCredentialKeyHash
+	if gc.Credential.IsScript {
+		header = byte(gc.KeyType)<<4 | govCredentialScriptHash
+	}
+
+	return bech32.EncodeFromBase256(prefix, append([]byte{header}, gc.Credential.Payload[:]...))
+}
+
+// Bech32CIP105 returns CIP-105 identifier of the credential which contains only the hash
+// (drep, drep_script, cc_hot_vkh, cc_hot_script, cc_cold_vkh or cc_cold_script).
+// DRep key hash uses legacy drep prefix as cardano-cli before CIP-129
+func (gc GovernanceCredential) Bech32CIP105() (string, error) {
+	var prefix string
+
+	switch gc.KeyType {
+	case GovernanceKeyDRep:
+		prefix = DRepIDPrefix
+		if gc.Credential.IsScript {
+			prefix = DRepScriptIDPrefix
+		}
+	case GovernanceKeyCommitteeHot:
+		prefix = CommitteeHotKeyHashIDPrefix
+		if gc.Credential.IsScript {
+			prefix = CommitteeHotScriptIDPrefix
+		}
+	case GovernanceKeyCommitteeCold:
+		prefix = CommitteeColdKeyHashIDPrefix
+		if gc.Credential.IsScript {
+			prefix = CommitteeColdScriptIDPrefix
+		}
+	default:
+		return "", fmt.Errorf("%w: unknown key type %d", ErrInvalidGovernanceID, gc.KeyType)
+	}
+
+	return bech32.EncodeFromBase256(prefix, gc.Credential.Payload[:])
+}
+
+// GetVoter returns voter of the DRep or constitutional committee hot credential
+func (gc GovernanceCredential) GetVoter() (Voter, error) {
+	switch gc.KeyType {
+	case GovernanceKeyDRep:
+		return NewDRepVoter(gc.Credential), nil
+	case GovernanceKeyCommitteeHot:
+		return NewCommitteeVoter(gc.Credential), nil
+	default:
+		return Voter{}, fmt.Errorf("governance key type %d can not vote", gc.KeyType)
+	}
+}
+
+func (kt GovernanceKeyType) getPrefix() (string, error) {
+	switch kt {
+	case GovernanceKeyCommitteeHot:
+		return CommitteeHotIDPrefix, nil
+	case GovernanceKeyCommitteeCold:
+		return CommitteeColdIDPrefix, nil
+	case GovernanceKeyDRep:
+		return DRepIDPrefix, nil
+	default:
+		return "", fmt.Errorf("%w: unknown key type %d", ErrInvalidGovernanceID, kt)
+	}
+}
+
+// GetDRepID returns CIP-129 DRep identifier (drep1...) of the DRep verification key
+func GetDRepID(drepVerificationKey []byte) (string, error) {
+	credential, err := NewDRepCredentialFromKey(drepVerificationKey)
+	if err != nil {
+		return "", err
+	}
+
+	return NewGovernanceCredential(GovernanceKeyDRep, credential).Bech32()
+}
+
+// NewDRepFromBech32 creates DRep from the DRep identifier (drep1..., drep_script1...)
+func NewDRepFromBech32(id string) (DRep, error) {
+	gc, err := NewGovernanceCredentialFromBech32(id)
+	if err != nil {
+		return DRep{}, err
+	} else if gc.KeyType != GovernanceKeyDRep {
+		return DRep{}, fmt.Errorf("%w: %s is not drep identifier", ErrInvalidGovernanceID, id)
+	}
+
+	return NewDRep(gc.Credential), nil
+}
+
+// Bech32 returns CIP-129 identifier of the governance action: transaction hash followed by the index
+func (id GovActionID) Bech32() (string, error) {
+	txHash, err := hex.DecodeString(id.TxHash)
+	if err != nil || len(txHash) != 32 {
+		return "", fmt.Errorf("invalid governance action transaction hash: %s", id.TxHash)
+	}
+
+	// index is serialized as big endian with the minimal number of bytes (at least one)
+	var index []byte
+	for value := id.Index; value > 0 || len(index) == 0; value >>= 8 {
+		index = append([]byte{byte(value)}, index...)
+	}
+
+	return bech32.EncodeFromBase256(GovActionIDPrefix, append(txHash, index...))
+}
+
+// NewGovActionIDFromBech32 decodes CIP-129 governance action identifier (gov_action1...)
+func NewGovActionIDFromBech32(id string) (GovActionID, error) {
+	prefix, data, err := bech32.DecodeToBase256(id)
+	if err != nil {
+		return GovActionID{}, fmt.Errorf("%w: %s: %w", ErrInvalidGovernanceID, id, err)
+	} else if prefix != GovActionIDPrefix {
+		return GovActionID{}, fmt.Errorf("%w: %s: invalid prefix %s", ErrInvalidGovernanceID, id, prefix)
+	} else if len(data) <= 32 || len(data) > 32+4 {
+		return GovActionID{}, fmt.Errorf("%w: %s: invalid length %d", ErrInvalidGovernanceID, id, len(data))
+	}
+
+	index := uint32(0)
+	for _, b := range data[32:] {
+		index = index<<8 | uint32(b)
+	}
+
+	return NewGovActionID(hex.EncodeToString(data[:32]), index), nil
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/igorcrevar/go-cardano-tx/core/bech32"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGovernanceCredential_Bech32(t *testing.T) {
+	t.Parallel()
+
+	const keyHash = "00000000000000000000000000000000000000000000000000000000"
+
+	keyHashBytes, err := hex.DecodeString(keyHash)
+	require.NoError(t, err)
+
+	payload := [KeyHashSize]byte(keyHashBytes)
+
+	// CIP-129 test vectors and the same credentials as CIP-105 identifiers
+	cases := []struct {
+		keyType  GovernanceKeyType
+		isScript bool
+		id       string
+		cip105ID string
+	}{
+		{GovernanceKeyDRep, false, "drep1ygqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq7vlc9n",
+			"drep1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqua9udh"},
+		{GovernanceKeyDRep, true, "drep1yvqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq770f95",
+			"drep_script1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqnlc53k"},
+		{GovernanceKeyCommitteeHot, false, "cc_hot1qgqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqvcdjk7",
+			"cc_hot_vkh1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq6eup7p"},
+		{GovernanceKeyCommitteeHot, true, "cc_hot1qvqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqv2arke",
+			"cc_hot_script1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqfgjar8"},
+		{GovernanceKeyCommitteeCold, false, "cc_cold1zgqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq6yewvh",
+			"cc_cold_vkh1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq935dga"},
+		{GovernanceKeyCommitteeCold, true, "cc_cold1zvqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq6kflvs",
+			"cc_cold_script1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqdk0zpv"},
+	}
+
+	for _, c := range cases {
+		gc := NewGovernanceCredential(c.keyType, CardanoAddressPayload{Payload: payload, IsScript: c.isScript})
+
+		id, err := gc.Bech32()
+		require.NoError(t, err)
+		require.Equal(t, c.id, id)
+
+		decoded, err := NewGovernanceCredentialFromBech32(id)
+		require.NoError(t, err)
+		require.Equal(t, gc, decoded)
+
+		cip105ID, err := gc.Bech32CIP105()
+		require.NoError(t, err)
+		require.Equal(t, c.cip105ID, cip105ID)
+
+		decoded, err = NewGovernanceCredentialFromBech32(cip105ID)
+		require.NoError(t, err)
+		require.Equal(t, gc, decoded)
+
+		voter, err := decoded.GetVoter()
+		if c.keyType == GovernanceKeyCommitteeCold {
+			require.Error(t, err)
+		} else {
+			require.NoError(t, err)
+			require.Equal(t, payload, voter.Hash)
+			require.Equal(t, c.isScript, voter.IsScript())
+		}
+	}
+
+	// CIP-105 identifiers
+	for prefix, isScript := range map[string]bool{DRepIDPrefix: false, DRepKeyHashIDPrefix: false, DRepScriptIDPrefix: true} {
+		id, err := bech32.EncodeFromBase256(prefix, keyHashBytes)
+		require.NoError(t, err)
+
+		drep, err := NewDRepFromBech32(id)
+		require.NoError(t, err)
+		require.Equal(t, NewDRep(CardanoAddressPayload{Payload: payload, IsScript: isScript}), drep)
+	}
+
+	// DRep ID printed by cardano-cli before CIP-129
+	const drepID = "drep15k6929drl7xt0spvudgcxndryn4kmlzpk4meed0xhqe25nle07s"
+
+	gc, err := NewGovernanceCredentialFromBech32(drepID)
+	require.NoError(t, err)
+	require.Equal(t, "a5b45515a3ff8cb7c02ce351834da324eb6dfc41b5779cb5e6b832aa", hex.EncodeToString(gc.Credential.Payload[:]))
+
+	id, err := gc.Bech32CIP105()
+	require.NoError(t, err)
+	require.Equal(t, drepID, id)
+
+	// header does not match the prefix
+	invalidID, err := bech32.EncodeFromBase256(DRepIDPrefix, append([]byte{0x02}, keyHashBytes...))
+	require.NoError(t, err)
+
+	_, err = NewGovernanceCredentialFromBech32(invalidID)
+	require.ErrorIs(t, err, ErrInvalidGovernanceID)
+
+	_, err = NewDRepFromBech32(cases[2].id)
+	require.ErrorContains(t, err, "is not drep identifier")
+}
+
+func TestGovActionID_Bech32(t *testing.T) {
+	t.Parallel()
+
+	const txHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+	// CIP-129 test vector
+	id, err := NewGovActionID(txHash, 17).Bech32()
+	require.NoError(t, err)
+	require.Equal(t, "gov_action1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqpzklpgpf", id)
+
+	for _, index := range []uint32{0, 17, 255, 256, 70_000} {
+		actionID := NewGovActionID("e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f", index)
+
+		id, err := actionID.Bech32()
+		require.NoError(t, err)
+
+		decoded, err := NewGovActionIDFromBech32(id)
+		require.NoError(t, err)
+		require.Equal(t, actionID, decoded)
+	}
+
+	_, err = NewGovActionIDFromBech32("drep1ygqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq7vlc9n")
+	require.ErrorIs(t, err, ErrInvalidGovernanceID)
+}
+
+func TestKey_Bech32(t *testing.T) {
+	t.Parallel()
+
+	wallet, err := GenerateWallet(false)
+	require.NoError(t, err)
+
+	for _, keyType := range []string{DRepVerificationKey, CommitteeHotVerificationKey, PaymentVerificationKeyShelley} {
+		key, err := NewKeyFromBytes(keyType, "", wallet.VerificationKey)
+		require.NoError(t, err)
+
+		bech32Key, err := key.Bech32()
+		require.NoError(t, err)
+		require.Equal(t, keyBech32Prefixes[keyType], bech32Key[:len(keyBech32Prefixes[keyType])])
+
+		keyBytes, err := GetKeyBytes(bech32Key)
+		require.NoError(t, err)
+		require.Equal(t, wallet.VerificationKey, keyBytes)
+	}
+
+	drepID, err := GetDRepID(wallet.VerificationKey)
+	require.NoError(t, err)
+
+	drep, err := NewDRepFromBech32(drepID)
+	require.NoError(t, err)
+
+	keyHash, err := GetKeyHashBytes(wallet.VerificationKey)
+	require.NoError(t, err)
+	require.Equal(t, DRep{Type: DRepKeyHash, Hash: [KeyHashSize]byte(keyHash)}, drep)
+
+	_, err = Key{Type: "unknown"}.Bech32()
+	require.Error(t, err)
+}
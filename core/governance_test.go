@@ -0,0 +1,325 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGovernanceCertificates(t *testing.T) {
+	t.Parallel()
+
+	const (
+		keyHash    = "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"
+		anchorURL  = "https://example.com/drep.json"
+		anchorData = `{"name":"drep"}`
+	)
+
+	keyHashBytes, err := hex.DecodeString(keyHash)
+	require.NoError(t, err)
+
+	credential := CardanoAddressPayload{Payload: [KeyHashSize]byte(keyHashBytes)}
+	scriptCredential := CardanoAddressPayload{Payload: [KeyHashSize]byte(keyHashBytes), IsScript: true}
+	anchor := NewAnchor(anchorURL, []byte(anchorData))
+	pp := ProtocolParameters{DRepDeposit: 500_000_000}
+
+	credentialHex := "8200581c" + keyHash
+	anchorHex := "82" + "78" + hex.EncodeToString([]byte{byte(len(anchorURL))}) + hex.EncodeToString([]byte(anchorURL)) +
+		"5820" + anchor.DataHash
+
+	cases := []struct {
+		name    string
+		cert    ICertificate
+		cbor    string
+		deposit uint64
+		refund  uint64
+	}{
+		{
+			"vote delegation to drep", VoteDelegationCertificate{credential, NewDRep(credential)},
+			"83" + "09" + credentialHex + "8200581c" + keyHash, 0, 0,
+		},
+		{
+			"vote delegation to script drep", VoteDelegationCertificate{credential, NewDRep(scriptCredential)},
+			"83" + "09" + credentialHex + "8201581c" + keyHash, 0, 0,
+		},
+		{
+			"vote delegation to always abstain", VoteDelegationCertificate{credential, NewAlwaysAbstainDRep()},
+			"83" + "09" + credentialHex + "8102", 0, 0,
+		},
+		{
+			"vote delegation to always no confidence", VoteDelegationCertificate{credential, NewAlwaysNoConfidenceDRep()},
+			"83" + "09" + credentialHex + "8103", 0, 0,
+		},
+		{
+			"drep registration", DRepRegistrationCertificate{DRepCredential: credential, Anchor: &anchor},
+			"84" + "10" + credentialHex + "1a1dcd6500" + anchorHex, 500_000_000, 0,
+		},
+		{
+			"drep registration without anchor", DRepRegistrationCertificate{DRepCredential: credential, Deposit: 1_000_000},
+			"84" + "10" + credentialHex + "1a000f4240" + "f6", 1_000_000, 0,
+		},
+		{
+			"drep update", DRepUpdateCertificate{DRepCredential: credential, Anchor: &anchor},
+			"83" + "12" + credentialHex + anchorHex, 0, 0,
+		},
+		{
+			"drep retirement", DRepRetirementCertificate{DRepCredential: credential},
+			"83" + "11" + credentialHex + "1a1dcd6500", 0, 500_000_000,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			bytes, err := c.cert.GetCbor(pp)
+			require.NoError(t, err)
+			require.Equal(t, c.cbor, hex.EncodeToString(bytes))
+
+			deposit, refund := c.cert.GetDeposit(pp)
+			require.Equal(t, c.deposit, deposit)
+			require.Equal(t, c.refund, refund)
+			require.NotNil(t, c.cert.GetWitnessCredential())
+		})
+	}
+
+	t.Run("invalid anchor", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := DRepUpdateCertificate{credential, &Anchor{URL: anchorURL, DataHash: "0102"}}.GetCbor(pp)
+		require.ErrorContains(t, err, "invalid anchor data hash")
+
+		longAnchor := NewAnchor("https://"+strings.Repeat("a", maxAnchorURLLength), nil)
+
+		_, err = DRepUpdateCertificate{credential, &longAnchor}.GetCbor(pp)
+		require.ErrorContains(t, err, "anchor url is longer")
+
+		_, err = VoteDelegationCertificate{credential, DRep{Type: 4}}.GetCbor(pp)
+		require.ErrorContains(t, err, "invalid drep type")
+	})
+}
+
+func TestTxBuilder_Governance(t *testing.T) {
+	t.Parallel()
+
+	const (
+		addr       = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		utxoAmount = uint64(200_000_000)
+		actionTx1  = "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"
+		actionTx2  = "098236134e0f2077a6434dd9d7727126fa8b3627bcab3ae030a194d46eded73e"
+		keyHash    = "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"
+	)
+
+	wallet, err := GenerateWallet(true)
+	require.NoError(t, err)
+
+	rewardAddr, err := NewRewardAddress(TestNetNetwork, wallet.StakeVerificationKey)
+	require.NoError(t, err)
+
+	drepWallet, err := GenerateWallet(false)
+	require.NoError(t, err)
+
+	drepCredential, err := NewDRepCredentialFromKey(drepWallet.VerificationKey)
+	require.NoError(t, err)
+
+	var pp ProtocolParameters
+
+	require.NoError(t, json.Unmarshal(protocolParameters, &pp))
+
+	pp.DRepDeposit = 5_000_000
+	pp.GovActionDeposit = 100_000_000
+
+	ppBytes, err := json.Marshal(pp)
+	require.NoError(t, err)
+
+	anchor := NewAnchor("https://example.com/rationale.json", []byte("rationale"))
+	utxo := Utxo{Hash: actionTx1, Index: 5, Amount: utxoAmount}
+
+	type decodedTx struct {
+		_          struct{} `cbor:",toarray"`
+		Body       map[int]cbor.RawMessage
+		WitnessSet map[int]cbor.RawMessage
+		IsValid    bool
+		Aux        cbor.RawMessage
+	}
+
+	newBuilder := func(t *testing.T) *TxBuilder {
+		t.Helper()
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		return builder.SetProtocolParameters(ppBytes).AddUtxos(utxo)
+	}
+
+	t.Run("drep registration and vote delegation", func(t *testing.T) {
+		t.Parallel()
+
+		voteDelegation, err := NewVoteDelegationCertificate(rewardAddr.String(), NewDRep(drepCredential))
+		require.NoError(t, err)
+
+		builder := newBuilder(t).AddCertificates(
+			DRepRegistrationCertificate{DRepCredential: drepCredential, Anchor: &anchor}, voteDelegation)
+
+		fee, err := builder.Balance(addr)
+		require.NoError(t, err)
+		require.Equal(t, utxoAmount-pp.DRepDeposit-fee, builder.outputs[0].Amount)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		// payment key, drep key and stake key (vote delegation) witnesses
+		expectedFee, err := CalculateMinFee(txRaw, pp, 3, 0)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, fee, expectedFee)
+		require.LessOrEqual(t, fee, expectedFee+10*44)
+	})
+
+	t.Run("votes", func(t *testing.T) {
+		t.Parallel()
+
+		poolVoter, err := NewStakePoolVoter(keyHash)
+		require.NoError(t, err)
+
+		drepVoter := NewDRepVoter(drepCredential)
+
+		builder := newBuilder(t).
+			AddVote(poolVoter, NewGovActionID(actionTx1, 1), VoteNo, nil).
+			AddVote(drepVoter, NewGovActionID(actionTx1, 0), VoteYes, &anchor).
+			AddVote(drepVoter, NewGovActionID(actionTx2, 0), VoteAbstain, nil)
+
+		fee, err := builder.Balance(addr)
+		require.NoError(t, err)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		var tx decodedTx
+
+		require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+
+		// two voters, voters and governance actions are in the canonical order
+		drepVoterBytes, err := cbor.Marshal(drepVoter)
+		require.NoError(t, err)
+
+		drepVotesBytes, err := cbor.Marshal(cborOrderedMap{
+			{Key: NewGovActionID(actionTx2, 0), Value: VotingProcedure{Vote: VoteAbstain}},
+			{Key: NewGovActionID(actionTx1, 0), Value: VotingProcedure{Vote: VoteYes, Anchor: &anchor}},
+		})
+		require.NoError(t, err)
+
+		require.True(t, strings.HasPrefix(hex.EncodeToString(tx.Body[19]),
+			"a2"+hex.EncodeToString(drepVoterBytes)+hex.EncodeToString(drepVotesBytes)))
+
+		// payment key, drep key and pool key witnesses
+		expectedFee, err := CalculateMinFee(txRaw, pp, 3, 0)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, fee, expectedFee)
+		require.LessOrEqual(t, fee, expectedFee+10*44)
+
+		_, _, err = newBuilder(t).
+			AddVote(drepVoter, NewGovActionID(actionTx1, 0), VoteYes, nil).
+			AddVote(drepVoter, NewGovActionID(actionTx1, 0), VoteNo, nil).
+			AddOutputs(NewTxOutput(addr, 1_000_000)).SetFee(200_000).Build()
+		require.ErrorContains(t, err, "duplicate vote")
+	})
+
+	t.Run("proposals", func(t *testing.T) {
+		t.Parallel()
+
+		prevActionID := NewGovActionID(actionTx2, 3)
+		removedMember := CardanoAddressPayload{Payload: [KeyHashSize]byte(bytes.Repeat([]byte{1}, KeyHashSize))}
+		addedMember := CardanoAddressPayload{
+			Payload:  [KeyHashSize]byte(bytes.Repeat([]byte{2}, KeyHashSize)),
+			IsScript: true,
+		}
+		actions := []IGovAction{
+			InfoAction{},
+			NoConfidenceAction{PrevActionID: &prevActionID},
+			HardForkInitiationAction{ProtocolVersion: NewProtocolParametersVersion(10, 0)},
+			TreasuryWithdrawalsAction{Withdrawals: map[string]uint64{rewardAddr.String(): 1_000_000}},
+			UpdateCommitteeAction{
+				RemoveMembers:        []CardanoAddressPayload{removedMember},
+				AddMembers:           map[CardanoAddressPayload]uint64{addedMember: 100},
+				ThresholdNumerator:   2,
+				ThresholdDenominator: 3,
+			},
+			NewConstitutionAction{Anchor: anchor, ScriptHash: keyHash},
+			ParameterChangeAction{Update: map[uint64]interface{}{0: 44}, PolicyHash: keyHash},
+		}
+		expectedActions := []string{
+			"8106",
+			"8203" + "8258" + "20" + actionTx2 + "03",
+			"8301" + "f6" + "820a00",
+			"8302" + "a1" + "581d" + hex.EncodeToString(rewardAddr.GetBytes()) + "1a000f4240" + "f6",
+			"8504" + "f6" + "81" + "8200581c" + strings.Repeat("01", KeyHashSize) +
+				"a1" + "8201581c" + strings.Repeat("02", KeyHashSize) + "1864" + "d81e" + "820203",
+		}
+
+		for i, action := range actions {
+			builder := newBuilder(t).AddProposals(ProposalProcedure{
+				RewardAddress: rewardAddr.String(),
+				Action:        action,
+				Anchor:        anchor,
+			})
+
+			fee, err := builder.Balance(addr)
+			require.NoError(t, err)
+			require.Equal(t, utxoAmount-pp.GovActionDeposit-fee, builder.outputs[0].Amount)
+
+			txRaw, _, err := builder.Build()
+			require.NoError(t, err)
+
+			var (
+				tx        decodedTx
+				proposals []struct {
+					_             struct{} `cbor:",toarray"`
+					Deposit       uint64
+					RewardAccount []byte
+					Action        cbor.RawMessage
+					Anchor        cbor.RawMessage
+				}
+			)
+
+			require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+			require.NoError(t, cbor.Unmarshal(tx.Body[20], &proposals))
+			require.Len(t, proposals, 1)
+			require.Equal(t, pp.GovActionDeposit, proposals[0].Deposit)
+			require.Equal(t, rewardAddr.GetBytes(), proposals[0].RewardAccount)
+
+			if i < len(expectedActions) {
+				require.Equal(t, expectedActions[i], hex.EncodeToString(proposals[0].Action))
+			}
+		}
+
+		for _, action := range []IGovAction{
+			nil,
+			TreasuryWithdrawalsAction{},
+			TreasuryWithdrawalsAction{Withdrawals: map[string]uint64{addr: 1}},
+			ParameterChangeAction{},
+			NewConstitutionAction{Anchor: anchor, ScriptHash: "0102"},
+			UpdateCommitteeAction{ThresholdNumerator: 1},
+			UpdateCommitteeAction{ThresholdNumerator: 3, ThresholdDenominator: 2},
+			UpdateCommitteeAction{
+				RemoveMembers:        []CardanoAddressPayload{removedMember},
+				AddMembers:           map[CardanoAddressPayload]uint64{removedMember: 100},
+				ThresholdNumerator:   1,
+				ThresholdDenominator: 2,
+			},
+		} {
+			_, _, err := newBuilder(t).AddProposals(ProposalProcedure{
+				RewardAddress: rewardAddr.String(),
+				Action:        action,
+				Anchor:        anchor,
+			}).AddOutputs(NewTxOutput(addr, 1_000_000)).SetFee(200_000).Build()
+			require.Error(t, err)
+		}
+	})
+}
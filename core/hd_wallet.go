@@ -0,0 +1,158 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	ExtendedKeySize = 64 // kL (ed25519 scalar) and kR (nonce key)
+	ChainCodeSize   = 32
+
+	HardenedIndex = uint32(0x80000000)
+
+	// CIP-1852 purpose and Cardano coin type
+	CIP1852Purpose  = 1852 | HardenedIndex
+	CardanoCoinType = 1815 | HardenedIndex
+)
+
+// CIP-1852 roles (chains) of the account
+const (
+	RoleExternal      = uint32(0)
+	RoleInternal      = uint32(1) // change addresses
+	RoleStaking       = uint32(2)
+	RoleDRep          = uint32(3)
+	RoleCommitteeCold = uint32(4)
+	RoleCommitteeHot  = uint32(5)
+)
+
+// HDKey is BIP32-Ed25519 extended private key with the chain code
+type HDKey struct {
+	PrivateKey []byte // 64 bytes extended private key
+	ChainCode  []byte
+}
+
+func newHDKey(privateKey, chainCode []byte) (*HDKey, error) {
+	if len(privateKey) != ExtendedKeySize || len(chainCode) != ChainCodeSize {
+		return nil, fmt.Errorf("invalid extended key size: %d, chain code size: %d", len(privateKey), len(chainCode))
+	}
+
+	return &HDKey{
+		PrivateKey: append([]byte(nil), privateKey...),
+		ChainCode:  append([]byte(nil), chainCode...),
+	}, nil
+}
+
+// GetVerificationKey returns ed25519 public key of the extended private key
+func (k HDKey) GetVerificationKey() []byte {
+	return GetVerificationKeyFromSigningKey(k.PrivateKey)
+}
+
+// Derive derives child key (BIP32-Ed25519). Index greater or equal to HardenedIndex is hardened derivation
+func (k HDKey) Derive(index uint32) (*HDKey, error) {
+	var (
+		indexBytes = binary.LittleEndian.AppendUint32(nil, index)
+		zMac       = hmac.New(sha512.New, k.ChainCode)
+		ccMac      = hmac.New(sha512.New, k.ChainCode)
+	)
+
+	if index >= HardenedIndex {
+		zMac.Write([]byte{0x00})
+		zMac.Write(k.PrivateKey)
+		ccMac.Write([]byte{0x01})
+		ccMac.Write(k.PrivateKey)
+	} else {
+		publicKey := k.GetVerificationKey()
+
+		zMac.Write([]byte{0x02})
+		zMac.Write(publicKey)
+		ccMac.Write([]byte{0x03})
+		ccMac.Write(publicKey)
+	}
+
+	zMac.Write(indexBytes)
+	ccMac.Write(indexBytes)
+
+	z, cc := zMac.Sum(nil), ccMac.Sum(nil)
+	privateKey := make([]byte, ExtendedKeySize)
+
+	// kL = 8 * zL (first 28 bytes) + parent kL, kR = zR + parent kR (mod 2^256)
+	var carryL, carryR uint16
+
+	for i := 0; i < KeySize; i++ {
+		zl := uint16(0)
+		if i < KeyHashSize {
+			zl = uint16(z[i]) << 3
+		}
+
+		carryL += zl + uint16(k.PrivateKey[i])
+		privateKey[i] = byte(carryL)
+		carryL >>= 8
+
+		carryR += uint16(z[KeySize+i]) + uint16(k.PrivateKey[KeySize+i])
+		privateKey[KeySize+i] = byte(carryR)
+		carryR >>= 8
+	}
+
+	return newHDKey(privateKey, cc[KeySize:])
+}
+
+// DerivePath derives key along the path of indexes
+func (k HDKey) DerivePath(indexes ...uint32) (*HDKey, error) {
+	result := &k
+
+	for _, index := range indexes {
+		var err error
+
+		result, err = result.Derive(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// DeriveAccountKey derives key m/1852'/1815'/account'/role/index from the master key
+func (k HDKey) DeriveAccountKey(account, role, index uint32) (*HDKey, error) {
+	if account >= HardenedIndex || index >= HardenedIndex {
+		return nil, fmt.Errorf("account %d and index %d must be less than %d", account, index, HardenedIndex)
+	}
+
+	return k.DerivePath(CIP1852Purpose, CardanoCoinType, account|HardenedIndex, role, index)
+}
+
+// DeriveWallet derives wallet from the master key: payment key m/1852'/1815'/account'/0/index
+// and stake key m/1852'/1815'/account'/2/0
+func (k HDKey) DeriveWallet(account, index uint32) (*Wallet, error) {
+	paymentKey, err := k.DeriveAccountKey(account, RoleExternal, index)
+	if err != nil {
+		return nil, err
+	}
+
+	stakeKey, err := k.DeriveAccountKey(account, RoleStaking, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// extended keys must not be padded (truncated) to the ed25519 seed size.
+	// Chain code is kept (kL‖kR‖chainCode) so the keys can be exported as derivable cardano-cli keys
+	return &Wallet{
+		VerificationKey:      paymentKey.GetVerificationKey(),
+		SigningKey:           append(append([]byte{}, paymentKey.PrivateKey...), paymentKey.ChainCode...),
+		StakeVerificationKey: stakeKey.GetVerificationKey(),
+		StakeSigningKey:      append(append([]byte{}, stakeKey.PrivateKey...), stakeKey.ChainCode...),
+	}, nil
+}
+
+// NewWalletFromMnemonic restores wallet of the account and address index from the BIP-39 mnemonic
+func NewWalletFromMnemonic(mnemonic string, passphrase string, account, index uint32) (*Wallet, error) {
+	masterKey, err := NewMasterKeyFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return masterKey.DeriveWallet(account, index)
+}
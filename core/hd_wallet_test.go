@@ -0,0 +1,94 @@
+package core
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWalletFromMnemonic(t *testing.T) {
+	t.Parallel()
+
+	// CIP-19 test vector: payment key m/1852'/1815'/0'/0/0 and stake key m/1852'/1815'/0'/2/0
+	const mnemonic = "test walk nut penalty hip pave soap entry language right filter choice"
+
+	wallet, err := NewWalletFromMnemonic(mnemonic, "", 0, 0)
+	require.NoError(t, err)
+
+	addr, err := NewBaseAddress(TestNetNetwork, wallet.VerificationKey, wallet.StakeVerificationKey)
+	require.NoError(t, err)
+	require.Equal(t,
+		"addr_test1qz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3jcu5d8ps7zex2k2xt3uqxgjqnnj83ws8lhrn648jjxtwq2ytjqp",
+		addr.String())
+
+	rewardAddr, err := NewRewardAddress(TestNetNetwork, wallet.StakeVerificationKey)
+	require.NoError(t, err)
+	require.Equal(t, "stake_test1uqevw2xnsc0pvn9t9r9c7qryfqfeerchgrlm3ea2nefr9hqp8n5xl", rewardAddr.String())
+
+	// wallet signs with the extended keys
+	message := []byte("message")
+
+	for _, signer := range []ITxSigner{wallet, wallet.GetStakeSigner()} {
+		signature, err := signer.SignTransaction(message)
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(signer.GetTransactionVerificationKey(), message, signature))
+		require.NoError(t, VerifyMessage(message, signer.GetTransactionVerificationKey(), signature))
+	}
+
+	// other account and index
+	masterKey, err := NewMasterKeyFromMnemonic(mnemonic, "")
+	require.NoError(t, err)
+
+	sameWallet, err := masterKey.DeriveWallet(0, 0)
+	require.NoError(t, err)
+	require.Equal(t, wallet, sameWallet)
+
+	otherIndexWallet, err := masterKey.DeriveWallet(0, 1)
+	require.NoError(t, err)
+	require.NotEqual(t, wallet.VerificationKey, otherIndexWallet.VerificationKey)
+	require.Equal(t, wallet.StakeVerificationKey, otherIndexWallet.StakeVerificationKey)
+
+	otherAccountWallet, err := masterKey.DeriveWallet(1, 0)
+	require.NoError(t, err)
+	require.NotEqual(t, wallet.VerificationKey, otherAccountWallet.VerificationKey)
+	require.NotEqual(t, wallet.StakeVerificationKey, otherAccountWallet.StakeVerificationKey)
+
+	_, err = masterKey.DeriveWallet(HardenedIndex, 0)
+	require.Error(t, err)
+
+	_, err = NewWalletFromMnemonic(mnemonic+" choice", "", 0, 0)
+	require.ErrorIs(t, err, ErrInvalidMnemonic)
+}
+
+func TestHDKey_Derive(t *testing.T) {
+	t.Parallel()
+
+	masterKey, err := NewMasterKeyFromMnemonic(
+		"eight country switch draw meat scout mystery blade tip drift useless good keep usage title", "")
+	require.NoError(t, err)
+
+	accountKey, err := masterKey.DerivePath(CIP1852Purpose, CardanoCoinType, HardenedIndex)
+	require.NoError(t, err)
+
+	key, err := accountKey.DerivePath(RoleDRep, 0)
+	require.NoError(t, err)
+
+	sameKey, err := masterKey.DeriveAccountKey(0, RoleDRep, 0)
+	require.NoError(t, err)
+	require.Equal(t, key, sameKey)
+	require.Len(t, key.PrivateKey, ExtendedKeySize)
+	require.Len(t, key.ChainCode, ChainCodeSize)
+
+	// derived scalar keeps the lowest three bits cleared
+	require.Zero(t, key.PrivateKey[0]&0b111)
+
+	// hardened and soft derivation of the same index are different
+	hardenedKey, err := accountKey.Derive(RoleDRep | HardenedIndex)
+	require.NoError(t, err)
+
+	softKey, err := accountKey.Derive(RoleDRep)
+	require.NoError(t, err)
+	require.NotEqual(t, hardenedKey.PrivateKey, softKey.PrivateKey)
+	require.NotEqual(t, hardenedKey.ChainCode, softKey.ChainCode)
+}
@@ -0,0 +1,509 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	KeystoreScryptKDF   = "scrypt"
+	KeystoreArgon2idKDF = "argon2id"
+
+	keystoreVersion       = 1
+	keystoreCipher        = "xchacha20-poly1305"
+	keystoreSaltSize      = 32
+	keystoreFileExtension = ".json"
+	keystoreFilePerm      = 0o600
+	keystoreDirPerm       = 0o700
+)
+
+var (
+	ErrInvalidPassphrase   = errors.New("invalid keystore passphrase")
+	ErrKeystoreKeyNotFound = errors.New("keystore key not found")
+	ErrKeystoreKeyExists   = errors.New("keystore key already exists")
+)
+
+// KeystoreKDFParams are parameters of the key derivation function which derives encryption key from the passphrase
+type KeystoreKDFParams struct {
+	KDF string `json:"kdf"`
+	// scrypt
+	N int `json:"n,omitempty"`
+	R int `json:"r,omitempty"`
+	P int `json:"p,omitempty"`
+	// argon2id
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"` // KiB
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+// NewScryptKDFParams returns recommended scrypt parameters
+func NewScryptKDFParams() KeystoreKDFParams {
+	return KeystoreKDFParams{KDF: KeystoreScryptKDF, N: 1 << 18, R: 8, P: 1}
+}
+
+// NewArgon2idKDFParams returns recommended argon2id parameters
+func NewArgon2idKDFParams() KeystoreKDFParams {
+	return KeystoreKDFParams{KDF: KeystoreArgon2idKDF, Time: 3, Memory: 64 * 1024, Threads: 4}
+}
+
+func (p KeystoreKDFParams) validate() error {
+	switch p.KDF {
+	case KeystoreScryptKDF:
+		if p.N <= 1 || p.N&(p.N-1) != 0 || p.R <= 0 || p.P <= 0 {
+			return fmt.Errorf("invalid scrypt parameters: n=%d, r=%d, p=%d", p.N, p.R, p.P)
+		}
+	case KeystoreArgon2idKDF:
+		if p.Time == 0 || p.Memory == 0 || p.Threads == 0 {
+			return fmt.Errorf("invalid argon2id parameters: time=%d, memory=%d, threads=%d", p.Time, p.Memory, p.Threads)
+		}
+	default:
+		return fmt.Errorf("unknown keystore kdf: %s", p.KDF)
+	}
+
+	return nil
+}
+
+func (p KeystoreKDFParams) deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	if p.KDF == KeystoreArgon2idKDF {
+		return argon2.IDKey([]byte(passphrase), salt, p.Time, p.Memory, p.Threads, chacha20poly1305.KeySize), nil
+	}
+
+	return scrypt.Key([]byte(passphrase), salt, p.N, p.R, p.P, chacha20poly1305.KeySize)
+}
+
+// KeystoreEntry describes wallet stored in the keystore. It is readable without the passphrase
+type KeystoreEntry struct {
+	KeyHash      string `json:"keyHash"`
+	StakeKeyHash string `json:"stakeKeyHash,omitempty"`
+}
+
+type keystoreFile struct {
+	Version int `json:"version"`
+	KeystoreEntry
+	Crypto keystoreCrypto `json:"crypto"`
+}
+
+type keystoreCrypto struct {
+	KDFParams  KeystoreKDFParams `json:"kdfParams"`
+	Salt       string            `json:"salt"`
+	Cipher     string            `json:"cipher"`
+	Nonce      string            `json:"nonce"`
+	Ciphertext string            `json:"ciphertext"`
+}
+
+// Keystore keeps wallets encrypted with the passphrase in the directory, one file per wallet named
+// by the payment key hash. Encryption key is derived with scrypt or argon2id and wallet is encrypted
+// with xchacha20-poly1305
+type Keystore struct {
+	directory string
+	kdfParams KeystoreKDFParams
+}
+
+// NewKeystore creates keystore in the directory. kdfParams are used for the newly encrypted wallets,
+// existing wallets are decrypted with the parameters they were encrypted with
+func NewKeystore(directory string, kdfParams KeystoreKDFParams) (*Keystore, error) {
+	if err := kdfParams.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(directory, keystoreDirPerm); err != nil {
+		return nil, err
+	}
+
+	return &Keystore{
+		directory: directory,
+		kdfParams: kdfParams,
+	}, nil
+}
+
+// Save encrypts the wallet with the passphrase and stores it. Returns key hash of the payment key
+func (ks *Keystore) Save(wallet *Wallet, passphrase string) (string, error) {
+	file, err := ks.encrypt(wallet, passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	filePath := ks.getFilePath(file.KeyHash)
+	if _, err := os.Stat(filePath); err == nil {
+		return "", fmt.Errorf("%w: %s", ErrKeystoreKeyExists, file.KeyHash)
+	}
+
+	return file.KeyHash, ks.write(filePath, file)
+}
+
+// Load decrypts the wallet with the payment key hash
+func (ks *Keystore) Load(keyHash string, passphrase string) (*Wallet, error) {
+	file, err := ks.read(keyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return file.decrypt(passphrase)
+}
+
+// List returns all the wallets in the keystore ordered by the key hash
+func (ks *Keystore) List() ([]KeystoreEntry, error) {
+	entries, err := os.ReadDir(ks.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []KeystoreEntry
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, keystoreFileExtension) {
+			continue
+		}
+
+		file, err := ks.read(strings.TrimSuffix(name, keystoreFileExtension))
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, file.KeystoreEntry)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].KeyHash < result[j].KeyHash
+	})
+
+	return result, nil
+}
+
+// ChangePassphrase re-encrypts the wallet with the new passphrase (and the current kdf parameters of the keystore)
+func (ks *Keystore) ChangePassphrase(keyHash string, oldPassphrase string, newPassphrase string) error {
+	wallet, err := ks.Load(keyHash, oldPassphrase)
+	if err != nil {
+		return err
+	}
+
+	file, err := ks.encrypt(wallet, newPassphrase)
+	if err != nil {
+		return err
+	}
+
+	return ks.write(ks.getFilePath(keyHash), file)
+}
+
+// Delete removes the wallet from the keystore. Passphrase is required so the wallet is not removed by mistake
+func (ks *Keystore) Delete(keyHash string, passphrase string) error {
+	if _, err := ks.Load(keyHash, passphrase); err != nil {
+		return err
+	}
+
+	return os.Remove(ks.getFilePath(keyHash))
+}
+
+// Export decrypts the wallet and writes its keys as plain cardano-cli text envelopes into the directory:
+// payment.skey, payment.vkey and stake.skey, stake.vkey for the stake wallet.
+// Existing files are never overwritten. Extended signing keys are exported only if the wallet contains
+// their chain code (e.g. wallets derived from the mnemonic)
+func (ks *Keystore) Export(keyHash string, passphrase string, directory string) error {
+	wallet, err := ks.Load(keyHash, passphrase)
+	if err != nil {
+		return err
+	}
+
+	keys, err := getWalletKeys(wallet)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(directory, keystoreDirPerm); err != nil {
+		return err
+	}
+
+	// check all files first so the export is not left half done
+	for fileName := range keys {
+		if _, err := os.Stat(filepath.Join(directory, fileName)); err == nil {
+			return fmt.Errorf("%w: %s", os.ErrExist, filepath.Join(directory, fileName))
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	for fileName, key := range keys {
+		bytes, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+
+		if err := writeNewFile(filepath.Join(directory, fileName), bytes, keystoreFilePerm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeNewFile writes the file which must not exist
+func writeNewFile(filePath string, data []byte, perm os.FileMode) error {
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
+
+func (ks *Keystore) encrypt(wallet *Wallet, passphrase string) (*keystoreFile, error) {
+	keyHash, err := GetKeyHash(wallet.VerificationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &keystoreFile{
+		Version:       keystoreVersion,
+		KeystoreEntry: KeystoreEntry{KeyHash: keyHash},
+	}
+
+	if len(wallet.StakeVerificationKey) > 0 {
+		file.StakeKeyHash, err = GetKeyHash(wallet.StakeVerificationKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plaintext, err := json.Marshal(wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, keystoreSaltSize)
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	key, err := ks.kdfParams.deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file.Crypto = keystoreCrypto{
+		KDFParams:  ks.kdfParams,
+		Salt:       hex.EncodeToString(salt),
+		Cipher:     keystoreCipher,
+		Nonce:      hex.EncodeToString(nonce),
+		Ciphertext: hex.EncodeToString(aead.Seal(nil, nonce, plaintext, file.getAdditionalData())),
+	}
+
+	return file, nil
+}
+
+func (f keystoreFile) decrypt(passphrase string) (*Wallet, error) {
+	if f.Crypto.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("unknown keystore cipher: %s", f.Crypto.Cipher)
+	}
+
+	salt, err := hex.DecodeString(f.Crypto.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore salt: %w", err)
+	}
+
+	nonce, err := hex.DecodeString(f.Crypto.Nonce)
+	if err != nil || len(nonce) != chacha20poly1305.NonceSizeX {
+		return nil, fmt.Errorf("invalid keystore nonce: %s", f.Crypto.Nonce)
+	}
+
+	ciphertext, err := hex.DecodeString(f.Crypto.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore ciphertext: %w", err)
+	}
+
+	key, err := f.Crypto.KDFParams.deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, f.getAdditionalData())
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+
+	var wallet Wallet
+
+	if err := json.Unmarshal(plaintext, &wallet); err != nil {
+		return nil, err
+	}
+
+	return &wallet, nil
+}
+
+// getAdditionalData binds the unencrypted part of the file to the ciphertext
+func (f keystoreFile) getAdditionalData() []byte {
+	return []byte(fmt.Sprintf("%d:%s:%s", f.Version, f.KeyHash, f.StakeKeyHash))
+}
+
+func (ks *Keystore) read(keyHash string) (*keystoreFile, error) {
+	if bytes, err := hex.DecodeString(keyHash); err != nil || len(bytes) != KeyHashSize {
+		return nil, fmt.Errorf("%w: invalid key hash %s", ErrKeystoreKeyNotFound, keyHash)
+	}
+
+	bytes, err := os.ReadFile(ks.getFilePath(keyHash))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrKeystoreKeyNotFound, keyHash)
+		}
+
+		return nil, err
+	}
+
+	var file keystoreFile
+
+	if err := json.Unmarshal(bytes, &file); err != nil {
+		return nil, fmt.Errorf("invalid keystore file %s: %w", keyHash, err)
+	} else if file.Version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version %d: %s", file.Version, keyHash)
+	} else if file.KeyHash != keyHash {
+		return nil, fmt.Errorf("keystore file %s contains key %s", keyHash, file.KeyHash)
+	}
+
+	return &file, nil
+}
+
+// write writes the file atomically so the key is not lost if writing fails
+func (ks *Keystore) write(filePath string, file *keystoreFile) error {
+	bytes, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(ks.directory, ".keystore-*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(bytes); err != nil {
+		tmpFile.Close()
+
+		return err
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmpFile.Name(), keystoreFilePerm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), filePath)
+}
+
+func (ks *Keystore) getFilePath(keyHash string) string {
+	return filepath.Join(ks.directory, keyHash+keystoreFileExtension)
+}
+
+// getWalletKeys returns cardano-cli text envelopes of the wallet keys by the file name
+// getExtendedSigningKeyChainCode returns chain code of the kL‖kR‖chainCode or kL‖kR‖publicKey‖chainCode key.
+// Export of the key without the chain code (kL‖kR) is refused, because the exported key type implies derivation
+func getExtendedSigningKeyChainCode(key []byte) ([]byte, error) {
+	switch len(key) {
+	case ExtendedKeySize + ChainCodeSize, ExtendedKeySize + KeySize + ChainCodeSize:
+		return key[len(key)-ChainCodeSize:], nil
+	case ExtendedKeySize:
+		return nil, errors.New("extended signing key without chain code can not be exported")
+	default:
+		return nil, fmt.Errorf("invalid extended signing key size: %d", len(key))
+	}
+}
+
+func getWalletKeys(wallet *Wallet) (map[string]Key, error) {
+	result := map[string]Key{}
+
+	for _, x := range []struct {
+		name                        string
+		signingKey, verificationKey []byte
+		skeyType, xskeyType         string
+		skeyDesc                    string
+		vkeyType, vkeyDesc          string
+	}{
+		{
+			"payment", wallet.SigningKey, wallet.VerificationKey,
+			PaymentSigningKeyShelley, PaymentExtendedSigningKeyShelley, PaymentSigningKeyShelleyDesc,
+			PaymentVerificationKeyShelley, PaymentVerificationKeyShelleyDesc,
+		},
+		{
+			"stake", wallet.StakeSigningKey, wallet.StakeVerificationKey,
+			StakeSigningKeyShelley, StakeExtendedSigningKeyShelley, StakeSigningKeyShelleyDesc,
+			StakeVerificationKeyShelley, StakeVerificationKeyShelleyDesc,
+		},
+	} {
+		if len(x.signingKey) == 0 {
+			continue
+		}
+
+		skeyType, signingKey := x.skeyType, x.signingKey
+
+		if len(signingKey) > KeySize {
+			extendedKey, err := getExtendedSigningKey(signingKey)
+			if err != nil {
+				return nil, err
+			}
+
+			chainCode, err := getExtendedSigningKeyChainCode(signingKey)
+			if err != nil {
+				return nil, fmt.Errorf("%s key: %w", x.name, err)
+			}
+
+			// kL‖kR‖publicKey‖chainCode
+			skeyType = x.xskeyType
+			signingKey = append(append(append([]byte{}, extendedKey...), x.verificationKey...), chainCode...)
+		}
+
+		skey, err := NewKeyFromBytes(skeyType, x.skeyDesc, signingKey)
+		if err != nil {
+			return nil, err
+		}
+
+		vkey, err := NewKeyFromBytes(x.vkeyType, x.vkeyDesc, x.verificationKey)
+		if err != nil {
+			return nil, err
+		}
+
+		result[x.name+".skey"] = skey
+		result[x.name+".vkey"] = vkey
+	}
+
+	return result, nil
+}
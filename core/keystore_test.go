@@ -0,0 +1,253 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystore(t *testing.T) {
+	t.Parallel()
+
+	const passphrase = "correct horse battery staple"
+
+	for _, kdfParams := range []KeystoreKDFParams{
+		{KDF: KeystoreScryptKDF, N: 1 << 10, R: 8, P: 1},
+		{KDF: KeystoreArgon2idKDF, Time: 1, Memory: 1024, Threads: 1},
+	} {
+		kdfParams := kdfParams
+
+		t.Run(kdfParams.KDF, func(t *testing.T) {
+			t.Parallel()
+
+			directory := filepath.Join(t.TempDir(), "keystore")
+
+			keystore, err := NewKeystore(directory, kdfParams)
+			require.NoError(t, err)
+
+			stakeWallet, err := GenerateWallet(true)
+			require.NoError(t, err)
+
+			wallet, err := GenerateWallet(false)
+			require.NoError(t, err)
+
+			hdWallet, err := NewWalletFromMnemonic(
+				"test walk nut penalty hip pave soap entry language right filter choice", "", 0, 0)
+			require.NoError(t, err)
+
+			entries := make([]KeystoreEntry, 0, 3)
+
+			for _, w := range []*Wallet{stakeWallet, wallet, hdWallet} {
+				keyHash, err := keystore.Save(w, passphrase)
+				require.NoError(t, err)
+
+				expectedKeyHash, err := GetKeyHash(w.VerificationKey)
+				require.NoError(t, err)
+				require.Equal(t, expectedKeyHash, keyHash)
+
+				entry := KeystoreEntry{KeyHash: keyHash}
+
+				if len(w.StakeVerificationKey) > 0 {
+					entry.StakeKeyHash, err = GetKeyHash(w.StakeVerificationKey)
+					require.NoError(t, err)
+				}
+
+				entries = append(entries, entry)
+
+				// file is not readable by others and does not contain the signing key
+				info, err := os.Stat(filepath.Join(directory, keyHash+".json"))
+				require.NoError(t, err)
+				require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+				loaded, err := keystore.Load(keyHash, passphrase)
+				require.NoError(t, err)
+				require.Equal(t, w, loaded)
+
+				_, err = keystore.Load(keyHash, "wrong passphrase")
+				require.ErrorIs(t, err, ErrInvalidPassphrase)
+			}
+
+			_, err = keystore.Save(wallet, passphrase)
+			require.ErrorIs(t, err, ErrKeystoreKeyExists)
+
+			list, err := keystore.List()
+			require.NoError(t, err)
+			require.ElementsMatch(t, entries, list)
+
+			for i := 1; i < len(list); i++ {
+				require.Less(t, list[i-1].KeyHash, list[i].KeyHash)
+			}
+
+			// passphrase rotation
+			require.ErrorIs(t, keystore.ChangePassphrase(entries[0].KeyHash, "wrong", "new"), ErrInvalidPassphrase)
+			require.NoError(t, keystore.ChangePassphrase(entries[0].KeyHash, passphrase, "new passphrase"))
+
+			_, err = keystore.Load(entries[0].KeyHash, passphrase)
+			require.ErrorIs(t, err, ErrInvalidPassphrase)
+
+			loaded, err := keystore.Load(entries[0].KeyHash, "new passphrase")
+			require.NoError(t, err)
+			require.Equal(t, stakeWallet, loaded)
+
+			// reopened keystore
+			keystore, err = NewKeystore(directory, kdfParams)
+			require.NoError(t, err)
+
+			loaded, err = keystore.Load(entries[1].KeyHash, passphrase)
+			require.NoError(t, err)
+			require.Equal(t, wallet, loaded)
+
+			_, err = keystore.Load("d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21", passphrase)
+			require.ErrorIs(t, err, ErrKeystoreKeyNotFound)
+
+			_, err = keystore.Load("../"+entries[1].KeyHash, passphrase)
+			require.ErrorIs(t, err, ErrKeystoreKeyNotFound)
+
+			// delete
+			require.ErrorIs(t, keystore.Delete(entries[1].KeyHash, "wrong"), ErrInvalidPassphrase)
+			require.NoError(t, keystore.Delete(entries[1].KeyHash, passphrase))
+
+			list, err = keystore.List()
+			require.NoError(t, err)
+			require.Len(t, list, 2)
+		})
+	}
+
+	t.Run("invalid kdf params", func(t *testing.T) {
+		t.Parallel()
+
+		for _, kdfParams := range []KeystoreKDFParams{
+			{KDF: "pbkdf2"},
+			{KDF: KeystoreScryptKDF, N: 1000, R: 8, P: 1},
+			{KDF: KeystoreArgon2idKDF, Time: 1},
+		} {
+			_, err := NewKeystore(t.TempDir(), kdfParams)
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("tampered file", func(t *testing.T) {
+		t.Parallel()
+
+		directory := t.TempDir()
+
+		keystore, err := NewKeystore(directory, KeystoreKDFParams{KDF: KeystoreScryptKDF, N: 1 << 10, R: 8, P: 1})
+		require.NoError(t, err)
+
+		wallet, err := GenerateWallet(true)
+		require.NoError(t, err)
+
+		keyHash, err := keystore.Save(wallet, passphrase)
+		require.NoError(t, err)
+
+		filePath := filepath.Join(directory, keyHash+".json")
+
+		bytes, err := os.ReadFile(filePath)
+		require.NoError(t, err)
+
+		otherStakeKeyHash, err := GetKeyHash(wallet.VerificationKey)
+		require.NoError(t, err)
+
+		stakeKeyHash, err := GetKeyHash(wallet.StakeVerificationKey)
+		require.NoError(t, err)
+
+		// unencrypted metadata is authenticated
+		require.NoError(t, os.WriteFile(filePath,
+			[]byte(strings.ReplaceAll(string(bytes), stakeKeyHash, otherStakeKeyHash)), 0o600))
+
+		_, err = keystore.Load(keyHash, passphrase)
+		require.ErrorIs(t, err, ErrInvalidPassphrase)
+	})
+}
+
+func TestKeystore_Export(t *testing.T) {
+	t.Parallel()
+
+	const passphrase = "passphrase"
+
+	keystore, err := NewKeystore(t.TempDir(), KeystoreKDFParams{KDF: KeystoreScryptKDF, N: 1 << 10, R: 8, P: 1})
+	require.NoError(t, err)
+
+	wallet, err := GenerateWallet(true)
+	require.NoError(t, err)
+
+	hdWallet, err := NewWalletFromMnemonic(
+		"test walk nut penalty hip pave soap entry language right filter choice", "", 0, 0)
+	require.NoError(t, err)
+
+	var (
+		message         = []byte("message")
+		exportDirectory string
+	)
+
+	for _, w := range []*Wallet{wallet, hdWallet} {
+		keyHash, err := keystore.Save(w, passphrase)
+		require.NoError(t, err)
+
+		exportDirectory = t.TempDir()
+
+		require.ErrorIs(t, keystore.Export(keyHash, "wrong", exportDirectory), ErrInvalidPassphrase)
+		require.NoError(t, keystore.Export(keyHash, passphrase, exportDirectory))
+		require.ErrorIs(t, keystore.Export(keyHash, passphrase, exportDirectory), os.ErrExist)
+
+		for _, x := range []struct {
+			name   string
+			signer ITxSigner
+		}{
+			{"payment", w},
+			{"stake", w.GetStakeSigner()},
+		} {
+			skey, err := NewKey(filepath.Join(exportDirectory, x.name+".skey"))
+			require.NoError(t, err)
+
+			vkey, err := NewKey(filepath.Join(exportDirectory, x.name+".vkey"))
+			require.NoError(t, err)
+
+			vkeyBytes, err := vkey.GetKeyBytes()
+			require.NoError(t, err)
+			require.Equal(t, x.signer.GetTransactionVerificationKey(), vkeyBytes)
+
+			signer, err := skey.GetSigner()
+			require.NoError(t, err)
+			require.Equal(t, vkeyBytes, signer.GetTransactionVerificationKey())
+
+			expectedSignature, err := x.signer.SignTransaction(message)
+			require.NoError(t, err)
+
+			signature, err := signer.SignTransaction(message)
+			require.NoError(t, err)
+			require.Equal(t, expectedSignature, signature)
+		}
+	}
+
+	// chain code of the derived key is exported (hdWallet is exported last)
+	paymentKey, err := NewKey(filepath.Join(exportDirectory, "payment.skey"))
+	require.NoError(t, err)
+
+	paymentKeyBytes, err := paymentKey.GetKeyBytes()
+	require.NoError(t, err)
+
+	masterKey, err := NewMasterKeyFromMnemonic(
+		"test walk nut penalty hip pave soap entry language right filter choice", "")
+	require.NoError(t, err)
+
+	hdKey, err := masterKey.DeriveAccountKey(0, RoleExternal, 0)
+	require.NoError(t, err)
+
+	require.Equal(t, PaymentExtendedSigningKeyShelley, paymentKey.Type)
+	require.Equal(t, hdKey.ChainCode, paymentKeyBytes[ExtendedKeySize+KeySize:])
+
+	// extended key without chain code can not be exported as derivable key
+	noChainCodeWallet := NewWallet(hdWallet.VerificationKey, hdWallet.SigningKey[:ExtendedKeySize])
+
+	otherKeystore, err := NewKeystore(t.TempDir(), KeystoreKDFParams{KDF: KeystoreScryptKDF, N: 1 << 10, R: 8, P: 1})
+	require.NoError(t, err)
+
+	keyHash, err := otherKeystore.Save(noChainCodeWallet, passphrase)
+	require.NoError(t, err)
+
+	require.ErrorContains(t, otherKeystore.Export(keyHash, passphrase, t.TempDir()), "without chain code")
+}
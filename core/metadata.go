@@ -0,0 +1,167 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	metadataMaxStringLength = 64
+	auxiliaryDataAlonzoTag  = 259
+)
+
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
+// newAuxiliaryDataFromMetadataJSON converts json metadata (same format as cardano-cli --metadata-json-file
+// with no schema) to the cbor auxiliary data
+func newAuxiliaryDataFromMetadataJSON(metadata []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(metadata))
+	decoder.UseNumber()
+
+	var data map[string]interface{}
+
+	if err := decoder.Decode(&data); err != nil {
+		return nil, errors.Join(ErrInvalidMetadata, err)
+	}
+
+	type metadataItem struct {
+		label uint64
+		value interface{}
+	}
+
+	items := make([]metadataItem, 0, len(data))
+
+	for key, value := range data {
+		label, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: label %s is not a number", ErrInvalidMetadata, key)
+		}
+
+		convertedValue, err := convertMetadataJSONValue(value)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, metadataItem{label: label, value: convertedValue})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].label < items[j].label
+	})
+
+	metadataMap := make(cborOrderedMap, len(items))
+	for i, item := range items {
+		metadataMap[i] = cborOrderedMapItem{Key: item.label, Value: item.value}
+	}
+
+	metadataBytes, err := metadataMap.MarshalCBOR()
+	if err != nil {
+		return nil, err
+	}
+
+	// alonzo format: #6.259({ 0: metadata })
+	result := appendCborHead(nil, cborMajorTypeTag, auxiliaryDataAlonzoTag)
+	result = appendCborHead(result, cborMajorTypeMap, 1)
+	result = appendCborHead(result, cborMajorTypeUnsignedInt, 0)
+
+	return append(result, metadataBytes...), nil
+}
+
+func convertMetadataJSONValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return convertMetadataJSONNumber(v.String())
+	case string:
+		return convertMetadataJSONString(v)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+
+		for i, x := range v {
+			item, err := convertMetadataJSONValue(x)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = item
+		}
+
+		return result, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		// keys are sorted the same way as in cardano-cli (lexicographically)
+		sort.Strings(keys)
+
+		result := make(cborOrderedMap, len(keys))
+
+		for i, key := range keys {
+			convertedKey, err := convertMetadataJSONKey(key)
+			if err != nil {
+				return nil, err
+			}
+
+			convertedValue, err := convertMetadataJSONValue(v[key])
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = cborOrderedMapItem{Key: convertedKey, Value: convertedValue}
+		}
+
+		return result, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %T", ErrInvalidMetadata, value)
+	}
+}
+
+func convertMetadataJSONKey(key string) (interface{}, error) {
+	if value, err := convertMetadataJSONNumber(key); err == nil {
+		return value, nil
+	}
+
+	return convertMetadataJSONString(key)
+}
+
+func convertMetadataJSONNumber(value string) (interface{}, error) {
+	if v, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return v, nil
+	}
+
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: number %s is not a 64 bit integer", ErrInvalidMetadata, value)
+	}
+
+	return v, nil
+}
+
+func convertMetadataJSONString(value string) (interface{}, error) {
+	if hexValue, found := strings.CutPrefix(value, "0x"); found {
+		if bytes, err := hex.DecodeString(hexValue); err == nil {
+			if len(bytes) > metadataMaxStringLength {
+				return nil, fmt.Errorf("%w: bytes %s longer than %d", ErrInvalidMetadata, value, metadataMaxStringLength)
+			}
+
+			return bytes, nil
+		}
+	}
+
+	if len(value) > metadataMaxStringLength {
+		return nil, fmt.Errorf("%w: text %s longer than %d", ErrInvalidMetadata, value, metadataMaxStringLength)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,30 @@
+package core
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAuxiliaryDataFromMetadataJSON(t *testing.T) {
+	t.Parallel()
+
+	auxData, err := newAuxiliaryDataFromMetadataJSON(
+		[]byte(`{"10": {"c": [true, null], "b": -1, "a": "0x0102", "12": "x"}, "2": 7}`))
+	require.NoError(t, err)
+
+	require.Equal(t,
+		"d90103a100a202070aa40c61786161420102616220616382647472756564"+"6e756c6c",
+		hex.EncodeToString(auxData))
+
+	_, err = newAuxiliaryDataFromMetadataJSON([]byte(`{"a": 1}`))
+	require.ErrorIs(t, err, ErrInvalidMetadata)
+
+	_, err = newAuxiliaryDataFromMetadataJSON([]byte(`{"1": 1.5}`))
+	require.ErrorIs(t, err, ErrInvalidMetadata)
+
+	_, err = newAuxiliaryDataFromMetadataJSON([]byte(`{"1": "` + strings.Repeat("a", 65) + `"}`))
+	require.ErrorIs(t, err, ErrInvalidMetadata)
+}
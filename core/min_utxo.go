@@ -0,0 +1,67 @@
+package core
+
+import "fmt"
+
+// minUtxoOutputOverhead is the constant overhead of the output in babbage min utxo calculation
+const minUtxoOutputOverhead = 160
+
+// GetMinUtxoForOutput returns babbage minimal lovelace for the output:
+// (160 + serialized output size) * coins per utxo byte (UtxoCostPerByte from the protocol parameters).
+// Size of the output depends on the amount so the amount is increased until the value is stable
+func GetMinUtxoForOutput(output TxOutput, coinsPerUtxoByte uint64) (uint64, error) {
+	output.setLovelace(0)
+
+	for {
+		rawOutput, err := newTxRawOutput(output)
+		if err != nil {
+			return 0, err
+		}
+
+		bytes, err := cborEncMode.Marshal(rawOutput)
+		if err != nil {
+			return 0, err
+		}
+
+		minUtxoValue := (minUtxoOutputOverhead + uint64(len(bytes))) * coinsPerUtxoByte
+		if minUtxoValue <= output.getLovelace() {
+			return output.getLovelace(), nil
+		}
+
+		output.setLovelace(minUtxoValue)
+	}
+}
+
+// SetAutoMinUtxo sets whether the amount of every output (which amount is specified)
+// should be automatically raised to the min utxo value before fee calculation and build
+func (b *TxBuilder) SetAutoMinUtxo(autoMinUtxo bool) *TxBuilder {
+	b.autoMinUtxo = autoMinUtxo
+
+	return b
+}
+
+// adjustOutputsToMinUtxo raises amounts of the outputs to the min utxo value if auto min utxo is set
+func (b *TxBuilder) adjustOutputsToMinUtxo() error {
+	if !b.autoMinUtxo {
+		return nil
+	}
+
+	protocolParameters, err := b.getProtocolParameters()
+	if err != nil {
+		return err
+	}
+
+	for i, output := range b.outputs {
+		if output.getLovelace() == 0 {
+			continue // amount is not specified yet
+		}
+
+		minUtxoValue, err := GetMinUtxoForOutput(output, protocolParameters.UtxoCostPerByte)
+		if err != nil {
+			return fmt.Errorf("output (%s, %d): %w", output.Addr, i, err)
+		}
+
+		b.outputs[i].setLovelace(max(output.getLovelace(), minUtxoValue))
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMinUtxoForOutput(t *testing.T) {
+	t.Parallel()
+
+	const addr = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+
+	// 82 581d <29 bytes> 1a <4 bytes>
+	minUtxoValue, err := GetMinUtxoForOutput(NewTxOutput(addr, 0), 4310)
+	require.NoError(t, err)
+	require.Equal(t, uint64((160+1+2+29+5)*4310), minUtxoValue)
+
+	minUtxoValueWithToken, err := GetMinUtxoForOutput(NewTxOutput(addr, 0,
+		NewTokenAmount("29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8", "Route3", 1)), 4310)
+	require.NoError(t, err)
+	// 82 [1a <4 bytes> a1 581c <28 bytes> a1 46 <6 bytes> 01]
+	require.Equal(t, uint64((160+1+2+29+1+5+1+2+28+1+7+1)*4310), minUtxoValueWithToken)
+}
+
+func TestTxBuilder_SetAutoMinUtxo(t *testing.T) {
+	t.Parallel()
+
+	const addr = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+
+	builder, err := NewTxBuilder("")
+	require.NoError(t, err)
+
+	builder.SetProtocolParameters(protocolParameters).AddOutputs(
+		NewTxOutput(addr, 1),
+		NewTxOutput(addr, 2_000_000),
+		NewTxOutput(addr, 0),
+	).AddInputs(NewTxInput("e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f", 0))
+
+	_, err = builder.CalculateFee(1)
+	require.NoError(t, err)
+
+	require.Equal(t, uint64(1), builder.outputs[0].Amount)
+
+	builder.SetAutoMinUtxo(true)
+
+	_, err = builder.CalculateFee(1)
+	require.NoError(t, err)
+
+	require.Equal(t, uint64(849_070), builder.outputs[0].Amount)
+	require.Equal(t, uint64(2_000_000), builder.outputs[1].Amount)
+	require.Equal(t, uint64(0), builder.outputs[2].Amount)
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	icarusPbkdf2Iterations = 4096
+	icarusMasterKeySize    = ExtendedKeySize + ChainCodeSize
+)
+
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+// GenerateMnemonic generates BIP-39 english mnemonic with 12, 15, 18, 21 or 24 words
+func GenerateMnemonic(wordsCount int) (string, error) {
+	if err := validateMnemonicWordsCount(wordsCount); err != nil {
+		return "", err
+	}
+
+	// every 3 words contain 32 bits of the entropy and 1 bit of the checksum
+	entropy, err := bip39.NewEntropy(wordsCount / 3 * 32)
+	if err != nil {
+		return "", err
+	}
+
+	return bip39.NewMnemonic(entropy)
+}
+
+// ValidateMnemonic checks words count, words and checksum of the BIP-39 english mnemonic
+func ValidateMnemonic(mnemonic string) error {
+	_, err := getMnemonicEntropy(mnemonic)
+
+	return err
+}
+
+// NewMasterKeyFromMnemonic creates Icarus (CIP-3) master key from the BIP-39 mnemonic and optional passphrase.
+// Same master key is used by Eternl, Lace, Daedalus (Shelley wallets) and Yoroi
+func NewMasterKeyFromMnemonic(mnemonic string, passphrase string) (*HDKey, error) {
+	entropy, err := getMnemonicEntropy(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	data := pbkdf2.Key([]byte(passphrase), entropy, icarusPbkdf2Iterations, icarusMasterKeySize, sha512.New)
+
+	// clamp the scalar as in ed25519 and clear the third highest bit (BIP32-Ed25519)
+	data[0] &= 0b1111_1000
+	data[31] &= 0b0001_1111
+	data[31] |= 0b0100_0000
+
+	return newHDKey(data[:ExtendedKeySize], data[ExtendedKeySize:])
+}
+
+func getMnemonicEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if err := validateMnemonicWordsCount(len(words)); err != nil {
+		return nil, err
+	}
+
+	entropy, err := bip39.EntropyFromMnemonic(strings.Join(words, " "))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMnemonic, err)
+	}
+
+	return entropy, nil
+}
+
+func validateMnemonicWordsCount(wordsCount int) error {
+	if wordsCount < 12 || wordsCount > 24 || wordsCount%3 != 0 {
+		return fmt.Errorf("%w: words count must be 12, 15, 18, 21 or 24 but it is %d", ErrInvalidMnemonic, wordsCount)
+	}
+
+	return nil
+}
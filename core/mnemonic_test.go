@@ -0,0 +1,61 @@
+package core
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMnemonic(t *testing.T) {
+	t.Parallel()
+
+	for _, wordsCount := range []int{12, 15, 18, 21, 24} {
+		mnemonic, err := GenerateMnemonic(wordsCount)
+		require.NoError(t, err)
+		require.Len(t, strings.Fields(mnemonic), wordsCount)
+		require.NoError(t, ValidateMnemonic(mnemonic))
+	}
+
+	for _, wordsCount := range []int{0, 9, 13, 27} {
+		_, err := GenerateMnemonic(wordsCount)
+		require.ErrorIs(t, err, ErrInvalidMnemonic)
+	}
+
+	for _, mnemonic := range []string{
+		"",
+		"test walk nut penalty hip pave soap entry language right filter",
+		"test walk nut penalty hip pave soap entry language right filter filter",
+		"test walk nut penalty hip pave soap entry language right filter cardano",
+	} {
+		require.ErrorIs(t, ValidateMnemonic(mnemonic), ErrInvalidMnemonic)
+	}
+}
+
+func TestNewMasterKeyFromMnemonic(t *testing.T) {
+	t.Parallel()
+
+	// CIP-3 Icarus test vector
+	masterKey, err := NewMasterKeyFromMnemonic(
+		"eight country switch draw meat scout mystery blade tip drift useless good keep usage title", "")
+	require.NoError(t, err)
+	require.Equal(t,
+		"c065afd2832cd8b087c4d9ab7011f481ee1e0721e78ea5dd609f3ab3f156d245"+
+			"d176bd8fd4ec60b4731c3918a2a72a0226c0cd119ec35b47e4d55884667f552a",
+		hex.EncodeToString(masterKey.PrivateKey))
+	require.Equal(t,
+		"23f7fdcd4a10c6cd2c7393ac61d877873e248f417634aa3d812af327ffe9d620",
+		hex.EncodeToString(masterKey.ChainCode))
+
+	// whitespaces are not important, passphrase is
+	sameMasterKey, err := NewMasterKeyFromMnemonic(
+		"  eight country switch draw meat scout mystery blade tip drift useless good keep usage\ttitle ", "")
+	require.NoError(t, err)
+	require.Equal(t, masterKey, sameMasterKey)
+
+	passphraseMasterKey, err := NewMasterKeyFromMnemonic(
+		"eight country switch draw meat scout mystery blade tip drift useless good keep usage title", "foo")
+	require.NoError(t, err)
+	require.NotEqual(t, masterKey.PrivateKey, passphraseMasterKey.PrivateKey)
+}
@@ -0,0 +1,347 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	DatumHashSize = 32
+
+	datumOptionHashType   = 0
+	datumOptionInlineType = 1
+)
+
+type PlutusScriptVersion byte
+
+const (
+	PlutusV1 PlutusScriptVersion = 1
+	PlutusV2 PlutusScriptVersion = 2
+	PlutusV3 PlutusScriptVersion = 3
+)
+
+type RedeemerTag byte
+
+const (
+	RedeemerTagSpend   RedeemerTag = 0
+	RedeemerTagMint    RedeemerTag = 1
+	RedeemerTagCert    RedeemerTag = 2
+	RedeemerTagWdrl    RedeemerTag = 3
+	RedeemerTagVote    RedeemerTag = 4
+	RedeemerTagPropose RedeemerTag = 5
+)
+
+// RedeemerPointer points to the item of the transaction (sorted input, policy id, certificate, ...)
+// the redeemer belongs to
+type RedeemerPointer struct {
+	Tag   RedeemerTag
+	Index uint32
+}
+
+func NewRedeemerPointer(tag RedeemerTag, index uint32) RedeemerPointer {
+	return RedeemerPointer{
+		Tag:   tag,
+		Index: index,
+	}
+}
+
+func (p RedeemerPointer) String() string {
+	return fmt.Sprintf("%d:%d", p.Tag, p.Index)
+}
+
+// PlutusScript is plutus script as it is in the witness set:
+// cbor bytestring of the flat encoded program (compiledCode in aiken's plutus.json)
+type PlutusScript struct {
+	Version PlutusScriptVersion `json:"version"`
+	Script  []byte              `json:"script"`
+}
+
+func NewPlutusScript(version PlutusScriptVersion, script []byte) PlutusScript {
+	return PlutusScript{
+		Version: version,
+		Script:  script,
+	}
+}
+
+// NewPlutusScriptFromEnvelope creates plutus script from cardano-cli text envelope
+// ({"type": "PlutusScriptV2", "cborHex": ...})
+func NewPlutusScriptFromEnvelope(envelope []byte) (PlutusScript, error) {
+	var data struct {
+		Type    string `json:"type"`
+		CborHex string `json:"cborHex"`
+	}
+
+	if err := json.Unmarshal(envelope, &data); err != nil {
+		return PlutusScript{}, err
+	}
+
+	var version PlutusScriptVersion
+
+	switch data.Type {
+	case "PlutusScriptV1":
+		version = PlutusV1
+	case "PlutusScriptV2":
+		version = PlutusV2
+	case "PlutusScriptV3":
+		version = PlutusV3
+	default:
+		return PlutusScript{}, fmt.Errorf("unsupported plutus script type: %s", data.Type)
+	}
+
+	cborBytes, err := hex.DecodeString(data.CborHex)
+	if err != nil {
+		return PlutusScript{}, err
+	}
+
+	// envelope contains serialized script (cbor bytestring) of the script from the witness set
+	var script []byte
+
+	if err := cbor.Unmarshal(cborBytes, &script); err != nil {
+		return PlutusScript{}, fmt.Errorf("invalid plutus script cbor: %w", err)
+	}
+
+	return NewPlutusScript(version, script), nil
+}
+
+// GetHash returns script hash: blake2b-224 of the language tag and the script
+func (ps PlutusScript) GetHash() (string, error) {
+	hash, err := ps.GetHashBytes()
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash), nil
+}
+
+func (ps PlutusScript) GetHashBytes() ([]byte, error) {
+	if ps.Version < PlutusV1 || ps.Version > PlutusV3 {
+		return nil, fmt.Errorf("unsupported plutus script version: %d", ps.Version)
+	}
+
+	return GetKeyHashBytes(append([]byte{byte(ps.Version)}, ps.Script...))
+}
+
+// ExUnits are execution units (memory and cpu steps) of the redeemer
+type ExUnits struct {
+	_      struct{} `cbor:",toarray"`
+	Memory uint64   `json:"memory"`
+	Steps  uint64   `json:"steps"`
+}
+
+func NewExUnits(memory, steps uint64) ExUnits {
+	return ExUnits{
+		Memory: memory,
+		Steps:  steps,
+	}
+}
+
+// Redeemer is argument of the plutus script. Data is cbor of the plutus data
+type Redeemer struct {
+	Data    []byte
+	ExUnits ExUnits
+}
+
+func NewRedeemer(data []byte, exUnits ExUnits) Redeemer {
+	return Redeemer{
+		Data:    data,
+		ExUnits: exUnits,
+	}
+}
+
+// txRawRedeemer is babbage redeemer format (still valid in conway): [tag, index, data, ex_units]
+type txRawRedeemer struct {
+	_       struct{} `cbor:",toarray"`
+	Tag     RedeemerTag
+	Index   uint32
+	Data    cbor.RawMessage
+	ExUnits ExUnits
+}
+
+// txRawRedeemerKey and txRawRedeemerValue are conway redeemers map format: {[tag, index]: [data, ex_units]}
+type txRawRedeemerKey struct {
+	_     struct{} `cbor:",toarray"`
+	Tag   RedeemerTag
+	Index uint32
+}
+
+type txRawRedeemerValue struct {
+	_       struct{} `cbor:",toarray"`
+	Data    cbor.RawMessage
+	ExUnits ExUnits
+}
+
+// newRedeemerPointerFromPurpose creates pointer from the evaluator validator purpose and index.
+// Both ogmios v6 (spend, publish, withdraw) and ogmios v5 (spend, certificate, withdrawal) names are accepted
+func newRedeemerPointerFromPurpose(purpose string, index uint32) (RedeemerPointer, error) {
+	switch purpose {
+	case "spend":
+		return NewRedeemerPointer(RedeemerTagSpend, index), nil
+	case "mint":
+		return NewRedeemerPointer(RedeemerTagMint, index), nil
+	case "publish", "certificate":
+		return NewRedeemerPointer(RedeemerTagCert, index), nil
+	case "withdraw", "withdrawal":
+		return NewRedeemerPointer(RedeemerTagWdrl, index), nil
+	case "vote":
+		return NewRedeemerPointer(RedeemerTagVote, index), nil
+	case "propose":
+		return NewRedeemerPointer(RedeemerTagPropose, index), nil
+	default:
+		return RedeemerPointer{}, fmt.Errorf("unknown redeemer purpose: %s", purpose)
+	}
+}
+
+// GetDatumHash returns hash of the datum (blake2b-256 of the plutus data cbor)
+func GetDatumHash(datum []byte) string {
+	hash := blake2b.Sum256(datum)
+
+	return hex.EncodeToString(hash[:])
+}
+
+// decodeInlineDatumHex decodes inline datum cbor returned by the providers (nil if there is no inline datum)
+func decodeInlineDatumHex(datum string) ([]byte, error) {
+	if datum == "" {
+		return nil, nil
+	}
+
+	result, err := hex.DecodeString(datum)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inline datum %s: %w", datum, err)
+	}
+
+	return result, nil
+}
+
+// getScriptDataHash calculates script integrity hash: blake2b-256(redeemers || datums || language views).
+// redeemers and datums must be exactly the same bytes as in the witness set
+func getScriptDataHash(
+	redeemers []byte, datums []byte, versions []PlutusScriptVersion, costModels map[string][]int64,
+) ([]byte, error) {
+	var data []byte
+
+	if len(redeemers) == 0 {
+		// conway: transaction with datums but without redeemers uses empty maps
+		data = append(append(append(data, 0xa0), datums...), 0xa0)
+	} else {
+		languageViews, err := getLanguageViews(versions, costModels)
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(append(append(data, redeemers...), datums...), languageViews...)
+	}
+
+	hash := blake2b.Sum256(data)
+
+	return hash[:], nil
+}
+
+// getLanguageViews encodes cost models of the used languages as the ledger does it for the script data hash.
+// PlutusV1 is encoded differently for historical reasons: key is serialized language id
+// and value is serialized indefinite list inside the bytestring
+func getLanguageViews(versions []PlutusScriptVersion, costModels map[string][]int64) ([]byte, error) {
+	uniqueVersions := map[PlutusScriptVersion]bool{}
+
+	for _, version := range versions {
+		uniqueVersions[version] = true
+	}
+
+	sortedVersions := make([]PlutusScriptVersion, 0, len(uniqueVersions))
+	for version := range uniqueVersions {
+		sortedVersions = append(sortedVersions, version)
+	}
+
+	// canonical order of the keys: shorter first (v1 key is two bytes long)
+	sort.Slice(sortedVersions, func(i, j int) bool {
+		if (sortedVersions[i] == PlutusV1) != (sortedVersions[j] == PlutusV1) {
+			return sortedVersions[j] == PlutusV1
+		}
+
+		return sortedVersions[i] < sortedVersions[j]
+	})
+
+	views := make(cborOrderedMap, len(sortedVersions))
+
+	for i, version := range sortedVersions {
+		costModelName := fmt.Sprintf("PlutusV%d", version)
+
+		costModel, exists := costModels[costModelName]
+		if !exists {
+			return nil, fmt.Errorf("cost model for %s not found in protocol parameters", costModelName)
+		}
+
+		languageID := uint64(version) - 1
+
+		if version != PlutusV1 {
+			views[i] = cborOrderedMapItem{Key: languageID, Value: costModel}
+
+			continue
+		}
+
+		costModelBytes := []byte{0x9f} // indefinite length array
+
+		for _, x := range costModel {
+			valueBytes, err := cbor.Marshal(x)
+			if err != nil {
+				return nil, err
+			}
+
+			costModelBytes = append(costModelBytes, valueBytes...)
+		}
+
+		views[i] = cborOrderedMapItem{
+			Key:   appendCborHead(nil, cborMajorTypeUnsignedInt, languageID),
+			Value: append(costModelBytes, 0xff),
+		}
+	}
+
+	return views.MarshalCBOR()
+}
+
+// getExUnitsFee calculates fee for the execution units of all redeemers from the witness set
+func getExUnitsFee(protocolParameters ProtocolParameters, redeemersRaw cbor.RawMessage) (uint64, error) {
+	if len(redeemersRaw) == 0 {
+		return 0, nil
+	}
+
+	exUnits, err := getRedeemersExUnits(redeemersRaw)
+	if err != nil {
+		return 0, err
+	}
+
+	return GetExUnitsFee(protocolParameters, exUnits), nil
+}
+
+// getRedeemersExUnits returns total execution units of the redeemers
+// (both babbage array format and conway map format are supported)
+func getRedeemersExUnits(redeemersRaw cbor.RawMessage) (total ExUnits, err error) {
+	var redeemers []txRawRedeemer
+
+	if errArr := cbor.Unmarshal(redeemersRaw, &redeemers); errArr == nil {
+		for _, x := range redeemers {
+			total.Memory += x.ExUnits.Memory
+			total.Steps += x.ExUnits.Steps
+		}
+
+		return total, nil
+	}
+
+	var redeemersMap map[txRawRedeemerKey]txRawRedeemerValue
+
+	if err := cbor.Unmarshal(redeemersRaw, &redeemersMap); err != nil {
+		return total, errors.New("invalid redeemers cbor")
+	}
+
+	for _, x := range redeemersMap {
+		total.Memory += x.ExUnits.Memory
+		total.Steps += x.ExUnits.Steps
+	}
+
+	return total, nil
+}
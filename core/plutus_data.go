@@ -0,0 +1,449 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	plutusDataBytesChunkSize = 64
+
+	cborMajorTypeNegativeInt = byte(1)
+	cborMajorTypeBytes       = byte(2)
+	cborMajorTypeArray       = byte(4)
+
+	cborTagPositiveBignum = 2
+	cborTagNegativeBignum = 3
+	cborTagConstrGeneral  = 102
+	cborTagConstrSmall    = 121  // constructors 0 - 6
+	cborTagConstrLarge    = 1280 // constructors 7 - 127
+
+	cborIndefinite = byte(31)
+	cborBreak      = byte(0xff)
+)
+
+var ErrInvalidPlutusData = errors.New("invalid plutus data")
+
+// PlutusData is on-chain data used by plutus scripts (datums and redeemers).
+// Implementations are PlutusConstr, PlutusMap, PlutusList, PlutusInteger and PlutusBytes
+type PlutusData interface {
+	MarshalCBOR() ([]byte, error)
+	isPlutusData()
+}
+
+// PlutusConstr is constructor (sum type variant) with the index and the fields
+type PlutusConstr struct {
+	Index  uint64
+	Fields []PlutusData
+}
+
+// PlutusMap is map with keys in the specified order
+type PlutusMap []PlutusMapItem
+
+type PlutusMapItem struct {
+	Key   PlutusData
+	Value PlutusData
+}
+
+type PlutusList []PlutusData
+
+// PlutusInteger is arbitrary precision integer
+type PlutusInteger struct {
+	Value *big.Int
+}
+
+type PlutusBytes []byte
+
+var (
+	_ PlutusData = PlutusConstr{}
+	_ PlutusData = PlutusMap{}
+	_ PlutusData = PlutusList{}
+	_ PlutusData = PlutusInteger{}
+	_ PlutusData = PlutusBytes{}
+)
+
+func NewPlutusConstr(index uint64, fields ...PlutusData) PlutusConstr {
+	return PlutusConstr{
+		Index:  index,
+		Fields: fields,
+	}
+}
+
+func NewPlutusInteger(value int64) PlutusInteger {
+	return PlutusInteger{Value: big.NewInt(value)}
+}
+
+func NewPlutusBigInteger(value *big.Int) PlutusInteger {
+	return PlutusInteger{Value: new(big.Int).Set(value)}
+}
+
+func (PlutusConstr) isPlutusData()  {}
+func (PlutusMap) isPlutusData()     {}
+func (PlutusList) isPlutusData()    {}
+func (PlutusInteger) isPlutusData() {}
+func (PlutusBytes) isPlutusData()   {}
+
+// MarshalCBOR encodes constructor with the compact tags (121-127, 1280-1400) if possible
+// and with the general tag 102 ([index, fields]) otherwise
+func (c PlutusConstr) MarshalCBOR() ([]byte, error) {
+	fields, err := PlutusList(c.Fields).MarshalCBOR()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c.Index < 7:
+		return append(appendCborHead(nil, cborMajorTypeTag, cborTagConstrSmall+c.Index), fields...), nil
+	case c.Index < 128:
+		return append(appendCborHead(nil, cborMajorTypeTag, cborTagConstrLarge+c.Index-7), fields...), nil
+	default:
+		result := appendCborHead(nil, cborMajorTypeTag, cborTagConstrGeneral)
+		result = appendCborHead(result, cborMajorTypeArray, 2)
+		result = appendCborHead(result, cborMajorTypeUnsignedInt, c.Index)
+
+		return append(result, fields...), nil
+	}
+}
+
+// MarshalCBOR encodes map with the definite length
+func (m PlutusMap) MarshalCBOR() ([]byte, error) {
+	result := appendCborHead(nil, cborMajorTypeMap, uint64(len(m)))
+
+	for _, item := range m {
+		if item.Key == nil || item.Value == nil {
+			return nil, fmt.Errorf("%w: nil map item", ErrInvalidPlutusData)
+		}
+
+		keyBytes, err := item.Key.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+
+		valueBytes, err := item.Value.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(append(result, keyBytes...), valueBytes...)
+	}
+
+	return result, nil
+}
+
+// MarshalCBOR encodes list as the ledger does it: empty list with definite length
+// and non empty list with indefinite length
+func (l PlutusList) MarshalCBOR() ([]byte, error) {
+	if len(l) == 0 {
+		return appendCborHead(nil, cborMajorTypeArray, 0), nil
+	}
+
+	result := []byte{cborMajorTypeArray<<5 | cborIndefinite}
+
+	for _, item := range l {
+		if item == nil {
+			return nil, fmt.Errorf("%w: nil list item", ErrInvalidPlutusData)
+		}
+
+		itemBytes, err := item.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, itemBytes...)
+	}
+
+	return append(result, cborBreak), nil
+}
+
+// MarshalCBOR encodes integer as cbor integer if it fits into 64 bits and as bignum otherwise
+func (i PlutusInteger) MarshalCBOR() ([]byte, error) {
+	value := i.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	if value.Sign() >= 0 {
+		if value.IsUint64() {
+			return appendCborHead(nil, cborMajorTypeUnsignedInt, value.Uint64()), nil
+		}
+
+		result := appendCborHead(nil, cborMajorTypeTag, cborTagPositiveBignum)
+
+		return appendPlutusBytes(result, value.Bytes()), nil
+	}
+
+	// negative integer n is encoded as -1 - n
+	encoded := new(big.Int).Sub(new(big.Int).Neg(value), big.NewInt(1))
+	if encoded.IsUint64() {
+		return appendCborHead(nil, cborMajorTypeNegativeInt, encoded.Uint64()), nil
+	}
+
+	result := appendCborHead(nil, cborMajorTypeTag, cborTagNegativeBignum)
+
+	return appendPlutusBytes(result, encoded.Bytes()), nil
+}
+
+// MarshalCBOR encodes bytes longer than 64 bytes as indefinite bytestring of 64 bytes chunks
+func (b PlutusBytes) MarshalCBOR() ([]byte, error) {
+	return appendPlutusBytes(nil, b), nil
+}
+
+func appendPlutusBytes(dst []byte, value []byte) []byte {
+	if len(value) <= plutusDataBytesChunkSize {
+		return append(appendCborHead(dst, cborMajorTypeBytes, uint64(len(value))), value...)
+	}
+
+	dst = append(dst, cborMajorTypeBytes<<5|cborIndefinite)
+
+	for len(value) > 0 {
+		chunk := value[:min(len(value), plutusDataBytesChunkSize)]
+		dst = append(appendCborHead(dst, cborMajorTypeBytes, uint64(len(chunk))), chunk...)
+		value = value[len(chunk):]
+	}
+
+	return append(dst, cborBreak)
+}
+
+// DecodePlutusData decodes plutus data from cbor. Both definite and indefinite lengths are accepted
+func DecodePlutusData(data []byte) (PlutusData, error) {
+	decoder := plutusDataDecoder{data: data}
+
+	result, err := decoder.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	if decoder.pos != len(data) {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidPlutusData, len(data)-decoder.pos)
+	}
+
+	return result, nil
+}
+
+type plutusDataDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *plutusDataDecoder) decode() (PlutusData, error) {
+	majorType, value, isIndefinite, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+
+	if isIndefinite && majorType != cborMajorTypeBytes && majorType != cborMajorTypeArray &&
+		majorType != cborMajorTypeMap {
+		return nil, fmt.Errorf("%w: indefinite length for major type %d", ErrInvalidPlutusData, majorType)
+	}
+
+	switch majorType {
+	case cborMajorTypeUnsignedInt:
+		return PlutusInteger{Value: new(big.Int).SetUint64(value)}, nil
+	case cborMajorTypeNegativeInt:
+		n := new(big.Int).SetUint64(value)
+
+		return PlutusInteger{Value: n.Neg(n).Sub(n, big.NewInt(1))}, nil
+	case cborMajorTypeBytes:
+		bytes, err := d.readBytes(value, isIndefinite)
+		if err != nil {
+			return nil, err
+		}
+
+		return PlutusBytes(bytes), nil
+	case cborMajorTypeArray:
+		return d.readList(value, isIndefinite)
+	case cborMajorTypeMap:
+		return d.readMap(value, isIndefinite)
+	case cborMajorTypeTag:
+		return d.readTag(value)
+	default:
+		return nil, fmt.Errorf("%w: unsupported cbor major type %d", ErrInvalidPlutusData, majorType)
+	}
+}
+
+func (d *plutusDataDecoder) readTag(tag uint64) (PlutusData, error) {
+	switch {
+	case tag >= cborTagConstrSmall && tag < cborTagConstrSmall+7:
+		fields, err := d.readFields()
+
+		return NewPlutusConstr(tag-cborTagConstrSmall, fields...), err
+	case tag >= cborTagConstrLarge && tag < cborTagConstrLarge+121:
+		fields, err := d.readFields()
+
+		return NewPlutusConstr(tag-cborTagConstrLarge+7, fields...), err
+	case tag == cborTagConstrGeneral:
+		majorType, length, isIndefinite, err := d.readHead()
+		if err != nil {
+			return nil, err
+		} else if majorType != cborMajorTypeArray || isIndefinite || length != 2 {
+			return nil, fmt.Errorf("%w: general constructor must be [index, fields]", ErrInvalidPlutusData)
+		}
+
+		majorType, index, _, err := d.readHead()
+		if err != nil {
+			return nil, err
+		} else if majorType != cborMajorTypeUnsignedInt {
+			return nil, fmt.Errorf("%w: invalid constructor index", ErrInvalidPlutusData)
+		}
+
+		fields, err := d.readFields()
+
+		return NewPlutusConstr(index, fields...), err
+	case tag == cborTagPositiveBignum || tag == cborTagNegativeBignum:
+		majorType, length, isIndefinite, err := d.readHead()
+		if err != nil {
+			return nil, err
+		} else if majorType != cborMajorTypeBytes {
+			return nil, fmt.Errorf("%w: bignum must be bytestring", ErrInvalidPlutusData)
+		}
+
+		bytes, err := d.readBytes(length, isIndefinite)
+		if err != nil {
+			return nil, err
+		}
+
+		n := new(big.Int).SetBytes(bytes)
+		if tag == cborTagNegativeBignum {
+			n.Neg(n).Sub(n, big.NewInt(1))
+		}
+
+		return PlutusInteger{Value: n}, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported cbor tag %d", ErrInvalidPlutusData, tag)
+	}
+}
+
+func (d *plutusDataDecoder) readFields() ([]PlutusData, error) {
+	majorType, length, isIndefinite, err := d.readHead()
+	if err != nil {
+		return nil, err
+	} else if majorType != cborMajorTypeArray {
+		return nil, fmt.Errorf("%w: constructor fields must be list", ErrInvalidPlutusData)
+	}
+
+	list, err := d.readList(length, isIndefinite)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (d *plutusDataDecoder) readList(length uint64, isIndefinite bool) (PlutusList, error) {
+	result := PlutusList{}
+
+	for i := uint64(0); isIndefinite || i < length; i++ {
+		if isIndefinite && d.readBreak() {
+			break
+		}
+
+		item, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+func (d *plutusDataDecoder) readMap(length uint64, isIndefinite bool) (PlutusMap, error) {
+	result := PlutusMap{}
+
+	for i := uint64(0); isIndefinite || i < length; i++ {
+		if isIndefinite && d.readBreak() {
+			break
+		}
+
+		key, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, PlutusMapItem{Key: key, Value: value})
+	}
+
+	return result, nil
+}
+
+func (d *plutusDataDecoder) readBytes(length uint64, isIndefinite bool) ([]byte, error) {
+	if !isIndefinite {
+		if uint64(len(d.data)-d.pos) < length {
+			return nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidPlutusData)
+		}
+
+		result := d.data[d.pos : d.pos+int(length)] //nolint:gosec
+		d.pos += int(length)                        //nolint:gosec
+
+		return append([]byte{}, result...), nil
+	}
+
+	result := []byte{}
+
+	for !d.readBreak() {
+		majorType, chunkLength, isChunkIndefinite, err := d.readHead()
+		if err != nil {
+			return nil, err
+		} else if majorType != cborMajorTypeBytes || isChunkIndefinite {
+			return nil, fmt.Errorf("%w: invalid bytestring chunk", ErrInvalidPlutusData)
+		}
+
+		chunk, err := d.readBytes(chunkLength, false)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, chunk...)
+	}
+
+	return result, nil
+}
+
+// readBreak consumes break byte if it is the next one
+func (d *plutusDataDecoder) readBreak() bool {
+	if d.pos < len(d.data) && d.data[d.pos] == cborBreak {
+		d.pos++
+
+		return true
+	}
+
+	return false
+}
+
+func (d *plutusDataDecoder) readHead() (majorType byte, value uint64, isIndefinite bool, err error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, false, fmt.Errorf("%w: unexpected end of data", ErrInvalidPlutusData)
+	}
+
+	majorType, info := d.data[d.pos]>>5, d.data[d.pos]&0x1f
+	d.pos++
+
+	switch {
+	case info < 24:
+		return majorType, uint64(info), false, nil
+	case info == cborIndefinite:
+		return majorType, 0, true, nil
+	case info > 27:
+		return 0, 0, false, fmt.Errorf("%w: invalid additional info %d", ErrInvalidPlutusData, info)
+	}
+
+	size := 1 << (info - 24)
+	if len(d.data)-d.pos < size {
+		return 0, 0, false, fmt.Errorf("%w: unexpected end of data", ErrInvalidPlutusData)
+	}
+
+	for _, b := range d.data[d.pos : d.pos+size] {
+		value = value<<8 | uint64(b)
+	}
+
+	d.pos += size
+
+	return majorType, value, false, nil
+}
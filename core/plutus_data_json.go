@@ -0,0 +1,138 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// NewPlutusDataFromJSON creates plutus data from the detailed json schema used by cardano-cli and cardano-node:
+// {"constructor": 0, "fields": [...]}, {"map": [{"k": ..., "v": ...}]}, {"list": [...]}, {"int": 1}, {"bytes": "hex"}
+func NewPlutusDataFromJSON(data []byte) (PlutusData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // integers can be larger than 64 bits
+
+	var value map[string]interface{}
+
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPlutusData, err)
+	}
+
+	return newPlutusDataFromJSONValue(value)
+}
+
+func newPlutusDataFromJSONValue(value interface{}) (PlutusData, error) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: json object expected: %v", ErrInvalidPlutusData, value)
+	}
+
+	getList := func(key string) ([]PlutusData, error) {
+		items, ok := object[key].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s must be array", ErrInvalidPlutusData, key)
+		}
+
+		result := make([]PlutusData, len(items))
+
+		for i, item := range items {
+			data, err := newPlutusDataFromJSONValue(item)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = data
+		}
+
+		return result, nil
+	}
+
+	switch {
+	case object["constructor"] != nil:
+		index, err := getPlutusJSONInteger(object["constructor"])
+		if err != nil || !index.IsUint64() {
+			return nil, fmt.Errorf("%w: invalid constructor: %v", ErrInvalidPlutusData, object["constructor"])
+		}
+
+		fields, err := getList("fields")
+		if err != nil {
+			return nil, err
+		} else if len(fields) == 0 {
+			return NewPlutusConstr(index.Uint64()), nil
+		}
+
+		return NewPlutusConstr(index.Uint64(), fields...), nil
+	case object["map"] != nil:
+		items, ok := object["map"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: map must be array", ErrInvalidPlutusData)
+		}
+
+		result := make(PlutusMap, len(items))
+
+		for i, item := range items {
+			kv, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%w: invalid map item: %v", ErrInvalidPlutusData, item)
+			}
+
+			key, err := newPlutusDataFromJSONValue(kv["k"])
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := newPlutusDataFromJSONValue(kv["v"])
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = PlutusMapItem{Key: key, Value: value}
+		}
+
+		return result, nil
+	case object["list"] != nil:
+		items, err := getList("list")
+		if err != nil {
+			return nil, err
+		}
+
+		return PlutusList(items), nil
+	case object["int"] != nil:
+		value, err := getPlutusJSONInteger(object["int"])
+		if err != nil {
+			return nil, err
+		}
+
+		return PlutusInteger{Value: value}, nil
+	case object["bytes"] != nil:
+		hexValue, ok := object["bytes"].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: bytes must be hex string", ErrInvalidPlutusData)
+		}
+
+		value, err := hex.DecodeString(hexValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPlutusData, err)
+		}
+
+		return PlutusBytes(value), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown json object: %v", ErrInvalidPlutusData, object)
+	}
+}
+
+func getPlutusJSONInteger(value interface{}) (*big.Int, error) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("%w: integer expected: %v", ErrInvalidPlutusData, value)
+	}
+
+	result, ok := new(big.Int).SetString(number.String(), 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid integer: %s", ErrInvalidPlutusData, number)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,457 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	plutusTagName         = "plutus"
+	plutusTagConstrPrefix = "constr="
+)
+
+var (
+	plutusDataType = reflect.TypeOf((*PlutusData)(nil)).Elem()
+	bigIntType     = reflect.TypeOf(big.Int{})
+)
+
+// MarshalPlutusData converts go value to plutus data (see PlutusDataFromValue) and encodes it to cbor
+func MarshalPlutusData(v interface{}) ([]byte, error) {
+	data, err := PlutusDataFromValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.MarshalCBOR()
+}
+
+// UnmarshalPlutusData decodes plutus data cbor into go value pointed by v (see PlutusDataToValue)
+func UnmarshalPlutusData(data []byte, v interface{}) error {
+	plutusData, err := DecodePlutusData(data)
+	if err != nil {
+		return err
+	}
+
+	return PlutusDataToValue(plutusData, v)
+}
+
+// PlutusDataFromValue converts go value to plutus data:
+//   - struct is constructor. Index is set with the tag on the blank field: _ struct{} `plutus:"constr=1"`.
+//     Fields are in the declaration order unless all of them have position tag `plutus:"0"`.
+//     Fields with `plutus:"-"` and unexported fields are skipped
+//   - bool is constructor 0 (false) or 1 (true) without fields
+//   - integers and big.Int are integers, []byte, [N]byte and string are bytes
+//   - slices and arrays are lists, maps are maps (sorted by the cbor of the keys)
+//   - pointers are dereferenced (nil pointers are errors) and PlutusData values are used as they are
+func PlutusDataFromValue(v interface{}) (PlutusData, error) {
+	if v == nil {
+		return nil, fmt.Errorf("%w: nil value", ErrInvalidPlutusData)
+	}
+
+	return plutusDataFromReflectValue(reflect.ValueOf(v))
+}
+
+// PlutusDataToValue converts plutus data into go value pointed by v. See PlutusDataFromValue for the rules
+func PlutusDataToValue(data PlutusData, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("%w: non nil pointer expected", ErrInvalidPlutusData)
+	}
+
+	return plutusDataToReflectValue(data, rv.Elem())
+}
+
+//nolint:gocyclo
+func plutusDataFromReflectValue(rv reflect.Value) (PlutusData, error) {
+	// pointers to plutus data (including nil ones) are handled by the pointer case below
+	if rv.Type().Implements(plutusDataType) && rv.Kind() != reflect.Pointer {
+		if rv.Kind() == reflect.Interface && rv.IsNil() {
+			return nil, fmt.Errorf("%w: nil value", ErrInvalidPlutusData)
+		}
+
+		return rv.Interface().(PlutusData), nil //nolint:forcetypeassert
+	}
+
+	if rv.Type() == bigIntType {
+		value := rv.Interface().(big.Int) //nolint:forcetypeassert,govet
+
+		return NewPlutusBigInteger(&value), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil, fmt.Errorf("%w: nil value", ErrInvalidPlutusData)
+		}
+
+		return plutusDataFromReflectValue(rv.Elem())
+	case reflect.Bool:
+		if rv.Bool() {
+			return NewPlutusConstr(1), nil
+		}
+
+		return NewPlutusConstr(0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NewPlutusInteger(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return PlutusInteger{Value: new(big.Int).SetUint64(rv.Uint())}, nil
+	case reflect.String:
+		return PlutusBytes(rv.String()), nil
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			result := make(PlutusBytes, rv.Len())
+			reflect.Copy(reflect.ValueOf([]byte(result)), rv)
+
+			return result, nil
+		}
+
+		result := make(PlutusList, rv.Len())
+
+		for i := range result {
+			item, err := plutusDataFromReflectValue(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = item
+		}
+
+		return result, nil
+	case reflect.Map:
+		return plutusMapFromReflectValue(rv)
+	case reflect.Struct:
+		index, fields, err := getPlutusStructInfo(rv.Type())
+		if err != nil {
+			return nil, err
+		}
+
+		result := NewPlutusConstr(index)
+
+		for _, field := range fields {
+			item, err := plutusDataFromReflectValue(rv.Field(field))
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", rv.Type().Name(), rv.Type().Field(field).Name, err)
+			}
+
+			result.Fields = append(result.Fields, item)
+		}
+
+		return result, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %s", ErrInvalidPlutusData, rv.Type())
+	}
+}
+
+func plutusMapFromReflectValue(rv reflect.Value) (PlutusData, error) {
+	type encodedItem struct {
+		keyBytes []byte
+		item     PlutusMapItem
+	}
+
+	items := make([]encodedItem, 0, rv.Len())
+	iter := rv.MapRange()
+
+	for iter.Next() {
+		key, err := plutusDataFromReflectValue(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := plutusDataFromReflectValue(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		keyBytes, err := key.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, encodedItem{
+			keyBytes: keyBytes,
+			item:     PlutusMapItem{Key: key, Value: value},
+		})
+	}
+
+	// go maps are not ordered so the keys are sorted to get deterministic cbor
+	sort.Slice(items, func(i, j int) bool {
+		return bytes.Compare(items[i].keyBytes, items[j].keyBytes) < 0
+	})
+
+	result := make(PlutusMap, len(items))
+	for i, x := range items {
+		result[i] = x.item
+	}
+
+	return result, nil
+}
+
+//nolint:gocyclo
+func plutusDataToReflectValue(data PlutusData, rv reflect.Value) error {
+	if data == nil {
+		return fmt.Errorf("%w: nil value", ErrInvalidPlutusData)
+	}
+
+	// PlutusData interface and concrete plutus data types (PlutusInteger, PlutusConstr, ...) are set as they are
+	if rv.Type().Implements(plutusDataType) && rv.Kind() != reflect.Pointer {
+		if !reflect.TypeOf(data).AssignableTo(rv.Type()) {
+			return newPlutusDataTypeError(data, rv.Type())
+		}
+
+		rv.Set(reflect.ValueOf(data))
+
+		return nil
+	}
+
+	if rv.Type() == bigIntType {
+		integer, ok := data.(PlutusInteger)
+		if !ok {
+			return newPlutusDataTypeError(data, rv.Type())
+		}
+
+		rv.Set(reflect.ValueOf(*NewPlutusBigInteger(getPlutusIntegerValue(integer)).Value))
+
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+
+		return plutusDataToReflectValue(data, rv.Elem())
+	case reflect.Bool:
+		constr, ok := data.(PlutusConstr)
+		if !ok || constr.Index > 1 || len(constr.Fields) > 0 {
+			return newPlutusDataTypeError(data, rv.Type())
+		}
+
+		rv.SetBool(constr.Index == 1)
+
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		integer, ok := data.(PlutusInteger)
+		if !ok {
+			return newPlutusDataTypeError(data, rv.Type())
+		}
+
+		value := getPlutusIntegerValue(integer)
+		if !value.IsInt64() || rv.OverflowInt(value.Int64()) {
+			return fmt.Errorf("%w: integer %s overflows %s", ErrInvalidPlutusData, value, rv.Type())
+		}
+
+		rv.SetInt(value.Int64())
+
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		integer, ok := data.(PlutusInteger)
+		if !ok {
+			return newPlutusDataTypeError(data, rv.Type())
+		}
+
+		value := getPlutusIntegerValue(integer)
+		if !value.IsUint64() || rv.OverflowUint(value.Uint64()) {
+			return fmt.Errorf("%w: integer %s overflows %s", ErrInvalidPlutusData, value, rv.Type())
+		}
+
+		rv.SetUint(value.Uint64())
+
+		return nil
+	case reflect.String:
+		bytes, ok := data.(PlutusBytes)
+		if !ok {
+			return newPlutusDataTypeError(data, rv.Type())
+		}
+
+		rv.SetString(string(bytes))
+
+		return nil
+	case reflect.Slice, reflect.Array:
+		return plutusListToReflectValue(data, rv)
+	case reflect.Map:
+		plutusMap, ok := data.(PlutusMap)
+		if !ok {
+			return newPlutusDataTypeError(data, rv.Type())
+		}
+
+		result := reflect.MakeMapWithSize(rv.Type(), len(plutusMap))
+
+		for _, item := range plutusMap {
+			key, value := reflect.New(rv.Type().Key()).Elem(), reflect.New(rv.Type().Elem()).Elem()
+
+			if err := plutusDataToReflectValue(item.Key, key); err != nil {
+				return err
+			}
+
+			if err := plutusDataToReflectValue(item.Value, value); err != nil {
+				return err
+			}
+
+			result.SetMapIndex(key, value)
+		}
+
+		rv.Set(result)
+
+		return nil
+	case reflect.Struct:
+		constr, ok := data.(PlutusConstr)
+		if !ok {
+			return newPlutusDataTypeError(data, rv.Type())
+		}
+
+		index, fields, err := getPlutusStructInfo(rv.Type())
+		if err != nil {
+			return err
+		}
+
+		if constr.Index != index || len(constr.Fields) != len(fields) {
+			return fmt.Errorf("%w: constructor %d with %d fields can not be decoded into %s",
+				ErrInvalidPlutusData, constr.Index, len(constr.Fields), rv.Type())
+		}
+
+		for i, field := range fields {
+			if err := plutusDataToReflectValue(constr.Fields[i], rv.Field(field)); err != nil {
+				return fmt.Errorf("%s.%s: %w", rv.Type().Name(), rv.Type().Field(field).Name, err)
+			}
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported type %s", ErrInvalidPlutusData, rv.Type())
+	}
+}
+
+func plutusListToReflectValue(data PlutusData, rv reflect.Value) error {
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		bytes, ok := data.(PlutusBytes)
+		if !ok {
+			return newPlutusDataTypeError(data, rv.Type())
+		}
+
+		if rv.Kind() == reflect.Array {
+			if len(bytes) != rv.Len() {
+				return fmt.Errorf("%w: %d bytes can not be decoded into %s", ErrInvalidPlutusData, len(bytes), rv.Type())
+			}
+		} else {
+			rv.Set(reflect.MakeSlice(rv.Type(), len(bytes), len(bytes)))
+		}
+
+		reflect.Copy(rv, reflect.ValueOf([]byte(bytes)))
+
+		return nil
+	}
+
+	list, ok := data.(PlutusList)
+	if !ok {
+		return newPlutusDataTypeError(data, rv.Type())
+	}
+
+	if rv.Kind() == reflect.Array {
+		if len(list) != rv.Len() {
+			return fmt.Errorf("%w: list of %d items can not be decoded into %s", ErrInvalidPlutusData, len(list), rv.Type())
+		}
+	} else {
+		rv.Set(reflect.MakeSlice(rv.Type(), len(list), len(list)))
+	}
+
+	for i, item := range list {
+		if err := plutusDataToReflectValue(item, rv.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getPlutusStructInfo returns constructor index and indexes of the struct fields in the plutus order
+func getPlutusStructInfo(structType reflect.Type) (uint64, []int, error) {
+	type fieldInfo struct {
+		index    int
+		position int
+	}
+
+	var (
+		constrIndex  uint64
+		fields       []fieldInfo
+		hasPositions = 0
+	)
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := field.Tag.Get(plutusTagName)
+
+		if field.Name == "_" {
+			if value, found := strings.CutPrefix(tag, plutusTagConstrPrefix); found {
+				index, err := strconv.ParseUint(value, 10, 64)
+				if err != nil {
+					return 0, nil, fmt.Errorf("%w: invalid constructor index in %s: %s",
+						ErrInvalidPlutusData, structType, tag)
+				}
+
+				constrIndex = index
+			}
+
+			continue
+		}
+
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+
+		position := len(fields)
+
+		if tag != "" {
+			value, err := strconv.Atoi(tag)
+			if err != nil {
+				return 0, nil, fmt.Errorf("%w: invalid field position %s.%s: %s",
+					ErrInvalidPlutusData, structType, field.Name, tag)
+			}
+
+			position = value
+			hasPositions++
+		}
+
+		fields = append(fields, fieldInfo{index: i, position: position})
+	}
+
+	if hasPositions > 0 {
+		if hasPositions != len(fields) {
+			return 0, nil, fmt.Errorf("%w: all fields of %s must have position", ErrInvalidPlutusData, structType)
+		}
+
+		sort.Slice(fields, func(i, j int) bool {
+			return fields[i].position < fields[j].position
+		})
+	}
+
+	result := make([]int, len(fields))
+
+	for i, field := range fields {
+		if field.position != i {
+			return 0, nil, fmt.Errorf("%w: field positions of %s must be 0..%d",
+				ErrInvalidPlutusData, structType, len(fields)-1)
+		}
+
+		result[i] = field.index
+	}
+
+	return constrIndex, result, nil
+}
+
+func getPlutusIntegerValue(integer PlutusInteger) *big.Int {
+	if integer.Value == nil {
+		return new(big.Int)
+	}
+
+	return integer.Value
+}
+
+func newPlutusDataTypeError(data PlutusData, targetType reflect.Type) error {
+	return fmt.Errorf("%w: %T can not be decoded into %s", ErrInvalidPlutusData, data, targetType)
+}
@@ -0,0 +1,233 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlutusData_MarshalCBOR(t *testing.T) {
+	t.Parallel()
+
+	bigValue, _ := new(big.Int).SetString("18446744073709551616", 10) // 2^64
+
+	cases := []struct {
+		name     string
+		data     PlutusData
+		expected string
+	}{
+		{"constr 0 empty", NewPlutusConstr(0), "d87980"},
+		{"constr 0 with field", NewPlutusConstr(0, NewPlutusInteger(1)), "d8799f01ff"},
+		{"constr 6", NewPlutusConstr(6), "d87f80"},
+		{"constr 7", NewPlutusConstr(7), "d9050080"},
+		{"constr 127", NewPlutusConstr(127), "d9057880"},
+		{"constr 128", NewPlutusConstr(128), "d86682188080"},
+		{"empty list", PlutusList{}, "80"},
+		{"list", PlutusList{NewPlutusInteger(1), NewPlutusInteger(2)}, "9f0102ff"},
+		{"map", PlutusMap{{Key: NewPlutusInteger(1), Value: NewPlutusInteger(2)}}, "a10102"},
+		{"negative", NewPlutusInteger(-1), "20"},
+		{"max uint64", PlutusInteger{Value: new(big.Int).SetUint64(^uint64(0))}, "1bffffffffffffffff"},
+		{"bignum", NewPlutusBigInteger(bigValue), "c249010000000000000000"},
+		{"min negint", NewPlutusBigInteger(new(big.Int).Neg(bigValue)), "3bffffffffffffffff"},
+		{"negative bignum", NewPlutusBigInteger(new(big.Int).Sub(new(big.Int).Neg(bigValue), big.NewInt(1))),
+			"c349010000000000000000"},
+		{"bytes", PlutusBytes{0xab, 0xcd}, "42abcd"},
+		{"long bytes", PlutusBytes(bytes.Repeat([]byte{0x01}, 65)),
+			"5f5840" + hex.EncodeToString(bytes.Repeat([]byte{0x01}, 64)) + "4101ff"},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := c.data.MarshalCBOR()
+			require.NoError(t, err)
+			require.Equal(t, c.expected, hex.EncodeToString(encoded))
+
+			decoded, err := DecodePlutusData(encoded)
+			require.NoError(t, err)
+
+			reencoded, err := decoded.MarshalCBOR()
+			require.NoError(t, err)
+			require.Equal(t, encoded, reencoded)
+		})
+	}
+}
+
+func TestDecodePlutusData(t *testing.T) {
+	t.Parallel()
+
+	// definite length list and map with nested constructor
+	data, err := DecodePlutusData([]byte{0x82, 0xa1, 0x41, 0x01, 0xd8, 0x7a, 0x80, 0x38, 0x63})
+	require.NoError(t, err)
+	require.Equal(t, PlutusList{
+		PlutusMap{{Key: PlutusBytes{0x01}, Value: NewPlutusConstr(1)}},
+		NewPlutusInteger(-100),
+	}, data)
+
+	// indefinite map and general constructor
+	data, err = DecodePlutusData([]byte{0xbf, 0x01, 0xd8, 0x66, 0x82, 0x18, 0x80, 0x81, 0x02, 0xff})
+	require.NoError(t, err)
+	require.Equal(t, PlutusMap{
+		{Key: NewPlutusInteger(1), Value: NewPlutusConstr(128, NewPlutusInteger(2))},
+	}, data)
+
+	for _, invalid := range []string{"", "d87980ff", "9f01", "f6", "d87b01", "1f", "5f01ff"} {
+		_, err := DecodePlutusData(mustHexDecode(t, invalid))
+		require.ErrorIs(t, err, ErrInvalidPlutusData, invalid)
+	}
+}
+
+func TestMarshalPlutusData(t *testing.T) {
+	t.Parallel()
+
+	type credential struct {
+		_    struct{} `plutus:"constr=1"`
+		Hash [2]byte
+	}
+
+	type datum struct {
+		Owner    []byte
+		Deadline int64
+		Amounts  map[string]uint64
+		Cred     *credential
+		Active   bool
+		Skipped  string `plutus:"-"`
+		internal int
+	}
+
+	type reordered struct {
+		_ struct{} `plutus:"constr=8"`
+		A int      `plutus:"1"`
+		B int      `plutus:"0"`
+	}
+
+	value := datum{
+		Owner:    []byte{0xaa},
+		Deadline: -5,
+		Amounts:  map[string]uint64{"b": 2, "a": 1},
+		Cred:     &credential{Hash: [2]byte{1, 2}},
+		Active:   true,
+		Skipped:  "x",
+		internal: 1,
+	}
+
+	encoded, err := MarshalPlutusData(value)
+	require.NoError(t, err)
+	require.Equal(t, "d8799f"+"41aa"+"24"+"a2416101416202"+"d87a9f420102ff"+"d87a80"+"ff", hex.EncodeToString(encoded))
+
+	var decoded datum
+
+	require.NoError(t, UnmarshalPlutusData(encoded, &decoded))
+
+	value.Skipped, value.internal = "", 0
+	require.Equal(t, value, decoded)
+
+	encoded, err = MarshalPlutusData(reordered{A: 1, B: 2})
+	require.NoError(t, err)
+	require.Equal(t, "d905019f0201ff", hex.EncodeToString(encoded))
+
+	var decodedReordered reordered
+
+	require.NoError(t, UnmarshalPlutusData(encoded, &decodedReordered))
+	require.Equal(t, reordered{A: 1, B: 2}, decodedReordered)
+
+	// wrong constructor index
+	require.ErrorIs(t, UnmarshalPlutusData([]byte{0xd8, 0x79, 0x9f, 0x02, 0x01, 0xff}, &decodedReordered),
+		ErrInvalidPlutusData)
+
+	// overflow
+	var small int8
+
+	require.ErrorIs(t, UnmarshalPlutusData([]byte{0x19, 0x01, 0x00}, &small), ErrInvalidPlutusData)
+
+	// PlutusData values are used as they are
+	encoded, err = MarshalPlutusData([]PlutusData{NewPlutusConstr(3)})
+	require.NoError(t, err)
+	require.Equal(t, "9fd87c80ff", hex.EncodeToString(encoded))
+
+	_, err = MarshalPlutusData(struct{ F float64 }{})
+	require.ErrorIs(t, err, ErrInvalidPlutusData)
+
+	// nil concrete plutus data pointer
+	_, err = MarshalPlutusData(struct{ C *PlutusConstr }{})
+	require.ErrorIs(t, err, ErrInvalidPlutusData)
+
+	// fields of concrete plutus data types
+	type plutusFields struct {
+		Integer PlutusInteger
+		Constr  *PlutusConstr
+		Map     PlutusMap
+		Bytes   PlutusBytes
+		List    PlutusList
+		Any     PlutusData
+	}
+
+	constr := NewPlutusConstr(2, PlutusBytes{0x01})
+	fieldsValue := plutusFields{
+		Integer: NewPlutusInteger(-7),
+		Constr:  &constr,
+		Map:     PlutusMap{{Key: PlutusBytes{0x02}, Value: NewPlutusInteger(1)}},
+		Bytes:   PlutusBytes{0x03},
+		List:    PlutusList{NewPlutusInteger(4)},
+		Any:     NewPlutusConstr(0),
+	}
+
+	encoded, err = MarshalPlutusData(fieldsValue)
+	require.NoError(t, err)
+
+	var decodedFields plutusFields
+
+	require.NoError(t, UnmarshalPlutusData(encoded, &decodedFields))
+
+	reencoded, err := MarshalPlutusData(decodedFields)
+	require.NoError(t, err)
+	require.Equal(t, encoded, reencoded)
+	require.Equal(t, constr.Index, decodedFields.Constr.Index)
+	require.Equal(t, fieldsValue.Bytes, decodedFields.Bytes)
+
+	// plutus data of the other type
+	require.ErrorIs(t, UnmarshalPlutusData(mustHexDecode(t, "d8799f4101ff"), &struct{ I PlutusInteger }{}),
+		ErrInvalidPlutusData)
+}
+
+func mustHexDecode(t *testing.T, s string) []byte {
+	t.Helper()
+
+	result, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return result
+}
+
+func TestNewPlutusDataFromJSON(t *testing.T) {
+	t.Parallel()
+
+	data, err := NewPlutusDataFromJSON([]byte(`{"constructor": 1, "fields": [
+		{"int": -5},
+		{"int": 18446744073709551616},
+		{"bytes": "abcd"},
+		{"list": [{"int": 1}]},
+		{"map": [{"k": {"bytes": ""}, "v": {"constructor": 0, "fields": []}}]}]}`))
+	require.NoError(t, err)
+
+	bigValue, _ := new(big.Int).SetString("18446744073709551616", 10)
+
+	require.Equal(t, NewPlutusConstr(1,
+		NewPlutusInteger(-5),
+		PlutusInteger{Value: bigValue},
+		PlutusBytes{0xab, 0xcd},
+		PlutusList{NewPlutusInteger(1)},
+		PlutusMap{{Key: PlutusBytes{}, Value: NewPlutusConstr(0)}},
+	), data)
+
+	for _, invalid := range []string{`[]`, `{"int": "1"}`, `{"bytes": "xy"}`, `{"unknown": 1}`, `{"constructor": 0}`} {
+		_, err := NewPlutusDataFromJSON([]byte(invalid))
+		require.ErrorIs(t, err, ErrInvalidPlutusData, invalid)
+	}
+}
@@ -0,0 +1,448 @@
+package core
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestPlutusScript(t *testing.T) {
+	t.Parallel()
+
+	script, err := NewPlutusScriptFromEnvelope([]byte(`{
+		"type": "PlutusScriptV2",
+		"description": "",
+		"cborHex": "4e4d01000033222220051200120011"
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, PlutusV2, script.Version)
+	require.Equal(t, "4d01000033222220051200120011", hex.EncodeToString(script.Script))
+
+	hash, err := script.GetHash()
+	require.NoError(t, err)
+
+	expectedHash, err := GetKeyHash(append([]byte{2}, script.Script...))
+	require.NoError(t, err)
+	require.Equal(t, expectedHash, hash)
+
+	_, err = NewPlutusScriptFromEnvelope([]byte(`{"type": "PlutusScriptV4", "cborHex": "4100"}`))
+	require.ErrorContains(t, err, "unsupported plutus script type")
+
+	_, err = NewPlutusScript(4, script.Script).GetHash()
+	require.Error(t, err)
+}
+
+func TestGetLanguageViews(t *testing.T) {
+	t.Parallel()
+
+	costModels := map[string][]int64{
+		"PlutusV1": {1, -2},
+		"PlutusV2": {3, 4, 5},
+		"PlutusV3": {6},
+	}
+
+	views, err := getLanguageViews([]PlutusScriptVersion{PlutusV2}, costModels)
+	require.NoError(t, err)
+	require.Equal(t, "a10183030405", hex.EncodeToString(views))
+
+	views, err = getLanguageViews([]PlutusScriptVersion{PlutusV1, PlutusV3, PlutusV2, PlutusV3}, costModels)
+	require.NoError(t, err)
+	require.Equal(t, "a3"+"0183030405"+"028106"+"4100"+"449f0121ff", hex.EncodeToString(views))
+
+	_, err = getLanguageViews([]PlutusScriptVersion{PlutusV3}, map[string][]int64{})
+	require.ErrorContains(t, err, "PlutusV3")
+}
+
+func TestGetExUnitsFee(t *testing.T) {
+	t.Parallel()
+
+	pp := ProtocolParameters{
+		ExecutionUnitPrices: NewProtocolParametersPriceMemorySteps(0.0577, 0.0000721),
+	}
+
+	require.Equal(t, uint64(0), GetExUnitsFee(pp, NewExUnits(0, 0)))
+	require.Equal(t, uint64(93_750), GetExUnitsFee(pp, NewExUnits(1_000_000, 500_000_000)))
+	require.Equal(t, uint64(93_751), GetExUnitsFee(pp, NewExUnits(1_000_000, 500_000_001)))
+
+	exUnits, err := getRedeemersExUnits([]byte{
+		0x82,
+		0x84, 0x00, 0x00, 0x00, 0x82, 0x01, 0x02,
+		0x84, 0x00, 0x01, 0x00, 0x82, 0x03, 0x04,
+	})
+	require.NoError(t, err)
+	require.Equal(t, NewExUnits(4, 6), exUnits)
+
+	// conway map format
+	exUnits, err = getRedeemersExUnits([]byte{0xa1, 0x82, 0x00, 0x00, 0x82, 0x00, 0x82, 0x05, 0x06})
+	require.NoError(t, err)
+	require.Equal(t, NewExUnits(5, 6), exUnits)
+}
+
+func TestTxBuilder_PlutusScriptInput(t *testing.T) {
+	t.Parallel()
+
+	const (
+		addr      = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		inputHash = "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"
+		otherHash = "098236134e0f2077a6434dd9d7727126fa8b3627bcab3ae030a194d46eded73e"
+	)
+
+	costModels := map[string][]int64{"PlutusV2": {3, 4, 5}}
+	ppBytes, err := json.Marshal(ProtocolParameters{
+		CostModels:           costModels,
+		TxFeeFixed:           155381,
+		TxFeePerByte:         44,
+		UtxoCostPerByte:      4310,
+		CollateralPercentage: 150,
+		ExecutionUnitPrices:  NewProtocolParametersPriceMemorySteps(0.0577, 0.0000721),
+	})
+	require.NoError(t, err)
+
+	script := NewPlutusScript(PlutusV2, []byte{0x4d, 0x01, 0x00, 0x00, 0x33, 0x22, 0x22, 0x20, 0x05, 0x12, 0x00, 0x12, 0x00, 0x11})
+	datum := []byte{0xd8, 0x79, 0x9f, 0x01, 0xff} // Constr 0 [1]
+	redeemerData := []byte{0xd8, 0x7a, 0x80}      // Constr 1 []
+	exUnits := NewExUnits(1_000_000, 500_000_000) // 93750 lovelace
+
+	builder, err := NewTxBuilder("")
+	require.NoError(t, err)
+
+	builder.SetProtocolParameters(ppBytes).
+		AddUtxos(Utxo{Hash: otherHash, Index: 1, Amount: 5_000_000}).
+		AddPlutusScriptInput(script, Utxo{Hash: inputHash, Index: 0, Amount: 10_000_000}, datum,
+			NewRedeemer(redeemerData, exUnits)).
+		AddCollateralUtxos(Utxo{Hash: otherHash, Index: 2, Amount: 5_000_000}).
+		SetCollateralReturnAddress(addr).
+		AddOutputs(NewTxOutput(addr, 3_000_000))
+
+	fee, err := builder.SetWitnessCount(1).Balance(addr)
+	require.NoError(t, err)
+
+	txRaw, _, err := builder.Build()
+	require.NoError(t, err)
+
+	type decodedTx struct {
+		_          struct{} `cbor:",toarray"`
+		Body       map[int]cbor.RawMessage
+		WitnessSet map[int]cbor.RawMessage
+		IsValid    bool
+		Aux        cbor.RawMessage
+	}
+
+	var (
+		tx        decodedTx
+		redeemers []txRawRedeemer
+		datums    []cbor.RawMessage
+		scripts   [][]byte
+		total     uint64
+		ret       txRawOutput
+	)
+
+	require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+
+	// redeemer points to the second input after sorting
+	require.NoError(t, cbor.Unmarshal(tx.WitnessSet[redeemersKey], &redeemers))
+	require.Len(t, redeemers, 1)
+	require.Equal(t, RedeemerTagSpend, redeemers[0].Tag)
+	require.Equal(t, uint32(1), redeemers[0].Index)
+	require.Equal(t, cbor.RawMessage(redeemerData), redeemers[0].Data)
+	require.Equal(t, exUnits, redeemers[0].ExUnits)
+
+	require.NoError(t, cbor.Unmarshal(tx.WitnessSet[4], &datums))
+	require.Equal(t, []cbor.RawMessage{datum}, datums)
+
+	require.NoError(t, cbor.Unmarshal(tx.WitnessSet[6], &scripts))
+	require.Equal(t, [][]byte{script.Script}, scripts)
+
+	// script data hash
+	languageViews, err := getLanguageViews([]PlutusScriptVersion{PlutusV2}, costModels)
+	require.NoError(t, err)
+
+	expectedScriptDataHash := blake2b.Sum256(append(append(append([]byte{},
+		tx.WitnessSet[redeemersKey]...), tx.WitnessSet[4]...), languageViews...))
+
+	var scriptDataHash []byte
+
+	require.NoError(t, cbor.Unmarshal(tx.Body[11], &scriptDataHash))
+	require.Equal(t, expectedScriptDataHash[:], scriptDataHash)
+
+	// collateral
+	require.NoError(t, cbor.Unmarshal(tx.Body[17], &total))
+	require.Equal(t, (fee*150+99)/100, total)
+	require.NoError(t, cbor.Unmarshal(tx.Body[16], &ret))
+	require.Equal(t, 5_000_000-total, ret.Amount.Coin)
+
+	// fee contains execution units fee (draft transaction used for the fee is few bytes larger)
+	feeWithoutScripts, err := CalculateMinFee(txRaw, ProtocolParameters{
+		TxFeeFixed:   155381,
+		TxFeePerByte: 44,
+	}, 1, 0)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, fee, feeWithoutScripts+93_750)
+	require.LessOrEqual(t, fee, feeWithoutScripts+93_750+10*44)
+
+	// change is everything from both inputs without the output and the fee
+	require.Equal(t, uint64(15_000_000-3_000_000)-fee, builder.outputs[1].Amount)
+
+	t.Run("not enough collateral", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		builder.SetProtocolParameters(ppBytes).
+			AddPlutusScriptInput(script, Utxo{Hash: inputHash, Index: 0, Amount: 10_000_000}, nil,
+				NewRedeemer(redeemerData, exUnits)).
+			AddCollateralUtxos(Utxo{Hash: otherHash, Index: 2, Amount: 100_000}).
+			SetCollateralReturnAddress(addr).
+			AddOutputs(NewTxOutput(addr, 3_000_000)).
+			SetFee(200_000)
+
+		_, _, err = builder.Build()
+		require.ErrorContains(t, err, "not enough collateral")
+	})
+
+	t.Run("dust collateral return", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		builder.SetProtocolParameters(ppBytes).
+			AddPlutusScriptInput(script, Utxo{Hash: inputHash, Index: 0, Amount: 10_000_000}, nil,
+				NewRedeemer(redeemerData, exUnits)).
+			AddCollateralUtxos(Utxo{Hash: otherHash, Index: 2, Amount: 400_000}).
+			SetCollateralReturnAddress(addr).
+			AddOutputs(NewTxOutput(addr, 3_000_000)).
+			SetFee(200_000)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		var (
+			tx    decodedTx
+			total uint64
+		)
+
+		require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+		require.NotContains(t, tx.Body, 16)
+		require.NotContains(t, tx.WitnessSet, 4) // datum is inline
+		require.NoError(t, cbor.Unmarshal(tx.Body[17], &total))
+		require.Equal(t, uint64(400_000), total)
+	})
+}
+
+func TestTxBuilder_EvaluateExUnits(t *testing.T) {
+	t.Parallel()
+
+	const (
+		addr      = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		inputHash = "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"
+		otherHash = "098236134e0f2077a6434dd9d7727126fa8b3627bcab3ae030a194d46eded73e"
+	)
+
+	// ogmios stand-in: script input is the second one after sorting
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","method":"evaluateTransaction","result":[
+			{"validator":{"purpose":"spend","index":1},"budget":{"memory":1000000,"cpu":500000000}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	script := NewPlutusScript(PlutusV2, []byte{0x4d, 0x01, 0x00, 0x00, 0x33, 0x22, 0x22, 0x20, 0x05, 0x12, 0x00, 0x12, 0x00, 0x11})
+
+	createBuilder := func(t *testing.T, maxExUnits ProtocolParametersMemorySteps) *TxBuilder {
+		t.Helper()
+
+		ppBytes, err := json.Marshal(ProtocolParameters{
+			CostModels:           map[string][]int64{"PlutusV2": {3, 4, 5}},
+			TxFeeFixed:           155381,
+			TxFeePerByte:         44,
+			UtxoCostPerByte:      4310,
+			CollateralPercentage: 150,
+			ExecutionUnitPrices:  NewProtocolParametersPriceMemorySteps(0.0577, 0.0000721),
+			MaxTxExecutionUnits:  maxExUnits,
+		})
+		require.NoError(t, err)
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		return builder.SetProtocolParameters(ppBytes).
+			AddUtxos(Utxo{Hash: otherHash, Index: 1, Amount: 5_000_000}).
+			AddPlutusScriptInput(script, Utxo{Hash: inputHash, Index: 0, Amount: 10_000_000}, nil,
+				NewRedeemer([]byte{0xd8, 0x79, 0x80}, ExUnits{})).
+			AddCollateralUtxos(Utxo{Hash: otherHash, Index: 2, Amount: 5_000_000}).
+			AddOutputs(NewTxOutput(addr, 3_000_000))
+	}
+
+	builder := createBuilder(t, NewProtocolParametersMemorySteps(14_000_000, 10_000_000_000))
+
+	feeWithoutExUnits, err := builder.CalculateFee(1)
+	require.NoError(t, err)
+
+	require.NoError(t, builder.EvaluateExUnits(context.Background(), NewTxProviderOgmios(server.URL)))
+	require.Equal(t, NewExUnits(1_000_000, 500_000_000), builder.inputs[1].plutus.redeemer.ExUnits)
+
+	fee, err := builder.SetWitnessCount(1).Balance(addr)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, fee, feeWithoutExUnits+93_750)
+
+	txRaw, _, err := builder.Build()
+	require.NoError(t, err)
+
+	exUnits, err := getRedeemersExUnits(getWitnessSetItem(t, txRaw, redeemersKey))
+	require.NoError(t, err)
+	require.Equal(t, NewExUnits(1_000_000, 500_000_000), exUnits)
+
+	t.Run("exceeds max tx execution units", func(t *testing.T) {
+		t.Parallel()
+
+		builder := createBuilder(t, NewProtocolParametersMemorySteps(14_000_000, 100_000_000))
+
+		err := builder.EvaluateExUnits(context.Background(), NewTxProviderOgmios(server.URL))
+		require.ErrorIs(t, err, ErrExUnitsExceedMax)
+		require.Equal(t, ExUnits{}, builder.inputs[1].plutus.redeemer.ExUnits)
+	})
+
+	t.Run("evaluate and balance", func(t *testing.T) {
+		t.Parallel()
+
+		var lastEvaluatedTxRaw []byte
+
+		// script budget depends on the number of outputs, so it changes when the change output is added
+		evaluator := testTxEvaluator(func(txRaw []byte) (map[RedeemerPointer]ExUnits, error) {
+			var tx []cbor.RawMessage
+
+			if err := cbor.Unmarshal(txRaw, &tx); err != nil {
+				return nil, err
+			}
+
+			var body struct {
+				Outputs []cbor.RawMessage `cbor:"1,keyasint"`
+			}
+
+			if err := cbor.Unmarshal(tx[0], &body); err != nil {
+				return nil, err
+			}
+
+			lastEvaluatedTxRaw = txRaw
+
+			return map[RedeemerPointer]ExUnits{
+				NewRedeemerPointer(RedeemerTagSpend, 1): NewExUnits(uint64(len(body.Outputs))*1_000_000, 100_000_000),
+			}, nil
+		})
+
+		builder := createBuilder(t, ProtocolParametersMemorySteps{})
+
+		fee, err := builder.SetWitnessCount(1).EvaluateAndBalance(context.Background(), evaluator, addr)
+		require.NoError(t, err)
+		require.Len(t, builder.outputs, 2)
+		require.Equal(t, NewExUnits(2_000_000, 100_000_000), builder.inputs[1].plutus.redeemer.ExUnits)
+		require.Equal(t, uint64(15_000_000-3_000_000)-fee, builder.outputs[1].Amount)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+		require.Equal(t, lastEvaluatedTxRaw, txRaw)
+	})
+
+	t.Run("redeemer not evaluated", func(t *testing.T) {
+		t.Parallel()
+
+		builder := createBuilder(t, ProtocolParametersMemorySteps{})
+		builder.inputs[1].txInput.Hash = strings.Repeat("0", 64) // script input is the first one after sorting
+
+		err := builder.EvaluateExUnits(context.Background(), NewTxProviderOgmios(server.URL))
+		require.ErrorContains(t, err, "not evaluated")
+	})
+}
+
+type testTxEvaluator func(txRaw []byte) (map[RedeemerPointer]ExUnits, error)
+
+func (e testTxEvaluator) EvaluateTx(_ context.Context, txRaw []byte) (map[RedeemerPointer]ExUnits, error) {
+	return e(txRaw)
+}
+
+func getWitnessSetItem(t *testing.T, txRaw []byte, key int) cbor.RawMessage {
+	t.Helper()
+
+	var tx []cbor.RawMessage
+
+	require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+
+	var witnessSet map[int]cbor.RawMessage
+
+	require.NoError(t, cbor.Unmarshal(tx[1], &witnessSet))
+
+	return witnessSet[key]
+}
+
+func TestTxOutput_Datum(t *testing.T) {
+	t.Parallel()
+
+	const addr = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+
+	datum := []byte{0xd8, 0x79, 0x9f, 0x41, 0x01, 0xff}
+	datumHash := GetDatumHash(datum)
+
+	hashOutput := NewTxOutput(addr, 0)
+	hashOutput.DatumHash = datumHash
+
+	inlineOutput := NewTxOutput(addr, 0)
+	inlineOutput.InlineDatum = datum
+
+	minUtxo, err := GetMinUtxoForOutput(NewTxOutput(addr, 0), 4310)
+	require.NoError(t, err)
+
+	for _, output := range []TxOutput{hashOutput, inlineOutput} {
+		outputMinUtxo, err := GetMinUtxoForOutput(output, 4310)
+		require.NoError(t, err)
+		require.Greater(t, outputMinUtxo, minUtxo)
+
+		output.Amount = outputMinUtxo
+
+		raw, err := newTxRawOutput(output)
+		require.NoError(t, err)
+
+		bytes, err := cbor.Marshal(raw)
+		require.NoError(t, err)
+		require.Equal(t, byte(0xa3), bytes[0]) // {0: address, 1: amount, 2: datum}
+
+		var decoded txRawOutput
+
+		require.NoError(t, cbor.Unmarshal(bytes, &decoded))
+		require.Equal(t, raw, decoded)
+	}
+
+	raw, err := newTxRawOutput(hashOutput)
+	require.NoError(t, err)
+	require.Equal(t, "8200"+"5820"+datumHash, hex.EncodeToString(mustCborMarshal(t, raw.Datum)))
+
+	raw, err = newTxRawOutput(inlineOutput)
+	require.NoError(t, err)
+	require.Equal(t, "8201"+"d81846"+hex.EncodeToString(datum), hex.EncodeToString(mustCborMarshal(t, raw.Datum)))
+
+	hashOutput.InlineDatum = datum
+	_, err = newTxRawOutput(hashOutput)
+	require.ErrorContains(t, err, "both datum hash and inline datum")
+
+	inlineOutput.InlineDatum, inlineOutput.DatumHash = nil, "abcd"
+	_, err = newTxRawOutput(inlineOutput)
+	require.ErrorContains(t, err, "invalid datum hash")
+}
+
+func mustCborMarshal(t *testing.T, value interface{}) []byte {
+	t.Helper()
+
+	bytes, err := cbor.Marshal(value)
+	require.NoError(t, err)
+
+	return bytes
+}
@@ -3,14 +3,34 @@ package core
 import (
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"sort"
+
+	"github.com/fxamacker/cbor/v2"
 )
 
 const (
 	PolicyScriptAtLeastType = "atLeast"
 	PolicyScriptSigType     = "sig"
+	PolicyScriptAllType     = "all"
+	PolicyScriptAnyType     = "any"
+	PolicyScriptAfterType   = "after"
+	PolicyScriptBeforeType  = "before"
+)
+
+// native script tags as defined in the ledger cddl
+const (
+	nativeScriptPubKeyTag = iota
+	nativeScriptAllTag
+	nativeScriptAnyTag
+	nativeScriptNOfKTag
+	nativeScriptInvalidBeforeTag
+	nativeScriptInvalidHereafterTag
 )
 
+const nativeScriptHashPrefix = 0x00
+
 type PolicyScript struct {
 	Type string `json:"type"`
 
@@ -41,6 +61,36 @@ func NewPolicyScript(keyHashes []string, atLeastSignersCount int) *PolicyScript
 	}
 }
 
+// NewSigPolicyScript creates script which requires signature of the key with the key hash
+func NewSigPolicyScript(keyHash string) PolicyScript {
+	return PolicyScript{Type: PolicyScriptSigType, KeyHash: keyHash}
+}
+
+// NewAllPolicyScript creates script which requires all the scripts to be satisfied
+func NewAllPolicyScript(scripts ...PolicyScript) PolicyScript {
+	return PolicyScript{Type: PolicyScriptAllType, Scripts: scripts}
+}
+
+// NewAnyPolicyScript creates script which requires at least one of the scripts to be satisfied
+func NewAnyPolicyScript(scripts ...PolicyScript) PolicyScript {
+	return PolicyScript{Type: PolicyScriptAnyType, Scripts: scripts}
+}
+
+// NewAtLeastPolicyScript creates script which requires required number of the scripts to be satisfied
+func NewAtLeastPolicyScript(required int, scripts ...PolicyScript) PolicyScript {
+	return PolicyScript{Type: PolicyScriptAtLeastType, Required: required, Scripts: scripts}
+}
+
+// NewAfterPolicyScript creates script which is satisfied only if transaction is valid from the slot or later
+func NewAfterPolicyScript(slot uint64) PolicyScript {
+	return PolicyScript{Type: PolicyScriptAfterType, Slot: slot}
+}
+
+// NewBeforePolicyScript creates script which is satisfied only if transaction is invalid from the slot onward
+func NewBeforePolicyScript(slot uint64) PolicyScript {
+	return PolicyScript{Type: PolicyScriptBeforeType, Slot: slot}
+}
+
 func (ps PolicyScript) GetPolicyScriptJSON() ([]byte, error) {
 	return json.MarshalIndent(ps, "", "  ")
 }
@@ -49,13 +99,13 @@ func (ps PolicyScript) GetCount() (cnt int) {
 	switch ps.Type {
 	case PolicyScriptSigType:
 		cnt = 1
-	case "any":
+	case PolicyScriptAnyType:
 		for _, x := range ps.Scripts {
 			if subCnt := x.GetCount(); cnt < subCnt {
 				cnt = subCnt
 			}
 		}
-	case "all", PolicyScriptAtLeastType:
+	case PolicyScriptAllType, PolicyScriptAtLeastType:
 		for _, x := range ps.Scripts {
 			cnt += x.GetCount()
 		}
@@ -64,6 +114,271 @@ func (ps PolicyScript) GetCount() (cnt int) {
 	return cnt
 }
 
+// GetPolicyIDBytes returns hash of the native script: blake2b-224 of the native script tag followed by the script cbor
+func (ps PolicyScript) GetPolicyIDBytes() ([]byte, error) {
+	if err := ps.Validate(); err != nil {
+		return nil, err
+	}
+
+	script, err := ps.MarshalCBOR()
+	if err != nil {
+		return nil, err
+	}
+
+	return getNativeScriptHash(script)
+}
+
+// GetPolicyID returns hex encoded hash of the native script (policy id or script hash of the address)
+func (ps PolicyScript) GetPolicyID() (string, error) {
+	policyID, err := ps.GetPolicyIDBytes()
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(policyID), nil
+}
+
+// GetAddress returns enterprise address of the policy script
+// or base address if the policy script of the stake part is provided
+func (ps PolicyScript) GetAddress(
+	networkID CardanoNetworkType, policyScriptStake ...PolicyScript,
+) (*CardanoAddress, error) {
+	policyID, err := ps.GetPolicyID()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(policyScriptStake) == 0 {
+		return NewPolicyScriptAddress(networkID, policyID)
+	}
+
+	policyIDStake, err := policyScriptStake[0].GetPolicyID()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPolicyScriptAddress(networkID, policyID, policyIDStake)
+}
+
+// Validate checks structure of the script and all its nested scripts
+func (ps PolicyScript) Validate() error {
+	switch ps.Type {
+	case PolicyScriptSigType:
+		keyHash, err := hex.DecodeString(ps.KeyHash)
+		if err != nil || len(keyHash) != KeyHashSize {
+			return fmt.Errorf("invalid policy script key hash: %s", ps.KeyHash)
+		}
+	case PolicyScriptAtLeastType:
+		if ps.Required < 0 || ps.Required > len(ps.Scripts) {
+			return fmt.Errorf("policy script requires %d of %d scripts", ps.Required, len(ps.Scripts))
+		}
+	case PolicyScriptAllType, PolicyScriptAnyType, PolicyScriptAfterType, PolicyScriptBeforeType:
+	default:
+		return fmt.Errorf("unknown policy script type: %s", ps.Type)
+	}
+
+	for _, script := range ps.Scripts {
+		if err := script.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validityInterval of the transaction. Start must be present in the transaction body if any after timelock
+// is used, even if it is zero, so it is tracked separately. Hereafter equal to zero means not set
+type validityInterval struct {
+	start     uint64
+	hasStart  bool
+	hereafter uint64
+}
+
+// satisfyValidityInterval narrows validity interval of the transaction so timelocks of the script are satisfied.
+// Signatures are assumed to be present. Ok is false if it is not possible
+func (ps PolicyScript) satisfyValidityInterval(interval validityInterval) (validityInterval, bool) {
+	switch ps.Type {
+	case PolicyScriptAfterType:
+		if !interval.hasStart || interval.start < ps.Slot {
+			interval.start, interval.hasStart = ps.Slot, true
+		}
+
+		return interval, interval.hereafter == 0 || interval.start < interval.hereafter
+	case PolicyScriptBeforeType:
+		if interval.hereafter == 0 || interval.hereafter > ps.Slot {
+			interval.hereafter = ps.Slot
+		}
+
+		return interval, interval.start < interval.hereafter
+	case PolicyScriptAllType:
+		for _, script := range ps.Scripts {
+			var ok bool
+
+			if interval, ok = script.satisfyValidityInterval(interval); !ok {
+				return interval, false
+			}
+		}
+
+		return interval, true
+	case PolicyScriptAnyType:
+		return satisfyValidityIntervalAtLeast(ps.Scripts, 1, interval)
+	case PolicyScriptAtLeastType:
+		return satisfyValidityIntervalAtLeast(ps.Scripts, ps.Required, interval)
+	default:
+		return interval, true
+	}
+}
+
+// satisfyValidityIntervalAtLeast prefers scripts which are satisfied without changing the validity interval
+// and then narrows the interval by the remaining scripts until required number of them is satisfied
+func satisfyValidityIntervalAtLeast(
+	scripts []PolicyScript, required int, interval validityInterval,
+) (validityInterval, bool) {
+	var pending []PolicyScript
+
+	for _, script := range scripts {
+		if required <= 0 {
+			break
+		}
+
+		newInterval, ok := script.satisfyValidityInterval(interval)
+		if ok && newInterval == interval {
+			required--
+		} else {
+			pending = append(pending, script)
+		}
+	}
+
+	for _, script := range pending {
+		if required <= 0 {
+			break
+		}
+
+		if newInterval, ok := script.satisfyValidityInterval(interval); ok {
+			interval = newInterval
+			required--
+		}
+	}
+
+	return interval, required <= 0
+}
+
+// MarshalCBOR serializes policy script as the ledger native script
+func (ps PolicyScript) MarshalCBOR() ([]byte, error) {
+	switch ps.Type {
+	case PolicyScriptSigType:
+		keyHash, err := hex.DecodeString(ps.KeyHash)
+		if err != nil {
+			return nil, err
+		}
+
+		return cbor.Marshal([]interface{}{nativeScriptPubKeyTag, keyHash})
+	case PolicyScriptAllType:
+		return cbor.Marshal([]interface{}{nativeScriptAllTag, ps.getScripts()})
+	case PolicyScriptAnyType:
+		return cbor.Marshal([]interface{}{nativeScriptAnyTag, ps.getScripts()})
+	case PolicyScriptAtLeastType:
+		return cbor.Marshal([]interface{}{nativeScriptNOfKTag, ps.Required, ps.getScripts()})
+	case PolicyScriptAfterType:
+		return cbor.Marshal([]interface{}{nativeScriptInvalidBeforeTag, ps.Slot})
+	case PolicyScriptBeforeType:
+		return cbor.Marshal([]interface{}{nativeScriptInvalidHereafterTag, ps.Slot})
+	default:
+		return nil, fmt.Errorf("unknown policy script type: %s", ps.Type)
+	}
+}
+
+// newPolicyScriptFromCbor deserializes the ledger native script
+func newPolicyScriptFromCbor(data []byte) (PolicyScript, error) {
+	var items []cbor.RawMessage
+
+	if err := cbor.Unmarshal(data, &items); err != nil {
+		return PolicyScript{}, err
+	} else if len(items) < 2 {
+		return PolicyScript{}, errors.New("invalid native script cbor")
+	}
+
+	var tag int
+
+	if err := cbor.Unmarshal(items[0], &tag); err != nil {
+		return PolicyScript{}, err
+	}
+
+	getScripts := func(data []byte) ([]PolicyScript, error) {
+		var scripts []cbor.RawMessage
+
+		if err := cbor.Unmarshal(data, &scripts); err != nil {
+			return nil, err
+		}
+
+		result := make([]PolicyScript, len(scripts))
+
+		for i, x := range scripts {
+			script, err := newPolicyScriptFromCbor(x)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = script
+		}
+
+		return result, nil
+	}
+
+	switch {
+	case tag == nativeScriptPubKeyTag:
+		var keyHash []byte
+
+		err := cbor.Unmarshal(items[1], &keyHash)
+
+		return PolicyScript{Type: PolicyScriptSigType, KeyHash: hex.EncodeToString(keyHash)}, err
+	case tag == nativeScriptAllTag || tag == nativeScriptAnyTag:
+		scripts, err := getScripts(items[1])
+
+		scriptType := PolicyScriptAllType
+		if tag == nativeScriptAnyTag {
+			scriptType = PolicyScriptAnyType
+		}
+
+		return PolicyScript{Type: scriptType, Scripts: scripts}, err
+	case tag == nativeScriptNOfKTag && len(items) == 3:
+		var required int
+
+		if err := cbor.Unmarshal(items[1], &required); err != nil {
+			return PolicyScript{}, err
+		}
+
+		scripts, err := getScripts(items[2])
+
+		return PolicyScript{Type: PolicyScriptAtLeastType, Required: required, Scripts: scripts}, err
+	case tag == nativeScriptInvalidBeforeTag || tag == nativeScriptInvalidHereafterTag:
+		var slot uint64
+
+		err := cbor.Unmarshal(items[1], &slot)
+
+		scriptType := PolicyScriptAfterType
+		if tag == nativeScriptInvalidHereafterTag {
+			scriptType = PolicyScriptBeforeType
+		}
+
+		return PolicyScript{Type: scriptType, Slot: slot}, err
+	default:
+		return PolicyScript{}, fmt.Errorf("unknown native script tag: %d", tag)
+	}
+}
+
+func getNativeScriptHash(script []byte) ([]byte, error) {
+	return GetKeyHashBytes(append([]byte{nativeScriptHashPrefix}, script...))
+}
+
+func (ps PolicyScript) getScripts() []PolicyScript {
+	if ps.Scripts == nil {
+		return []PolicyScript{} // must be serialized as an empty array
+	}
+
+	return ps.Scripts
+}
+
 // GetAddress returns address for this policy script
 func NewPolicyScriptAddress(
 	networkID CardanoNetworkType, policyID string, policyIDStake ...string,
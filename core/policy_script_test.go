@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
 )
 
 func TestPolicyScript(t *testing.T) {
@@ -33,6 +34,10 @@ func TestPolicyScript(t *testing.T) {
 	policyID, err := cliUtils.GetPolicyID(ps)
 	require.NoError(t, err)
 
+	nativePolicyID, err := ps.GetPolicyID()
+	require.NoError(t, err)
+	require.Equal(t, policyID, nativePolicyID)
+
 	policyIDDifferentOrder, err := cliUtils.GetPolicyID(psDifferentOrder)
 	require.NoError(t, err)
 
@@ -129,3 +134,105 @@ func TestPolicyScript_SpecificKeysAllPermutations(t *testing.T) {
 		require.Equal(t, cliAddr, addr.String())
 	})
 }
+
+func TestPolicyScript_Builders(t *testing.T) {
+	t.Parallel()
+
+	const (
+		keyHash1 = "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"
+		keyHash2 = "cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41"
+	)
+
+	ps := NewAllPolicyScript(
+		NewAnyPolicyScript(NewSigPolicyScript(keyHash1), NewSigPolicyScript(keyHash2)),
+		NewAtLeastPolicyScript(1, NewSigPolicyScript(keyHash2)),
+		NewAfterPolicyScript(1000),
+		NewBeforePolicyScript(2000),
+	)
+
+	require.NoError(t, ps.Validate())
+	require.Equal(t, 2, ps.GetCount())
+
+	bytes, err := ps.MarshalCBOR()
+	require.NoError(t, err)
+	require.Equal(t, "8201"+"84"+
+		"8202"+"82"+"8200581c"+keyHash1+"8200581c"+keyHash2+
+		"830301"+"81"+"8200581c"+keyHash2+
+		"82041903e8"+
+		"82051907d0", hex.EncodeToString(bytes))
+
+	decoded, err := newPolicyScriptFromCbor(bytes)
+	require.NoError(t, err)
+	require.Equal(t, ps, decoded)
+
+	for _, invalid := range []PolicyScript{
+		NewSigPolicyScript("0102"),
+		NewSigPolicyScript("not hex"),
+		NewAtLeastPolicyScript(2, NewSigPolicyScript(keyHash1)),
+		NewAtLeastPolicyScript(-1),
+		NewAnyPolicyScript(NewAllPolicyScript(NewSigPolicyScript(keyHash1 + "00"))),
+		{Type: "unknown"},
+	} {
+		require.Error(t, invalid.Validate())
+	}
+}
+
+func TestPolicyScript_PolicyIDAndAddress(t *testing.T) {
+	t.Parallel()
+
+	keyHashes := []string{
+		"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21",
+		"cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41",
+		"79df3577e4c7d7da04872c2182b8d8829d7b477912dbf35d89287c39",
+	}
+
+	ps := NewPolicyScript(keyHashes, 2)
+	psStake := NewPolicyScript(keyHashes[:1], 1)
+
+	script, err := ps.MarshalCBOR()
+	require.NoError(t, err)
+
+	hasher, err := blake2b.New(KeyHashSize, nil)
+	require.NoError(t, err)
+
+	hasher.Write(append([]byte{0x00}, script...))
+
+	policyID, err := ps.GetPolicyID()
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(hasher.Sum(nil)), policyID)
+
+	// order of the keys does not change policy id
+	psDifferentOrder := NewPolicyScript([]string{keyHashes[2], keyHashes[0], keyHashes[1]}, 2)
+
+	policyIDDifferentOrder, err := psDifferentOrder.GetPolicyID()
+	require.NoError(t, err)
+	require.Equal(t, policyID, policyIDDifferentOrder)
+
+	policyIDStake, err := psStake.GetPolicyID()
+	require.NoError(t, err)
+	require.NotEqual(t, policyID, policyIDStake)
+
+	addr, err := ps.GetAddress(TestNetNetwork)
+	require.NoError(t, err)
+
+	expectedAddr, err := NewPolicyScriptAddress(TestNetNetwork, policyID)
+	require.NoError(t, err)
+	require.Equal(t, expectedAddr.String(), addr.String())
+	require.Equal(t, EnterpriseAddress, addr.GetInfo().AddressType)
+	require.True(t, addr.GetInfo().Payment.IsScript)
+
+	addrStake, err := ps.GetAddress(MainNetNetwork, *psStake)
+	require.NoError(t, err)
+
+	expectedAddrStake, err := NewPolicyScriptAddress(MainNetNetwork, policyID, policyIDStake)
+	require.NoError(t, err)
+	require.Equal(t, expectedAddrStake.String(), addrStake.String())
+	require.Equal(t, BaseAddress, addrStake.GetInfo().AddressType)
+	require.True(t, addrStake.GetInfo().Stake.IsScript)
+
+	_, err = NewAtLeastPolicyScript(2, NewSigPolicyScript(keyHashes[0])).GetPolicyID()
+	require.Error(t, err)
+
+	_, err = ps.GetAddress(TestNetNetwork, NewSigPolicyScript("0102"))
+	require.Error(t, err)
+}
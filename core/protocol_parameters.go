@@ -36,6 +36,29 @@ func NewProtocolParametersPriceMemorySteps(memory, steps float64) ProtocolParame
 	}
 }
 
+// ProtocolParametersDRepVotingThresholds are conway thresholds of the DRep votes needed for the governance actions
+type ProtocolParametersDRepVotingThresholds struct {
+	MotionNoConfidence    float64 `json:"motionNoConfidence"`
+	CommitteeNormal       float64 `json:"committeeNormal"`
+	CommitteeNoConfidence float64 `json:"committeeNoConfidence"`
+	UpdateToConstitution  float64 `json:"updateToConstitution"`
+	HardForkInitiation    float64 `json:"hardForkInitiation"`
+	PPNetworkGroup        float64 `json:"ppNetworkGroup"`
+	PPEconomicGroup       float64 `json:"ppEconomicGroup"`
+	PPTechnicalGroup      float64 `json:"ppTechnicalGroup"`
+	PPGovGroup            float64 `json:"ppGovGroup"`
+	TreasuryWithdrawal    float64 `json:"treasuryWithdrawal"`
+}
+
+// ProtocolParametersPoolVotingThresholds are conway thresholds of the stake pool votes needed for the governance actions
+type ProtocolParametersPoolVotingThresholds struct {
+	MotionNoConfidence    float64 `json:"motionNoConfidence"`
+	CommitteeNormal       float64 `json:"committeeNormal"`
+	CommitteeNoConfidence float64 `json:"committeeNoConfidence"`
+	HardForkInitiation    float64 `json:"hardForkInitiation"`
+	PPSecurityGroup       float64 `json:"ppSecurityGroup"`
+}
+
 type ProtocolParameters struct {
 	CostModels             map[string][]int64                 `json:"costModels"`
 	ProtocolVersion        ProtocolParametersVersion          `json:"protocolVersion"`
@@ -62,4 +85,20 @@ type ProtocolParameters struct {
 	ExtraPraosEntropy      *uint64                            `json:"extraPraosEntropy"`
 	Decentralization       *uint64                            `json:"decentralization"`
 	MinUTxOValue           *uint64                            `json:"minUTxOValue"`
+
+	MinFeeRefScriptCostPerByte float64 `json:"minFeeRefScriptCostPerByte"`
+	// MinFeeRefScriptRange and MinFeeRefScriptMultiplier are tier size and price multiplier of the reference
+	// scripts fee. Conway ledger constants (25600 and 1.2) are used if they are not set
+	MinFeeRefScriptRange      uint64  `json:"minFeeRefScriptRange,omitempty"`
+	MinFeeRefScriptMultiplier float64 `json:"minFeeRefScriptMultiplier,omitempty"`
+
+	// conway governance
+	DRepDeposit            uint64                                 `json:"dRepDeposit"`
+	DRepActivity           uint64                                 `json:"dRepActivity"`
+	DRepVotingThresholds   ProtocolParametersDRepVotingThresholds `json:"dRepVotingThresholds"`
+	PoolVotingThresholds   ProtocolParametersPoolVotingThresholds `json:"poolVotingThresholds"`
+	GovActionDeposit       uint64                                 `json:"govActionDeposit"`
+	GovActionLifetime      uint64                                 `json:"govActionLifetime"`
+	CommitteeMinSize       uint64                                 `json:"committeeMinSize"`
+	CommitteeMaxTermLength uint64                                 `json:"committeeMaxTermLength"`
 }
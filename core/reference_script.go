@@ -0,0 +1,179 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const scriptRefNativeType = 0
+
+// ReferenceScript is script stored in the output which can be used instead of attaching the script
+// to the transaction. Exactly one of the fields is set
+type ReferenceScript struct {
+	Native *PolicyScript `json:"native,omitempty"`
+	Plutus *PlutusScript `json:"plutus,omitempty"`
+}
+
+func NewNativeReferenceScript(script PolicyScript) *ReferenceScript {
+	return &ReferenceScript{
+		Native: &script,
+	}
+}
+
+func NewPlutusReferenceScript(script PlutusScript) *ReferenceScript {
+	return &ReferenceScript{
+		Plutus: &script,
+	}
+}
+
+// GetHash returns hash of the script (policy id or script hash of the address)
+func (rs ReferenceScript) GetHash() (string, error) {
+	switch {
+	case rs.Native != nil:
+		return rs.Native.GetPolicyID()
+	case rs.Plutus != nil:
+		return rs.Plutus.GetHash()
+	default:
+		return "", errors.New("reference script is empty")
+	}
+}
+
+// GetSize returns size of the script used for the conway reference scripts fee
+func (rs ReferenceScript) GetSize() (uint64, error) {
+	switch {
+	case rs.Native != nil:
+		script, err := rs.Native.MarshalCBOR()
+		if err != nil {
+			return 0, err
+		}
+
+		return uint64(len(script)), nil
+	case rs.Plutus != nil:
+		return uint64(len(rs.Plutus.Script)), nil
+	default:
+		return 0, errors.New("reference script is empty")
+	}
+}
+
+// MarshalCBOR serializes script as script_ref from the ledger cddl: #6.24(bytes .cbor [type, script])
+func (rs ReferenceScript) MarshalCBOR() ([]byte, error) {
+	var (
+		script []byte
+		err    error
+	)
+
+	switch {
+	case rs.Native != nil && rs.Plutus != nil:
+		return nil, errors.New("reference script must be either native or plutus")
+	case rs.Native != nil:
+		var nativeScript []byte
+
+		nativeScript, err = rs.Native.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+
+		script, err = cbor.Marshal([]interface{}{scriptRefNativeType, cbor.RawMessage(nativeScript)})
+	case rs.Plutus != nil:
+		if rs.Plutus.Version < PlutusV1 || rs.Plutus.Version > PlutusV3 {
+			return nil, fmt.Errorf("unsupported plutus script version: %d", rs.Plutus.Version)
+		}
+
+		script, err = cbor.Marshal([]interface{}{rs.Plutus.Version, rs.Plutus.Script})
+	default:
+		return nil, errors.New("reference script is empty")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cbor.Marshal(cbor.Tag{Number: cborTagEncodedData, Content: script})
+}
+
+// UnmarshalCBOR deserializes script_ref
+func (rs *ReferenceScript) UnmarshalCBOR(data []byte) error {
+	var tag cbor.Tag
+
+	if err := cbor.Unmarshal(data, &tag); err != nil {
+		return err
+	}
+
+	scriptBytes, ok := tag.Content.([]byte)
+	if !ok || tag.Number != cborTagEncodedData {
+		return errors.New("invalid reference script cbor")
+	}
+
+	var script struct {
+		_      struct{} `cbor:",toarray"`
+		Type   uint64
+		Script cbor.RawMessage
+	}
+
+	if err := cbor.Unmarshal(scriptBytes, &script); err != nil {
+		return err
+	}
+
+	if script.Type != scriptRefNativeType {
+		var plutusScript []byte
+
+		if err := cbor.Unmarshal(script.Script, &plutusScript); err != nil {
+			return err
+		}
+
+		*rs = *NewPlutusReferenceScript(NewPlutusScript(PlutusScriptVersion(script.Type), plutusScript)) //nolint:gosec
+
+		return nil
+	}
+
+	nativeScript, err := newPolicyScriptFromCbor(script.Script)
+	if err != nil {
+		return err
+	}
+
+	*rs = *NewNativeReferenceScript(nativeScript)
+
+	return nil
+}
+
+// newReferenceScriptFromBytes creates reference script from the script bytes returned by the providers.
+// Script type is the one from script_ref (0 for native, plutus version otherwise). If the hash is known,
+// it is checked and plutus script additionally wrapped in cbor bytestring (as in text envelope) is unwrapped
+func newReferenceScriptFromBytes(scriptType uint64, script []byte, hash string) (*ReferenceScript, error) {
+	var result *ReferenceScript
+
+	switch PlutusScriptVersion(scriptType) { //nolint:gosec
+	case scriptRefNativeType:
+		nativeScript, err := newPolicyScriptFromCbor(script)
+		if err != nil {
+			return nil, err
+		}
+
+		result = NewNativeReferenceScript(nativeScript)
+	case PlutusV1, PlutusV2, PlutusV3:
+		result = NewPlutusReferenceScript(NewPlutusScript(PlutusScriptVersion(scriptType), script)) //nolint:gosec
+
+		var unwrapped []byte
+
+		if hash != "" && !hasReferenceScriptHash(result, hash) && cbor.Unmarshal(script, &unwrapped) == nil {
+			result = NewPlutusReferenceScript(NewPlutusScript(PlutusScriptVersion(scriptType), unwrapped)) //nolint:gosec
+		}
+	default:
+		return nil, fmt.Errorf("unsupported reference script type: %d", scriptType)
+	}
+
+	if hash != "" && !hasReferenceScriptHash(result, hash) {
+		return nil, fmt.Errorf("reference script does not match hash %s", hash)
+	}
+
+	return result, nil
+}
+
+func hasReferenceScriptHash(rs *ReferenceScript, hash string) bool {
+	actualHash, err := rs.GetHash()
+
+	return err == nil && strings.EqualFold(actualHash, hash)
+}
@@ -0,0 +1,280 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenceScript(t *testing.T) {
+	t.Parallel()
+
+	const multisigPolicyID = "4aaad0f0626a8ce7b097497e542055b6520842ade881f980e002ae66"
+
+	policyScript := NewPolicyScript([]string{
+		"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21",
+		"cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41",
+		"79df3577e4c7d7da04872c2182b8d8829d7b477912dbf35d89287c39",
+		"2368e8113bd5f32d713751791d29acee9e1b5a425b0454b963b2558b",
+		"06b4c7f5254d6395b527ac3de60c1d77194df7431d85fe55ca8f107d",
+	}, 4)
+
+	native := NewNativeReferenceScript(*policyScript)
+
+	hash, err := native.GetHash()
+	require.NoError(t, err)
+	require.Equal(t, multisigPolicyID, hash)
+
+	nativeBytes, err := policyScript.MarshalCBOR()
+	require.NoError(t, err)
+
+	size, err := native.GetSize()
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(nativeBytes)), size)
+
+	plutus := NewPlutusReferenceScript(NewPlutusScript(PlutusV2, []byte{0x46, 0x01, 0x00, 0x00, 0x22, 0x20, 0x01}))
+
+	plutusBytes, err := cbor.Marshal(plutus)
+	require.NoError(t, err)
+	require.Equal(t, "d8184a"+"8202"+"47"+"46010000222001", hex.EncodeToString(plutusBytes))
+
+	for _, script := range []*ReferenceScript{native, plutus} {
+		bytes, err := cbor.Marshal(script)
+		require.NoError(t, err)
+
+		var decoded ReferenceScript
+
+		require.NoError(t, cbor.Unmarshal(bytes, &decoded))
+		require.Equal(t, *script, decoded)
+
+		bytes, err = json.Marshal(script)
+		require.NoError(t, err)
+
+		decoded = ReferenceScript{}
+
+		require.NoError(t, json.Unmarshal(bytes, &decoded))
+		require.Equal(t, *script, decoded)
+	}
+
+	_, err = cbor.Marshal(ReferenceScript{})
+	require.Error(t, err)
+}
+
+func TestTxBuilder_ReferenceScripts(t *testing.T) {
+	t.Parallel()
+
+	const (
+		addr       = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		inputHash  = "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"
+		refHash    = "098236134e0f2077a6434dd9d7727126fa8b3627bcab3ae030a194d46eded73e"
+		refPerByte = 15
+	)
+
+	costModels := map[string][]int64{"PlutusV2": {3, 4, 5}}
+	pp := ProtocolParameters{
+		CostModels:                 costModels,
+		TxFeeFixed:                 155381,
+		TxFeePerByte:               44,
+		UtxoCostPerByte:            4310,
+		CollateralPercentage:       150,
+		ExecutionUnitPrices:        NewProtocolParametersPriceMemorySteps(0.0577, 0.0000721),
+		MinFeeRefScriptCostPerByte: refPerByte,
+	}
+
+	ppBytes, err := json.Marshal(pp)
+	require.NoError(t, err)
+
+	policyScript := NewPolicyScript([]string{
+		"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21",
+		"cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41",
+	}, 2)
+	plutusScript := NewPlutusScript(PlutusV2, []byte{0x46, 0x01, 0x00, 0x00, 0x22, 0x20, 0x01})
+
+	nativeRefUtxo := Utxo{Hash: refHash, Index: 0, Amount: 10_000_000, ReferenceScript: NewNativeReferenceScript(*policyScript)}
+	plutusRefUtxo := Utxo{Hash: refHash, Index: 1, Amount: 10_000_000, ReferenceScript: NewPlutusReferenceScript(plutusScript)}
+
+	type decodedTx struct {
+		_          struct{} `cbor:",toarray"`
+		Body       map[int]cbor.RawMessage
+		WitnessSet map[int]cbor.RawMessage
+		IsValid    bool
+		Aux        cbor.RawMessage
+	}
+
+	t.Run("output with reference script", func(t *testing.T) {
+		t.Parallel()
+
+		output := NewTxOutput(addr, 0)
+		output.ReferenceScript = NewNativeReferenceScript(*policyScript)
+
+		minUtxoWithScript, err := GetMinUtxoForOutput(output, pp.UtxoCostPerByte)
+		require.NoError(t, err)
+
+		minUtxo, err := GetMinUtxoForOutput(NewTxOutput(addr, 0), pp.UtxoCostPerByte)
+		require.NoError(t, err)
+		require.Greater(t, minUtxoWithScript, minUtxo)
+
+		output.Amount = minUtxoWithScript
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		txRaw, _, err := builder.SetProtocolParameters(ppBytes).
+			AddInputs(NewTxInput(inputHash, 0)).
+			AddOutputs(output, NewTxOutput(addr, 1_000_000)).
+			SetFee(200_000).
+			Build()
+		require.NoError(t, err)
+
+		var (
+			tx      decodedTx
+			outputs []cbor.RawMessage
+			decoded txRawOutput
+		)
+
+		require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+		require.NoError(t, cbor.Unmarshal(tx.Body[1], &outputs))
+		require.Equal(t, byte(0xa3), outputs[0][0]) // post alonzo output: {0: address, 1: amount, 3: script_ref}
+		require.Equal(t, byte(0x82), outputs[1][0]) // legacy output: [address, amount]
+
+		require.NoError(t, cbor.Unmarshal(outputs[0], &decoded))
+		require.Equal(t, output.ReferenceScript, decoded.ScriptRef)
+		require.Equal(t, minUtxoWithScript, decoded.Amount.Coin)
+	})
+
+	t.Run("native script from reference input", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		builder.SetProtocolParameters(ppBytes).
+			AddUtxosWithReferenceScript(nativeRefUtxo, Utxo{Hash: inputHash, Index: 0, Amount: 5_000_000}).
+			AddOutputs(NewTxOutput(addr, 1_000_000))
+
+		fee, err := builder.Balance(addr)
+		require.NoError(t, err)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		var (
+			tx              decodedTx
+			referenceInputs []txRawInput
+		)
+
+		require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+		require.NotContains(t, tx.WitnessSet, nativeScriptsKey)
+		require.NoError(t, cbor.Unmarshal(tx.Body[18], &referenceInputs))
+		require.Len(t, referenceInputs, 1)
+		require.Equal(t, refHash, hex.EncodeToString(referenceInputs[0].Hash))
+		require.Equal(t, uint32(0), referenceInputs[0].Index)
+
+		// fee contains reference script fee and two (policy script) witnesses
+		// (draft transaction used for the fee is few bytes larger)
+		scriptSize, err := nativeRefUtxo.ReferenceScript.GetSize()
+		require.NoError(t, err)
+
+		expectedFee, err := CalculateMinFee(txRaw, pp, 2, scriptSize)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, fee, expectedFee)
+		require.LessOrEqual(t, fee, expectedFee+10*44)
+		require.Equal(t, refPerByte*scriptSize, GetReferenceScriptsFee(pp, scriptSize))
+	})
+
+	t.Run("plutus script from reference input", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		txRaw, _, err := builder.SetProtocolParameters(ppBytes).
+			AddReferenceInputs(plutusRefUtxo, plutusRefUtxo).
+			AddPlutusScriptInputWithReference(plutusRefUtxo, Utxo{Hash: inputHash, Index: 0, Amount: 5_000_000},
+				[]byte{0x80}, NewRedeemer([]byte{0x80}, NewExUnits(10, 20))).
+			AddCollateralUtxos(Utxo{Hash: refHash, Index: 2, Amount: 5_000_000}).
+			AddOutputs(NewTxOutput(addr, 1_000_000)).
+			SetFee(300_000).
+			Build()
+		require.NoError(t, err)
+
+		var (
+			tx              decodedTx
+			referenceInputs []txRawInput
+		)
+
+		require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+		require.NotContains(t, tx.WitnessSet, 6)
+		require.Contains(t, tx.WitnessSet, redeemersKey)
+		require.Contains(t, tx.Body, 11)
+		require.NoError(t, cbor.Unmarshal(tx.Body[18], &referenceInputs))
+		require.Len(t, referenceInputs, 1)
+
+		// script data hash still uses language views of the referenced script
+		var scriptDataHash []byte
+
+		require.NoError(t, cbor.Unmarshal(tx.Body[11], &scriptDataHash))
+
+		expectedHash, err := getScriptDataHash(tx.WitnessSet[redeemersKey], tx.WitnessSet[4],
+			[]PlutusScriptVersion{PlutusV2}, costModels)
+		require.NoError(t, err)
+		require.Equal(t, expectedHash, scriptDataHash)
+	})
+
+	t.Run("reference utxo without script", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		_, _, err = builder.SetProtocolParameters(ppBytes).
+			AddPlutusScriptInputWithReference(nativeRefUtxo, Utxo{Hash: inputHash, Index: 0, Amount: 5_000_000},
+				nil, NewRedeemer([]byte{0x80}, NewExUnits(10, 20))).
+			AddOutputs(NewTxOutput(addr, 1_000_000)).
+			SetFee(300_000).
+			Build()
+		require.ErrorContains(t, err, "does not contain plutus script")
+
+		builder, err = NewTxBuilder("")
+		require.NoError(t, err)
+
+		_, _, err = builder.SetProtocolParameters(ppBytes).
+			AddInputsWithReferenceScript(plutusRefUtxo, NewTxInput(inputHash, 0)).
+			AddOutputs(NewTxOutput(addr, 1_000_000)).
+			SetFee(300_000).
+			Build()
+		require.ErrorContains(t, err, "does not contain native script")
+	})
+
+	t.Run("reference input is spent", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		builder.SetProtocolParameters(ppBytes).
+			AddUtxos(Utxo{Hash: refHash, Index: 0, Amount: 5_000_000}).
+			AddReferenceInputs(nativeRefUtxo).
+			AddOutputs(NewTxOutput(addr, 1_000_000)).
+			SetFee(300_000)
+
+		_, _, err = builder.Build()
+		require.ErrorIs(t, err, ErrReferenceInputSpent)
+
+		_, err = builder.CalculateFee(0)
+		require.ErrorIs(t, err, ErrReferenceInputSpent)
+
+		builder, err = NewTxBuilder("")
+		require.NoError(t, err)
+
+		_, _, err = builder.SetProtocolParameters(ppBytes).
+			AddUtxosWithReferenceScript(nativeRefUtxo, nativeRefUtxo).
+			AddOutputs(NewTxOutput(addr, 1_000_000)).
+			SetFee(300_000).
+			Build()
+		require.ErrorIs(t, err, ErrReferenceInputSpent)
+	})
+}
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const remoteSignerTimeout = 30 * time.Second
+
+// RemoteSigner is ITxSigner which signs transactions with the key held by the RemoteSignerServer
+type RemoteSigner struct {
+	url             string
+	keyHash         string
+	authToken       string
+	verificationKey []byte
+	txRaw           []byte
+	client          *http.Client
+}
+
+var _ ITxSigner = (*RemoteSigner)(nil)
+
+// NewRemoteSigner creates signer for the key with the key hash. Verification key is retrieved from the server
+func NewRemoteSigner(ctx context.Context, url string, keyHash string, authToken string) (*RemoteSigner, error) {
+	signer := &RemoteSigner{
+		url:       strings.TrimSuffix(url, "/"),
+		keyHash:   keyHash,
+		authToken: authToken,
+		client:    &http.Client{Timeout: remoteSignerTimeout},
+	}
+
+	var keys []RemoteSignerKey
+
+	if err := signer.send(ctx, http.MethodGet, RemoteSignerKeysPath, nil, &keys); err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		if key.KeyHash != keyHash {
+			continue
+		}
+
+		verificationKey, err := hex.DecodeString(key.VerificationKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid verification key of %s: %w", keyHash, err)
+		}
+
+		if actualKeyHash, err := GetKeyHash(verificationKey); err != nil || actualKeyHash != keyHash {
+			return nil, fmt.Errorf("verification key %s does not match key hash %s", key.VerificationKey, keyHash)
+		}
+
+		signer.verificationKey = verificationKey
+
+		return signer, nil
+	}
+
+	return nil, fmt.Errorf("key %s not found on the remote signer", keyHash)
+}
+
+// WithTxRaw returns signer which sends the whole transaction to the server (so the server can check it)
+// instead of only its hash
+func (s RemoteSigner) WithTxRaw(txRaw []byte) *RemoteSigner {
+	s.txRaw = txRaw
+
+	return &s
+}
+
+// SignTransaction implements ITxSigner. Request is limited only by the timeout of the signer,
+// use SignTransactionWithContext to cancel it
+func (s RemoteSigner) SignTransaction(txHash []byte) ([]byte, error) {
+	return s.SignTransactionWithContext(context.Background(), txHash)
+}
+
+// SignTransactionWithContext signs transaction hash on the server. Request is canceled with the context
+func (s RemoteSigner) SignTransactionWithContext(ctx context.Context, txHash []byte) ([]byte, error) {
+	request := RemoteSignRequest{
+		KeyHash: s.keyHash,
+		TxHash:  hex.EncodeToString(txHash),
+	}
+
+	if s.txRaw != nil {
+		request.TxRaw = hex.EncodeToString(s.txRaw)
+	}
+
+	var response RemoteSignResponse
+
+	if err := s.send(ctx, http.MethodPost, RemoteSignerSignPath, request, &response); err != nil {
+		return nil, err
+	}
+
+	signature, err := hex.DecodeString(response.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	// remote signer must not be trusted blindly
+	if err := VerifyMessage(txHash, s.verificationKey, signature); err != nil {
+		return nil, fmt.Errorf("remote signature verification failed: %w", err)
+	}
+
+	return signature, nil
+}
+
+func (s RemoteSigner) GetTransactionVerificationKey() []byte {
+	return s.verificationKey
+}
+
+func (s RemoteSigner) send(ctx context.Context, method, path string, request, response interface{}) error {
+	var body bytes.Buffer
+
+	if request != nil {
+		if err := json.NewEncoder(&body).Encode(request); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.url+path, &body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if s.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.authToken)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResponse remoteSignerErrorResponse
+
+		if err := json.NewDecoder(resp.Body).Decode(&errResponse); err != nil {
+			return fmt.Errorf("remote signer: status code %d", resp.StatusCode)
+		}
+
+		if len(errResponse.Violations) > 0 {
+			return fmt.Errorf("remote signer: %w", &SigningPolicyError{Violations: errResponse.Violations})
+		}
+
+		return fmt.Errorf("remote signer: status code %d: %s", resp.StatusCode, errResponse.Message)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(response)
+}
@@ -0,0 +1,289 @@
+package core
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	RemoteSignerKeysPath = "/keys"
+	RemoteSignerSignPath = "/sign"
+
+	remoteSignerMaxRequestSize = 1 << 20
+)
+
+// RemoteSignerKey is key which can be used for signing on the remote signer server
+type RemoteSignerKey struct {
+	KeyHash         string `json:"keyHash"`
+	VerificationKey string `json:"verificationKey"`
+}
+
+// RemoteSignRequest is request for the signature of the transaction. If TxRaw (hex encoded transaction cbor)
+// is provided, the server calculates transaction hash from it and TxHash is optional
+type RemoteSignRequest struct {
+	KeyHash string `json:"keyHash"`
+	TxHash  string `json:"txHash,omitempty"`
+	TxRaw   string `json:"txRaw,omitempty"`
+}
+
+type RemoteSignResponse struct {
+	Signature       string `json:"signature"`
+	VerificationKey string `json:"verificationKey"`
+}
+
+type remoteSignerErrorResponse struct {
+	Message    string                   `json:"message"`
+	Violations []SigningPolicyViolation `json:"violations,omitempty"`
+}
+
+// ErrRemoteSignerAuthTokenRequired is returned when the remote signer server is created without the auth token
+var ErrRemoteSignerAuthTokenRequired = errors.New("remote signer server requires auth token")
+
+// RemoteSignerServer is http handler which signs transactions with the keys it holds so the keys
+// are not needed in the process which builds transactions. Endpoints:
+// GET /keys returns all the keys and POST /sign signs transaction hash (or body) with the key with the key hash.
+// If the signing policy is set, transactions which violate it are rejected with 403 and the list of violations.
+// The bearer token is sent in cleartext, so the server must be served over TLS (http.ListenAndServeTLS
+// or a TLS terminating proxy) unless it is reachable only from the local host
+type RemoteSignerServer struct {
+	authToken            string
+	allowUnauthenticated bool
+	signers              map[string]ITxSigner
+	signingPolicy        ISigningPolicy
+	lock                 sync.RWMutex
+}
+
+var _ http.Handler = (*RemoteSignerServer)(nil)
+
+// RemoteSignerServerOption defines RemoteSignerServer configuration option
+type RemoteSignerServerOption func(s *RemoteSignerServer)
+
+// WithAllowUnauthenticated allows server without the auth token: anyone who can reach the server
+// can sign with any of its keys. Use it only for servers reachable from the trusted processes
+func WithAllowUnauthenticated() RemoteSignerServerOption {
+	return func(s *RemoteSignerServer) {
+		s.allowUnauthenticated = true
+	}
+}
+
+// NewRemoteSignerServer creates server. Requests must contain authToken as the bearer token.
+// Empty authToken is an error unless WithAllowUnauthenticated option is used
+func NewRemoteSignerServer(authToken string, options ...RemoteSignerServerOption) (*RemoteSignerServer, error) {
+	server := &RemoteSignerServer{
+		authToken: authToken,
+		signers:   map[string]ITxSigner{},
+	}
+
+	for _, option := range options {
+		option(server)
+	}
+
+	if authToken == "" && !server.allowUnauthenticated {
+		return nil, ErrRemoteSignerAuthTokenRequired
+	}
+
+	return server, nil
+}
+
+// AddWallet adds payment and (if exists) stake key of the wallet
+func (s *RemoteSignerServer) AddWallet(wallet *Wallet) error {
+	if _, err := s.AddSigner(wallet); err != nil {
+		return err
+	}
+
+	if len(wallet.StakeSigningKey) == 0 {
+		return nil
+	}
+
+	_, err := s.AddSigner(wallet.GetStakeSigner())
+
+	return err
+}
+
+// AddSigner adds signer and returns key hash of its verification key
+func (s *RemoteSignerServer) AddSigner(signer ITxSigner) (string, error) {
+	keyHash, err := GetKeyHash(signer.GetTransactionVerificationKey())
+	if err != nil {
+		return "", err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.signers[keyHash] = signer
+
+	return keyHash, nil
+}
+
+// SetSigningPolicy sets policy which every transaction must satisfy before it is signed.
+// When policy is set, requests must contain the whole transaction (RemoteSignRequest.TxRaw)
+func (s *RemoteSignerServer) SetSigningPolicy(policy ISigningPolicy) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.signingPolicy = policy
+}
+
+func (s *RemoteSignerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.isAuthorized(r) {
+		writeRemoteSignerError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+
+		return
+	}
+
+	switch {
+	case r.URL.Path == RemoteSignerKeysPath && r.Method == http.MethodGet:
+		s.handleKeys(w)
+	case r.URL.Path == RemoteSignerSignPath && r.Method == http.MethodPost:
+		s.handleSign(w, r)
+	default:
+		writeRemoteSignerError(w, http.StatusNotFound, fmt.Errorf("%s %s not found", r.Method, r.URL.Path))
+	}
+}
+
+func (s *RemoteSignerServer) handleKeys(w http.ResponseWriter) {
+	s.lock.RLock()
+
+	keys := make([]RemoteSignerKey, 0, len(s.signers))
+	for keyHash, signer := range s.signers {
+		keys = append(keys, RemoteSignerKey{
+			KeyHash:         keyHash,
+			VerificationKey: hex.EncodeToString(signer.GetTransactionVerificationKey()),
+		})
+	}
+
+	s.lock.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].KeyHash < keys[j].KeyHash
+	})
+
+	writeRemoteSignerResponse(w, http.StatusOK, keys)
+}
+
+func (s *RemoteSignerServer) handleSign(w http.ResponseWriter, r *http.Request) {
+	var request RemoteSignRequest
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, remoteSignerMaxRequestSize)).Decode(&request); err != nil {
+		writeRemoteSignerError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+
+		return
+	}
+
+	s.lock.RLock()
+	signer, exists := s.signers[request.KeyHash]
+	signingPolicy := s.signingPolicy
+	s.lock.RUnlock()
+
+	if !exists {
+		writeRemoteSignerError(w, http.StatusNotFound, fmt.Errorf("key %s not found", request.KeyHash))
+
+		return
+	}
+
+	txHash, err := request.getTxHash()
+	if err != nil {
+		writeRemoteSignerError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	if signingPolicy != nil {
+		if err := checkSigningPolicy(signingPolicy, request.TxRaw); err != nil {
+			writeRemoteSignerError(w, http.StatusForbidden, err)
+
+			return
+		}
+	}
+
+	signature, err := signer.SignTransaction(txHash)
+	if err != nil {
+		writeRemoteSignerError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	writeRemoteSignerResponse(w, http.StatusOK, RemoteSignResponse{
+		Signature:       hex.EncodeToString(signature),
+		VerificationKey: hex.EncodeToString(signer.GetTransactionVerificationKey()),
+	})
+}
+
+func (s *RemoteSignerServer) isAuthorized(r *http.Request) bool {
+	if s.authToken == "" {
+		return s.allowUnauthenticated
+	}
+
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	return found && subtle.ConstantTimeCompare([]byte(token), []byte(s.authToken)) == 1
+}
+
+// getTxHash returns hash of the transaction body if provided or transaction hash from the request
+func (r RemoteSignRequest) getTxHash() ([]byte, error) {
+	txHash, err := hex.DecodeString(r.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction hash: %s", r.TxHash)
+	}
+
+	if r.TxRaw == "" {
+		if len(txHash) != 32 {
+			return nil, fmt.Errorf("invalid transaction hash: %s", r.TxHash)
+		}
+
+		return txHash, nil
+	}
+
+	txRaw, err := hex.DecodeString(r.TxRaw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction: %w", err)
+	}
+
+	txRawHash, err := GetTxHashBytes(txRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(txHash) > 0 && !bytes.Equal(txHash, txRawHash) {
+		return nil, fmt.Errorf("transaction hash %s does not match transaction %x", r.TxHash, txRawHash)
+	}
+
+	return txRawHash, nil
+}
+
+func checkSigningPolicy(signingPolicy ISigningPolicy, txRawHex string) error {
+	if txRawHex == "" {
+		return errors.New("signing policy requires the whole transaction")
+	}
+
+	// txRaw is already validated by getTxHash
+	txRaw, _ := hex.DecodeString(txRawHex)
+
+	return signingPolicy.Check(txRaw)
+}
+
+func writeRemoteSignerResponse(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func writeRemoteSignerError(w http.ResponseWriter, statusCode int, err error) {
+	response := remoteSignerErrorResponse{Message: err.Error()}
+
+	var policyErr *SigningPolicyError
+	if errors.As(err, &policyErr) {
+		response.Violations = policyErr.Violations
+	}
+
+	writeRemoteSignerResponse(w, statusCode, response)
+}
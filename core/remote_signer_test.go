@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	t.Parallel()
+
+	const (
+		addr      = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		authToken = "secret token"
+	)
+
+	ctx := context.Background()
+
+	wallet, err := GenerateWallet(true)
+	require.NoError(t, err)
+
+	hdWallet, err := NewWalletFromMnemonic(
+		"test walk nut penalty hip pave soap entry language right filter choice", "", 0, 0)
+	require.NoError(t, err)
+
+	signerServer, err := NewRemoteSignerServer(authToken)
+	require.NoError(t, err)
+
+	require.NoError(t, signerServer.AddWallet(wallet))
+	require.NoError(t, signerServer.AddWallet(hdWallet))
+
+	server := httptest.NewServer(signerServer)
+	t.Cleanup(server.Close)
+
+	builder, err := NewTxBuilder("")
+	require.NoError(t, err)
+
+	txRaw, txHash, err := builder.SetProtocolParameters(protocolParameters).
+		AddUtxos(Utxo{
+			Hash:   "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f",
+			Index:  0,
+			Amount: 3_000_000,
+		}).
+		AddOutputs(NewTxOutput(addr, 2_800_000)).SetFee(200_000).Build()
+	require.NoError(t, err)
+
+	otherTxRaw, _, err := builder.SetFee(199_999).Build()
+	require.NoError(t, err)
+
+	txHashBytes, err := hex.DecodeString(txHash)
+	require.NoError(t, err)
+
+	localSigners := []ITxSigner{wallet, wallet.GetStakeSigner(), hdWallet, hdWallet.GetStakeSigner()}
+	remoteSigners := make([]ITxSigner, len(localSigners))
+
+	for i, signer := range localSigners {
+		keyHash, err := GetKeyHash(signer.GetTransactionVerificationKey())
+		require.NoError(t, err)
+
+		remoteSigner, err := NewRemoteSigner(ctx, server.URL+"/", keyHash, authToken)
+		require.NoError(t, err)
+		require.Equal(t, signer.GetTransactionVerificationKey(), remoteSigner.GetTransactionVerificationKey())
+
+		remoteSigners[i] = remoteSigner
+	}
+
+	expectedTx, err := builder.SignTx(txRaw, localSigners)
+	require.NoError(t, err)
+
+	signedTx, err := builder.SignTx(txRaw, remoteSigners)
+	require.NoError(t, err)
+	require.Equal(t, expectedTx, signedTx)
+
+	// transaction body is sent and the server calculates its hash
+	remoteSigner := remoteSigners[0].(*RemoteSigner) //nolint:forcetypeassert
+
+	signedTx, err = builder.SignTx(txRaw, []ITxSigner{remoteSigner.WithTxRaw(txRaw)})
+	require.NoError(t, err)
+
+	expectedTx, err = builder.SignTx(txRaw, localSigners[:1])
+	require.NoError(t, err)
+	require.Equal(t, expectedTx, signedTx)
+
+	witness, err := CreateTxWitness(txHash, remoteSigner.WithTxRaw(txRaw))
+	require.NoError(t, err)
+	require.NoError(t, VerifyWitness(txHash, witness))
+
+	_, err = CreateTxWitness(txHash, remoteSigner.WithTxRaw(otherTxRaw))
+	require.ErrorContains(t, err, "does not match transaction")
+
+	_, err = remoteSigner.SignTransaction([]byte{1, 2, 3})
+	require.ErrorContains(t, err, "invalid transaction hash")
+
+	signature, err := remoteSigner.SignTransactionWithContext(ctx, txHashBytes)
+	require.NoError(t, err)
+	require.NoError(t, VerifyMessage(txHashBytes, remoteSigner.GetTransactionVerificationKey(), signature))
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err = remoteSigner.SignTransactionWithContext(canceledCtx, txHashBytes)
+	require.ErrorIs(t, err, context.Canceled)
+
+	t.Run("invalid requests", func(t *testing.T) {
+		t.Parallel()
+
+		keyHash, err := GetKeyHash(wallet.VerificationKey)
+		require.NoError(t, err)
+
+		_, err = NewRemoteSigner(ctx, server.URL, keyHash, "wrong token")
+		require.ErrorContains(t, err, "unauthorized")
+
+		_, err = NewRemoteSigner(ctx, server.URL, "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21", authToken)
+		require.ErrorContains(t, err, "not found")
+
+		// key removed from the server after the client was created
+		_, err = NewRemoteSignerServer("")
+		require.ErrorIs(t, err, ErrRemoteSignerAuthTokenRequired)
+
+		unauthenticatedServer, err := NewRemoteSignerServer("", WithAllowUnauthenticated())
+		require.NoError(t, err)
+
+		otherServer := httptest.NewServer(unauthenticatedServer)
+		t.Cleanup(otherServer.Close)
+
+		otherSigner := *remoteSigner
+		otherSigner.url = otherServer.URL
+
+		_, err = otherSigner.SignTransaction(make([]byte, 32))
+		require.ErrorContains(t, err, "not found")
+	})
+}
@@ -0,0 +1,442 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+// SigningPolicyRule identifies the rule of the signing policy
+type SigningPolicyRule string
+
+const (
+	SigningPolicyRuleDestinationAddress   SigningPolicyRule = "destinationAddress"
+	SigningPolicyRuleMaxLovelace          SigningPolicyRule = "maxLovelace"
+	SigningPolicyRuleForbiddenCertificate SigningPolicyRule = "forbiddenCertificate"
+	SigningPolicyRuleRequiredMetadata     SigningPolicyRule = "requiredMetadata"
+	SigningPolicyRuleMint                 SigningPolicyRule = "mint"
+	SigningPolicyRuleMaxFee               SigningPolicyRule = "maxFee"
+	SigningPolicyRuleUnknownBodyField     SigningPolicyRule = "unknownBodyField"
+)
+
+// CertificateType is the tag of the certificate as defined in the ledger cddl
+type CertificateType uint64
+
+const (
+	CertificateTypeStakeRegistration               CertificateType = certStakeRegistrationTag
+	CertificateTypeStakeDeregistration             CertificateType = certStakeDeregistrationTag
+	CertificateTypeStakeDelegation                 CertificateType = certStakeDelegationTag
+	CertificateTypePoolRegistration                CertificateType = 3
+	CertificateTypePoolRetirement                  CertificateType = 4
+	CertificateTypeRegistration                    CertificateType = certRegistrationTag
+	CertificateTypeDeregistration                  CertificateType = certDeregistrationTag
+	CertificateTypeVoteDelegation                  CertificateType = certVoteDelegationTag
+	CertificateTypeStakeVoteDelegation             CertificateType = 10
+	CertificateTypeStakeRegistrationDelegation     CertificateType = certStakeRegistrationDelegationTag
+	CertificateTypeVoteRegistrationDelegation      CertificateType = 12
+	CertificateTypeStakeVoteRegistrationDelegation CertificateType = 13
+	CertificateTypeAuthCommitteeHot                CertificateType = 14
+	CertificateTypeResignCommitteeCold             CertificateType = 15
+	CertificateTypeDRepRegistration                CertificateType = certDRepRegistrationTag
+	CertificateTypeDRepRetirement                  CertificateType = certDRepRetirementTag
+	CertificateTypeDRepUpdate                      CertificateType = certDRepUpdateTag
+)
+
+// ISigningPolicy checks the whole transaction before it is signed
+type ISigningPolicy interface {
+	// Check returns *SigningPolicyError if the transaction violates the policy
+	Check(txRaw []byte) error
+}
+
+// SigningPolicyViolation is the reason why the transaction is rejected
+type SigningPolicyViolation struct {
+	Rule    SigningPolicyRule `json:"rule"`
+	Message string            `json:"message"`
+}
+
+// SigningPolicyError is returned when the transaction violates one or more rules of the signing policy
+type SigningPolicyError struct {
+	Violations []SigningPolicyViolation
+}
+
+func (e *SigningPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = fmt.Sprintf("%s: %s", violation.Rule, violation.Message)
+	}
+
+	return "transaction rejected by the signing policy: " + strings.Join(messages, "; ")
+}
+
+// SigningPolicy is ISigningPolicy with the most common rules. Empty (zero) rules are not checked.
+// Transactions with body fields unknown to the policy are always rejected
+type SigningPolicy struct {
+	// AllowedAddresses are the only addresses outputs can be sent to and the only reward addresses
+	// proposal deposits can be returned to (besides ChangeAddresses)
+	AllowedAddresses []string
+	// ChangeAddresses are always allowed and lovelace sent to them is not counted in MaxLovelace
+	ChangeAddresses []string
+	// MaxLovelace is the maximum amount of lovelace transaction spends: outputs to addresses other than
+	// ChangeAddresses, fee, treasury donation and proposal deposits
+	MaxLovelace uint64
+	// MaxFee is the maximum fee of the transaction
+	MaxFee uint64
+	// ForbiddenCertificates are the certificates transaction must not contain
+	ForbiddenCertificates []CertificateType
+	// RequiredMetadataKeys are the labels which transaction metadata must contain
+	RequiredMetadataKeys []uint64
+	// ForbidMint rejects transactions which mint or burn tokens
+	ForbidMint bool
+}
+
+var _ ISigningPolicy = (*SigningPolicy)(nil)
+
+const (
+	txBodyProposalProceduresKey = 20
+	txBodyTreasuryDonationKey   = 22
+)
+
+// signingPolicyBodyKeys are the keys of the conway transaction body understood by the signing policy.
+// Withdrawals (5) are not checked because withdrawn lovelace can be spent only by outputs, fee or donation
+var signingPolicyBodyKeys = map[uint64]bool{
+	0: true, 1: true, 2: true, 3: true, 4: true, 5: true, 7: true, 8: true, 9: true, 11: true, 13: true,
+	14: true, 15: true, 16: true, 17: true, 18: true, 19: true, 20: true, 21: true, 22: true,
+}
+
+// txRawProposalProcedure is proposal_procedure from the ledger cddl: [deposit, reward_account, gov_action, anchor]
+type txRawProposalProcedure struct {
+	_             struct{} `cbor:",toarray"`
+	Deposit       uint64
+	RewardAccount []byte
+	GovAction     cbor.RawMessage
+	Anchor        cbor.RawMessage
+}
+
+func (p SigningPolicy) Check(txRaw []byte) error {
+	var tx []cbor.RawMessage
+
+	if err := cbor.Unmarshal(txRaw, &tx); err != nil {
+		return fmt.Errorf("invalid transaction cbor: %w", err)
+	} else if len(tx) == 0 {
+		return errors.New("invalid transaction cbor: body not found")
+	}
+
+	var bodyMap map[uint64]cbor.RawMessage
+
+	if err := cbor.Unmarshal(tx[0], &bodyMap); err != nil {
+		return fmt.Errorf("invalid transaction body: %w", err)
+	}
+
+	var (
+		body     txRawBody
+		donation uint64
+	)
+
+	if err := cbor.Unmarshal(tx[0], &body); err != nil {
+		return fmt.Errorf("invalid transaction body: %w", err)
+	}
+
+	if raw, exists := bodyMap[txBodyTreasuryDonationKey]; exists {
+		if err := cbor.Unmarshal(raw, &donation); err != nil {
+			return fmt.Errorf("invalid treasury donation: %w", err)
+		}
+	}
+
+	violations := p.checkBodyKeys(bodyMap)
+
+	outputsLovelace, outputViolations, err := p.checkOutputs(body)
+	if err != nil {
+		return err
+	}
+
+	violations = append(violations, outputViolations...)
+
+	proposalsDeposit, proposalViolations, err := p.checkProposals(bodyMap[txBodyProposalProceduresKey])
+	if err != nil {
+		return err
+	}
+
+	violations = append(violations, proposalViolations...)
+
+	if p.MaxFee > 0 && body.Fee > p.MaxFee {
+		violations = append(violations, SigningPolicyViolation{
+			Rule:    SigningPolicyRuleMaxFee,
+			Message: fmt.Sprintf("transaction fee is %d lovelace, maximum is %d", body.Fee, p.MaxFee),
+		})
+	}
+
+	lovelace := addLovelaceSaturated(outputsLovelace, body.Fee, donation, proposalsDeposit)
+
+	if p.MaxLovelace > 0 && lovelace > p.MaxLovelace {
+		violations = append(violations, SigningPolicyViolation{
+			Rule: SigningPolicyRuleMaxLovelace,
+			Message: fmt.Sprintf("transaction spends %d lovelace (outputs %d, fee %d, donation %d, proposal deposits %d), maximum is %d",
+				lovelace, outputsLovelace, body.Fee, donation, proposalsDeposit, p.MaxLovelace),
+		})
+	}
+
+	certificateViolations, err := p.checkCertificates(body.Certificates)
+	if err != nil {
+		return err
+	}
+
+	violations = append(violations, certificateViolations...)
+
+	if p.ForbidMint && len(body.Mint) > 0 {
+		violations = append(violations, SigningPolicyViolation{
+			Rule:    SigningPolicyRuleMint,
+			Message: fmt.Sprintf("transaction mints or burns tokens of %d policies", len(body.Mint)),
+		})
+	}
+
+	if len(p.RequiredMetadataKeys) > 0 {
+		var auxiliaryData cbor.RawMessage
+		if len(tx) > 3 {
+			auxiliaryData = tx[3]
+		}
+
+		metadataViolations, err := p.checkMetadata(auxiliaryData, body.AuxiliaryDataHash)
+		if err != nil {
+			return err
+		}
+
+		violations = append(violations, metadataViolations...)
+	}
+
+	if len(violations) > 0 {
+		return &SigningPolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+func (p SigningPolicy) checkBodyKeys(bodyMap map[uint64]cbor.RawMessage) (violations []SigningPolicyViolation) {
+	keys := make([]uint64, 0, len(bodyMap))
+
+	for key := range bodyMap {
+		if !signingPolicyBodyKeys[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		violations = append(violations, SigningPolicyViolation{
+			Rule:    SigningPolicyRuleUnknownBodyField,
+			Message: fmt.Sprintf("transaction body field %d is not supported", key),
+		})
+	}
+
+	return violations
+}
+
+// checkOutputs checks destination addresses and returns lovelace sent to addresses other than change addresses
+func (p SigningPolicy) checkOutputs(body txRawBody) (lovelace uint64, violations []SigningPolicyViolation, err error) {
+	outputs := body.Outputs
+	if body.CollateralReturn != nil {
+		outputs = append(slices.Clip(outputs), *body.CollateralReturn)
+	}
+
+	for _, output := range outputs {
+		address, err := NewCardanoAddress(output.Address)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid output address %s: %w", hex.EncodeToString(output.Address), err)
+		}
+
+		addr := address.String()
+
+		if slices.Contains(p.ChangeAddresses, addr) {
+			continue
+		}
+
+		lovelace = addLovelaceSaturated(lovelace, output.Amount.Coin)
+
+		if !p.isAddressAllowed(addr) {
+			violations = append(violations, SigningPolicyViolation{
+				Rule:    SigningPolicyRuleDestinationAddress,
+				Message: fmt.Sprintf("address %s is not allowed", addr),
+			})
+		}
+	}
+
+	return lovelace, violations, nil
+}
+
+// checkProposals checks reward addresses of the proposals and returns total deposit of the proposals
+func (p SigningPolicy) checkProposals(
+	proposalsRaw cbor.RawMessage,
+) (deposit uint64, violations []SigningPolicyViolation, err error) {
+	if len(proposalsRaw) == 0 {
+		return 0, nil, nil
+	}
+
+	var proposals []txRawProposalProcedure
+
+	if err := cbor.Unmarshal(proposalsRaw, &proposals); err != nil {
+		return 0, nil, fmt.Errorf("invalid proposal procedures: %w", err)
+	}
+
+	for i, proposal := range proposals {
+		address, err := NewCardanoAddress(proposal.RewardAccount)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid reward address of proposal %d: %w", i, err)
+		}
+
+		deposit = addLovelaceSaturated(deposit, proposal.Deposit)
+
+		if addr := address.String(); !p.isAddressAllowed(addr) && !slices.Contains(p.ChangeAddresses, addr) {
+			violations = append(violations, SigningPolicyViolation{
+				Rule:    SigningPolicyRuleDestinationAddress,
+				Message: fmt.Sprintf("reward address %s of proposal %d is not allowed", addr, i),
+			})
+		}
+	}
+
+	return deposit, violations, nil
+}
+
+func (p SigningPolicy) isAddressAllowed(addr string) bool {
+	return len(p.AllowedAddresses) == 0 || slices.Contains(p.AllowedAddresses, addr)
+}
+
+func (p SigningPolicy) checkCertificates(certificates []cbor.RawMessage) (violations []SigningPolicyViolation, err error) {
+	if len(p.ForbiddenCertificates) == 0 {
+		return nil, nil
+	}
+
+	for i, certificate := range certificates {
+		var fields []cbor.RawMessage
+
+		if err := cbor.Unmarshal(certificate, &fields); err != nil || len(fields) == 0 {
+			return nil, fmt.Errorf("invalid certificate at index %d: %x", i, []byte(certificate))
+		}
+
+		var certificateType CertificateType
+
+		if err := cbor.Unmarshal(fields[0], &certificateType); err != nil {
+			return nil, fmt.Errorf("invalid certificate at index %d: %w", i, err)
+		}
+
+		if slices.Contains(p.ForbiddenCertificates, certificateType) {
+			violations = append(violations, SigningPolicyViolation{
+				Rule:    SigningPolicyRuleForbiddenCertificate,
+				Message: fmt.Sprintf("certificate of type %d at index %d is forbidden", certificateType, i),
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+func (p SigningPolicy) checkMetadata(
+	auxiliaryData cbor.RawMessage, auxiliaryDataHash []byte,
+) (violations []SigningPolicyViolation, err error) {
+	labels := map[uint64]cbor.RawMessage{}
+
+	if len(auxiliaryData) > 0 && !bytes.Equal(auxiliaryData, cborNull) {
+		// metadata which is not covered by the body hash could be replaced after signing
+		actualHash := blake2b.Sum256(auxiliaryData)
+		if !bytes.Equal(actualHash[:], auxiliaryDataHash) {
+			return nil, fmt.Errorf("auxiliary data hash %x does not match auxiliary data", auxiliaryDataHash)
+		}
+
+		labels, err = getMetadataFromAuxiliaryData(auxiliaryData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, key := range p.RequiredMetadataKeys {
+		if _, exists := labels[key]; !exists {
+			violations = append(violations, SigningPolicyViolation{
+				Rule:    SigningPolicyRuleRequiredMetadata,
+				Message: fmt.Sprintf("metadata key %d is missing", key),
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+// CreateTxWitnessWithPolicy checks the whole transaction with the signing policy and creates witness
+// only if the transaction satisfies the policy
+func CreateTxWitnessWithPolicy(txRaw []byte, signer ITxSigner, policy ISigningPolicy) ([]byte, error) {
+	if err := policy.Check(txRaw); err != nil {
+		return nil, err
+	}
+
+	txHash, err := GetTxHash(txRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateTxWitness(txHash, signer)
+}
+
+var cborNull = []byte{0xf6}
+
+// addLovelaceSaturated sums amounts. Sum larger than math.MaxUint64 is math.MaxUint64 so it can not wrap
+// below the limits of the policy
+func addLovelaceSaturated(amounts ...uint64) (sum uint64) {
+	for _, amount := range amounts {
+		var carry uint64
+
+		if sum, carry = bits.Add64(sum, amount, 0); carry != 0 {
+			return math.MaxUint64
+		}
+	}
+
+	return sum
+}
+
+// getMetadataFromAuxiliaryData returns metadata of any auxiliary data format:
+// shelley (metadata map), allegra ([metadata, scripts]) or alonzo (#6.259({ 0: metadata }))
+func getMetadataFromAuxiliaryData(auxiliaryData []byte) (map[uint64]cbor.RawMessage, error) {
+	var (
+		metadata map[uint64]cbor.RawMessage
+		err      error
+	)
+
+	switch auxiliaryData[0] >> 5 {
+	case cborMajorTypeMap:
+		err = cbor.Unmarshal(auxiliaryData, &metadata)
+	case cborMajorTypeArray:
+		var allegra []cbor.RawMessage
+
+		if err = cbor.Unmarshal(auxiliaryData, &allegra); err == nil && len(allegra) > 0 {
+			err = cbor.Unmarshal(allegra[0], &metadata)
+		}
+	case cborMajorTypeTag:
+		var alonzo struct {
+			Metadata map[uint64]cbor.RawMessage `cbor:"0,keyasint,omitempty"`
+		}
+
+		var tag cbor.RawTag
+
+		if err = cbor.Unmarshal(auxiliaryData, &tag); err == nil {
+			if tag.Number != auxiliaryDataAlonzoTag {
+				return nil, fmt.Errorf("invalid auxiliary data tag %d", tag.Number)
+			}
+
+			err = cbor.Unmarshal(tag.Content, &alonzo)
+			metadata = alonzo.Metadata
+		}
+	default:
+		return nil, fmt.Errorf("invalid auxiliary data: %x", auxiliaryData)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid auxiliary data: %w", err)
+	}
+
+	return metadata, nil
+}
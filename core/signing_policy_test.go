@@ -0,0 +1,315 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningPolicy(t *testing.T) {
+	t.Parallel()
+
+	const (
+		addr       = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		changeAddr = "addr_test1qz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3jcu5d8ps7zex2k2xt3uqxgjqnnj83ws8lhrn648jjxtwq2ytjqp"
+		otherAddr  = "addr_test1vz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzerspjrlsz"
+		stakeAddr  = "stake_test1uqevw2xnsc0pvn9t9r9c7qryfqfeerchgrlm3ea2nefr9hqp8n5xl"
+	)
+
+	utxo := Utxo{
+		Hash:   "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f",
+		Index:  0,
+		Amount: 10_000_000,
+	}
+
+	mintScript := NewSigPolicyScript("d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21")
+
+	mintPolicyID, err := mintScript.GetPolicyID()
+	require.NoError(t, err)
+
+	registration, err := NewStakeRegistrationCertificate(stakeAddr)
+	require.NoError(t, err)
+
+	deregistration, err := NewStakeDeregistrationCertificate(stakeAddr)
+	require.NoError(t, err)
+
+	build := func(t *testing.T, modify func(*TxBuilder)) []byte {
+		t.Helper()
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		builder.SetProtocolParameters(protocolParameters).AddUtxos(utxo).SetFee(200_000)
+		modify(builder)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		return txRaw
+	}
+
+	policy := SigningPolicy{
+		AllowedAddresses:      []string{addr},
+		ChangeAddresses:       []string{changeAddr},
+		MaxLovelace:           3_000_000,
+		ForbiddenCertificates: []CertificateType{CertificateTypeStakeDeregistration},
+		RequiredMetadataKeys:  []uint64{674},
+		ForbidMint:            true,
+	}
+
+	getViolations := func(t *testing.T, err error) []SigningPolicyRule {
+		t.Helper()
+
+		var policyErr *SigningPolicyError
+
+		require.ErrorAs(t, err, &policyErr)
+
+		rules := make([]SigningPolicyRule, len(policyErr.Violations))
+		for i, violation := range policyErr.Violations {
+			rules[i] = violation.Rule
+		}
+
+		return rules
+	}
+
+	validTxRaw := build(t, func(b *TxBuilder) {
+		b.AddOutputs(NewTxOutput(addr, 2_000_000), NewTxOutput(changeAddr, 7_800_000)).
+			SetMetaData([]byte(`{"674": {"msg": ["payout"]}}`)).
+			AddCertificates(registration)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, policy.Check(validTxRaw))
+		require.NoError(t, SigningPolicy{}.Check(validTxRaw))
+
+		wallet, err := GenerateWallet(false)
+		require.NoError(t, err)
+
+		witness, err := CreateTxWitnessWithPolicy(validTxRaw, wallet, policy)
+		require.NoError(t, err)
+
+		txHash, err := GetTxHash(validTxRaw)
+		require.NoError(t, err)
+
+		require.NoError(t, VerifyWitness(txHash, witness))
+	})
+
+	t.Run("all rules violated", func(t *testing.T) {
+		t.Parallel()
+
+		txRaw := build(t, func(b *TxBuilder) {
+			b.AddOutputs(NewTxOutput(otherAddr, 3_500_000, NewTokenAmount(mintPolicyID, "token", 1)),
+				NewTxOutput(changeAddr, 6_300_000)).
+				SetMetaData([]byte(`{"1": "other"}`)).
+				AddCertificates(registration, deregistration).
+				AddTokenMints([]IPolicyScript{mintScript}, []TokenAmount{NewTokenAmount(mintPolicyID, "token", 1)})
+		})
+
+		wallet, err := GenerateWallet(false)
+		require.NoError(t, err)
+
+		witness, err := CreateTxWitnessWithPolicy(txRaw, wallet, policy)
+		require.Nil(t, witness)
+		require.Equal(t, []SigningPolicyRule{
+			SigningPolicyRuleDestinationAddress,
+			SigningPolicyRuleMaxLovelace,
+			SigningPolicyRuleForbiddenCertificate,
+			SigningPolicyRuleMint,
+			SigningPolicyRuleRequiredMetadata,
+		}, getViolations(t, err))
+		require.ErrorContains(t, err, "address "+otherAddr+" is not allowed")
+		require.ErrorContains(t, err, "certificate of type 1 at index 1 is forbidden")
+		require.ErrorContains(t, err, "metadata key 674 is missing")
+	})
+
+	t.Run("change is not counted", func(t *testing.T) {
+		t.Parallel()
+
+		txRaw := build(t, func(b *TxBuilder) {
+			b.AddOutputs(NewTxOutput(addr, 3_000_001), NewTxOutput(changeAddr, 6_799_999))
+		})
+
+		// fee is counted
+		require.NoError(t, SigningPolicy{MaxLovelace: 3_200_001, ChangeAddresses: []string{changeAddr}}.Check(txRaw))
+		require.Equal(t, []SigningPolicyRule{SigningPolicyRuleMaxLovelace},
+			getViolations(t, SigningPolicy{MaxLovelace: 3_200_000, ChangeAddresses: []string{changeAddr}}.Check(txRaw)))
+		require.Equal(t, []SigningPolicyRule{SigningPolicyRuleMaxLovelace},
+			getViolations(t, SigningPolicy{MaxLovelace: 9_000_000}.Check(txRaw)))
+	})
+
+	t.Run("fee, donation and unknown body fields", func(t *testing.T) {
+		t.Parallel()
+
+		txRaw := build(t, func(b *TxBuilder) {
+			b.AddOutputs(NewTxOutput(addr, 1_000_000), NewTxOutput(changeAddr, 8_800_000)).SetFee(200_000)
+		})
+
+		policy := SigningPolicy{
+			AllowedAddresses: []string{addr}, ChangeAddresses: []string{changeAddr}, MaxLovelace: 2_000_000, MaxFee: 300_000,
+		}
+
+		require.NoError(t, policy.Check(txRaw))
+
+		// returns transaction with the body changed by modify
+		modifyBody := func(t *testing.T, modify func(map[uint64]cbor.RawMessage)) []byte {
+			t.Helper()
+
+			var tx []cbor.RawMessage
+
+			require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+
+			var body map[uint64]cbor.RawMessage
+
+			require.NoError(t, cbor.Unmarshal(tx[0], &body))
+
+			modify(body)
+
+			bodyRaw, err := cbor.Marshal(body)
+			require.NoError(t, err)
+
+			tx[0] = bodyRaw
+
+			result, err := cbor.Marshal(tx)
+			require.NoError(t, err)
+
+			return result
+		}
+
+		donationTxRaw := modifyBody(t, func(body map[uint64]cbor.RawMessage) {
+			body[2], _ = cbor.Marshal(uint64(900_000_000_000))
+			body[22], _ = cbor.Marshal(uint64(500_000_000_000))
+		})
+
+		err := policy.Check(donationTxRaw)
+		require.Equal(t, []SigningPolicyRule{SigningPolicyRuleMaxFee, SigningPolicyRuleMaxLovelace}, getViolations(t, err))
+		require.ErrorContains(t, err, "fee 900000000000, donation 500000000000")
+
+		// sum which overflows uint64 must not wrap
+		overflowTxRaw := modifyBody(t, func(body map[uint64]cbor.RawMessage) {
+			body[22], _ = cbor.Marshal(uint64(math.MaxUint64 - 100_000))
+		})
+
+		require.Equal(t, []SigningPolicyRule{SigningPolicyRuleMaxLovelace},
+			getViolations(t, policy.Check(overflowTxRaw)))
+
+		unknownTxRaw := modifyBody(t, func(body map[uint64]cbor.RawMessage) {
+			body[6], _ = cbor.Marshal([]interface{}{})
+			body[30], _ = cbor.Marshal(uint64(1))
+		})
+
+		err = policy.Check(unknownTxRaw)
+		require.Equal(t, []SigningPolicyRule{SigningPolicyRuleUnknownBodyField, SigningPolicyRuleUnknownBodyField},
+			getViolations(t, err))
+		require.ErrorContains(t, err, "transaction body field 6 is not supported")
+	})
+
+	t.Run("proposals", func(t *testing.T) {
+		t.Parallel()
+
+		anchor := NewAnchor("https://example.com/rationale.json", []byte("rationale"))
+		pp := protocolParameters
+
+		build := func(t *testing.T, rewardAddr string) []byte {
+			t.Helper()
+
+			builder, err := NewTxBuilder("")
+			require.NoError(t, err)
+
+			txRaw, _, err := builder.SetProtocolParameters(pp).AddUtxos(utxo).
+				AddProposals(ProposalProcedure{
+					Deposit:       5_000_000,
+					RewardAddress: rewardAddr,
+					Action:        InfoAction{},
+					Anchor:        anchor,
+				}).
+				AddOutputs(NewTxOutput(changeAddr, 4_800_000)).SetFee(200_000).Build()
+			require.NoError(t, err)
+
+			return txRaw
+		}
+
+		policy := SigningPolicy{AllowedAddresses: []string{addr}, ChangeAddresses: []string{changeAddr, stakeAddr}}
+
+		require.NoError(t, policy.Check(build(t, stakeAddr)))
+
+		otherStakeAddr, err := NewRewardAddress(TestNetNetwork, make([]byte, KeySize))
+		require.NoError(t, err)
+
+		err = policy.Check(build(t, otherStakeAddr.String()))
+		require.Equal(t, []SigningPolicyRule{SigningPolicyRuleDestinationAddress}, getViolations(t, err))
+		require.ErrorContains(t, err, "reward address "+otherStakeAddr.String()+" of proposal 0 is not allowed")
+
+		policy.MaxLovelace = 5_199_999
+
+		require.Equal(t, []SigningPolicyRule{SigningPolicyRuleMaxLovelace},
+			getViolations(t, policy.Check(build(t, stakeAddr))))
+	})
+
+	t.Run("metadata without transaction metadata", func(t *testing.T) {
+		t.Parallel()
+
+		txRaw := build(t, func(b *TxBuilder) {
+			b.AddOutputs(NewTxOutput(addr, 9_800_000))
+		})
+
+		require.Equal(t, []SigningPolicyRule{SigningPolicyRuleRequiredMetadata},
+			getViolations(t, SigningPolicy{RequiredMetadataKeys: []uint64{674}}.Check(txRaw)))
+	})
+
+	t.Run("invalid transaction", func(t *testing.T) {
+		t.Parallel()
+
+		err := policy.Check([]byte{0x80})
+		require.Error(t, err)
+		require.False(t, errors.As(err, new(*SigningPolicyError)))
+	})
+
+	t.Run("remote signer", func(t *testing.T) {
+		t.Parallel()
+
+		wallet, err := GenerateWallet(false)
+		require.NoError(t, err)
+
+		signerServer, err := NewRemoteSignerServer("secret token")
+		require.NoError(t, err)
+
+		signerServer.SetSigningPolicy(policy)
+
+		keyHash, err := signerServer.AddSigner(wallet)
+		require.NoError(t, err)
+
+		server := httptest.NewServer(signerServer)
+		t.Cleanup(server.Close)
+
+		remoteSigner, err := NewRemoteSigner(context.Background(), server.URL, keyHash, "secret token")
+		require.NoError(t, err)
+
+		txHash, err := GetTxHashBytes(validTxRaw)
+		require.NoError(t, err)
+
+		// policy can not be checked without the whole transaction
+		_, err = remoteSigner.SignTransaction(txHash)
+		require.ErrorContains(t, err, "status code 403: signing policy requires the whole transaction")
+
+		signature, err := remoteSigner.WithTxRaw(validTxRaw).SignTransaction(txHash)
+		require.NoError(t, err)
+		require.NoError(t, VerifyMessage(txHash, wallet.GetTransactionVerificationKey(), signature))
+
+		txRaw := build(t, func(b *TxBuilder) {
+			b.AddOutputs(NewTxOutput(addr, 2_000_000), NewTxOutput(changeAddr, 7_800_000))
+		})
+
+		txHash, err = GetTxHashBytes(txRaw)
+		require.NoError(t, err)
+
+		_, err = remoteSigner.WithTxRaw(txRaw).SignTransaction(txHash)
+		require.Equal(t, []SigningPolicyRule{SigningPolicyRuleRequiredMetadata}, getViolations(t, err))
+	})
+}
@@ -4,18 +4,12 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strconv"
+	"math"
 	"strings"
 
 	"github.com/fxamacker/cbor/v2"
 )
 
-const (
-	draftTxFile = "tx.draft"
-)
-
 // CreateTxWitness signs transaction hash and creates witness cbor
 func CreateTxWitness(txHash string, signer ITxSigner) ([]byte, error) {
 	txHashBytes, err := hex.DecodeString(txHash)
@@ -48,9 +42,18 @@ func (i TxInput) String() string {
 }
 
 type TxOutput struct {
-	Addr   string        `json:"addr"`
-	Amount uint64        `json:"amount"`
+	Addr string `json:"addr"`
+	// Value of the output. If it is zero, Amount and Tokens are used instead
+	Value Value `json:"value"`
+	// Deprecated: Amount is kept for compatibility, use Value
+	Amount uint64 `json:"amount"`
+	// Deprecated: Tokens are kept for compatibility, use Value
 	Tokens []TokenAmount `json:"token,omitempty"`
+	// DatumHash (hex) or InlineDatum (cbor of the plutus data) is attached to the script locked output
+	DatumHash   string `json:"datumHash,omitempty"`
+	InlineDatum []byte `json:"inlineDatum,omitempty"`
+	// ReferenceScript is stored in the output so other transactions can use it instead of attaching the script
+	ReferenceScript *ReferenceScript `json:"refScript,omitempty"`
 }
 
 func NewTxOutput(addr string, amount uint64, tokens ...TokenAmount) TxOutput {
@@ -64,9 +67,11 @@ func NewTxOutput(addr string, amount uint64, tokens ...TokenAmount) TxOutput {
 func (o TxOutput) String() string {
 	var sb strings.Builder
 
-	sb.WriteString(fmt.Sprintf("%s+%d", o.Addr, o.Amount))
+	amount, tokens := o.getAmountAndTokens()
 
-	for _, token := range o.Tokens {
+	sb.WriteString(fmt.Sprintf("%s+%d", o.Addr, amount))
+
+	for _, token := range tokens {
 		sb.WriteRune('+')
 		sb.WriteString(token.String())
 	}
@@ -75,34 +80,36 @@ func (o TxOutput) String() string {
 }
 
 type TxBuilder struct {
-	baseDirectory      string
 	inputs             []txInputWithPolicyScript
 	outputs            []TxOutput
 	mints              txTokenMintInputs
 	metadata           []byte
 	protocolParameters []byte
 	timeToLive         uint64
+	validityStart      uint64
 	testNetMagic       uint
 	fee                uint64
-	cardanoCliBinary   string
-}
-
-func NewTxBuilder(cardanoCliBinary string) (*TxBuilder, error) {
-	baseDirectory, err := os.MkdirTemp("", "cardano-txs")
-	if err != nil {
-		return nil, err
-	}
+	autoMinUtxo        bool
+	witnessCount       int
 
-	return &TxBuilder{
-		baseDirectory:    baseDirectory,
-		cardanoCliBinary: cardanoCliBinary,
-	}, nil
+	collateralInputs     []Utxo
+	collateralReturnAddr string
+	referenceInputs      []Utxo
+	certificates         []txCertificate
+	withdrawals          []txWithdrawal
+	votes                []txVote
+	proposals            []ProposalProcedure
 }
 
-func (b *TxBuilder) Dispose() {
-	os.RemoveAll(b.baseDirectory)
+// NewTxBuilder creates new transaction builder. Transactions are built natively so cardano-cli binary
+// is not used anymore - parameter and error are kept for backward compatibility
+func NewTxBuilder(_ string) (*TxBuilder, error) {
+	return &TxBuilder{}, nil
 }
 
+// Dispose is kept for backward compatibility - builder does not hold any resources anymore
+func (b *TxBuilder) Dispose() {}
+
 func (b *TxBuilder) SetTestNetMagic(testNetMagic uint) *TxBuilder {
 	b.testNetMagic = testNetMagic
 
@@ -142,6 +149,8 @@ func (b *TxBuilder) AddInputsWithScripts(inputs []TxInput, scripts []IPolicyScri
 	return b
 }
 
+// AddInputs adds inputs without their utxos. Reference scripts stored in such inputs are unknown, so their
+// size is not included in the fee which is then underestimated. Use AddUtxos for utxos with reference scripts
 func (b *TxBuilder) AddInputs(inputs ...TxInput) *TxBuilder {
 	for _, inp := range inputs {
 		b.inputs = append(b.inputs, txInputWithPolicyScript{
@@ -152,6 +161,26 @@ func (b *TxBuilder) AddInputs(inputs ...TxInput) *TxBuilder {
 	return b
 }
 
+// AddUtxos adds utxos as inputs. Value of every utxo is remembered so the builder can balance the transaction
+func (b *TxBuilder) AddUtxos(utxos ...Utxo) *TxBuilder {
+	return b.AddUtxosWithScript(nil, utxos...)
+}
+
+// AddUtxosWithScript adds utxos as inputs which are spent with the provided policy script
+func (b *TxBuilder) AddUtxosWithScript(script IPolicyScript, utxos ...Utxo) *TxBuilder {
+	for _, utxo := range utxos {
+		utxo := utxo
+
+		b.inputs = append(b.inputs, txInputWithPolicyScript{
+			txInput:      NewTxInput(utxo.Hash, utxo.Index),
+			policyScript: script,
+			utxo:         &utxo,
+		})
+	}
+
+	return b
+}
+
 func (b *TxBuilder) AddOutputs(outputs ...TxOutput) *TxBuilder {
 	b.outputs = append(b.outputs, outputs...)
 
@@ -163,11 +192,22 @@ func (b *TxBuilder) UpdateOutputAmount(index int, amount uint64, tokenAmounts ..
 		index = len(b.outputs) + index
 	}
 
-	b.outputs[index].Amount = amount
+	output := &b.outputs[index]
+	output.setLovelace(amount)
+
+	// tokens of the value are updated in the order of Value.GetTokens
+	valueTokens := output.Value.GetTokens()
+	if len(valueTokens) > 0 {
+		output.Value = output.Value.Clone()
+	}
 
 	for i, amount := range tokenAmounts {
-		if len(b.outputs[index].Tokens) > i {
-			b.outputs[index].Tokens[i].Amount = amount
+		if len(output.Tokens) > i {
+			output.Tokens[i].Amount = amount
+		}
+
+		if len(valueTokens) > i {
+			output.Value.setAsset(valueTokens[i].PolicyID, valueTokens[i].Name, amount)
 		}
 	}
 
@@ -212,17 +252,42 @@ func (b *TxBuilder) SetTimeToLive(timeToLive uint64) *TxBuilder {
 	return b
 }
 
+// SetWitnessCount sets number of witnesses used for the fee by Balance.
+// Zero (default) means it is estimated from the inputs policy scripts (see CalculateFee)
+func (b *TxBuilder) SetWitnessCount(witnessCount int) *TxBuilder {
+	b.witnessCount = witnessCount
+
+	return b
+}
+
+// CalculateFee calculates minimal fee for the transaction natively from the protocol parameters.
+// If witnessCount is zero it is estimated from the inputs policy scripts
+// and keys (or scripts) of the certificates, withdrawals and voters
 func (b *TxBuilder) CalculateFee(witnessCount int) (uint64, error) {
-	if b.protocolParameters == nil {
-		return 0, errors.New("protocol parameters not set")
+	if err := b.adjustOutputsToMinUtxo(); err != nil {
+		return 0, err
 	}
 
-	protocolParamsFilePath := filepath.Join(b.baseDirectory, "protocol-parameters.json")
-	if err := os.WriteFile(protocolParamsFilePath, b.protocolParameters, FilePermission); err != nil {
+	protocolParameters, err := b.getProtocolParameters()
+	if err != nil {
 		return 0, err
 	}
 
-	if err := b.buildRawTx(protocolParamsFilePath, 0); err != nil {
+	// fee and not yet specified output amounts are serialized with the largest possible values
+	// so the size of the draft transaction is never lower than the size of the final one
+	draftBuilder := *b
+	draftBuilder.outputs = make([]TxOutput, len(b.outputs))
+
+	for i, out := range b.outputs {
+		draftBuilder.outputs[i] = out
+
+		if out.getLovelace() == 0 {
+			draftBuilder.outputs[i].setLovelace(math.MaxUint64)
+		}
+	}
+
+	txRaw, err := draftBuilder.buildRawTx(math.MaxUint64)
+	if err != nil {
 		return 0, err
 	}
 
@@ -231,53 +296,33 @@ func (b *TxBuilder) CalculateFee(witnessCount int) (uint64, error) {
 			witnessCount += inp.GetWitnessCount()
 		}
 
-		witnessCount = max(witnessCount, 1)
+		witnessCount = max(witnessCount, 1) + b.getCredentialsWitnessCount()
 	}
 
-	feeOutput, err := runCommand(b.cardanoCliBinary, append([]string{
-		"transaction", "calculate-min-fee",
-		"--tx-body-file", filepath.Join(b.baseDirectory, draftTxFile),
-		"--tx-in-count", strconv.Itoa(len(b.inputs)),
-		"--tx-out-count", strconv.Itoa(len(b.outputs)),
-		"--witness-count", strconv.FormatUint(uint64(witnessCount), 10),
-		"--protocol-params-file", protocolParamsFilePath,
-	}, getTestNetMagicArgs(b.testNetMagic)...))
+	referenceScriptsSize, err := b.getReferenceScriptsSize()
 	if err != nil {
 		return 0, err
 	}
 
-	return strconv.ParseUint(strings.Split(feeOutput, " ")[0], 10, 64)
+	return CalculateMinFee(txRaw, protocolParameters, witnessCount, referenceScriptsSize)
 }
 
+// Build builds transaction body natively (without cardano-cli) and returns cbor of unwitnessed transaction
 func (b *TxBuilder) Build() ([]byte, string, error) {
-	if b.protocolParameters == nil {
-		return nil, "", errors.New("protocol parameters not set")
-	}
-
-	if err := b.CheckOutputs(); err != nil {
-		return nil, "", err
-	}
-
-	protocolParamsFilePath := filepath.Join(b.baseDirectory, "protocol-parameters.json")
-	if err := os.WriteFile(protocolParamsFilePath, b.protocolParameters, FilePermission); err != nil {
-		return nil, "", err
-	}
-
-	if err := b.buildRawTx(protocolParamsFilePath, b.fee); err != nil {
+	if err := b.adjustOutputsToMinUtxo(); err != nil {
 		return nil, "", err
 	}
 
-	bytes, err := os.ReadFile(filepath.Join(b.baseDirectory, draftTxFile))
-	if err != nil {
+	if err := b.CheckOutputs(); err != nil {
 		return nil, "", err
 	}
 
-	txRaw, err := newTransactionUnwitnessedRawFromJSON(bytes)
+	txRaw, err := b.buildRawTx(b.fee)
 	if err != nil {
 		return nil, "", err
 	}
 
-	txHash, err := NewCliUtils(b.cardanoCliBinary).getTxHash(txRaw, b.baseDirectory)
+	txHash, err := GetTxHash(txRaw)
 	if err != nil {
 		return nil, "", err
 	}
@@ -289,7 +334,7 @@ func (b *TxBuilder) CheckOutputs() error {
 	var errs []error
 
 	for i, x := range b.outputs {
-		if x.Amount == 0 {
+		if x.getLovelace() == 0 {
 			errs = append(errs, fmt.Errorf("output (%s, %d) amount not specified", x.Addr, i))
 		}
 	}
@@ -297,46 +342,9 @@ func (b *TxBuilder) CheckOutputs() error {
 	return errors.Join(errs...)
 }
 
-func (b *TxBuilder) buildRawTx(protocolParamsFilePath string, fee uint64) error {
-	args := []string{
-		"transaction", "build-raw",
-		"--protocol-params-file", protocolParamsFilePath,
-		"--fee", strconv.FormatUint(fee, 10),
-		"--invalid-hereafter", strconv.FormatUint(b.timeToLive, 10),
-		"--out-file", filepath.Join(b.baseDirectory, draftTxFile),
-	}
-
-	if b.metadata != nil {
-		metaDataFilePath := filepath.Join(b.baseDirectory, "metadata.json")
-		if err := os.WriteFile(metaDataFilePath, b.metadata, FilePermission); err != nil {
-			return err
-		}
-
-		args = append(args, "--metadata-json-file", metaDataFilePath)
-	}
-
-	if err := b.mints.Apply(&args, b.baseDirectory); err != nil {
-		return err
-	}
-
-	for i, inp := range b.inputs {
-		if err := inp.Apply(&args, b.baseDirectory, i); err != nil {
-			return err
-		}
-	}
-
-	for _, out := range b.outputs {
-		args = append(args, "--tx-out", out.String())
-	}
-
-	_, err := runCommand(b.cardanoCliBinary, args)
-
-	return err
-}
-
 // SignTx signs tx and assembles all signatures in final tx
 func (b *TxBuilder) SignTx(txRaw []byte, signers []ITxSigner) ([]byte, error) {
-	txHash, err := NewCliUtils(b.cardanoCliBinary).getTxHash(txRaw, b.baseDirectory)
+	txHash, err := GetTxHash(txRaw)
 	if err != nil {
 		return nil, err
 	}
@@ -355,80 +363,15 @@ func (b *TxBuilder) SignTx(txRaw []byte, signers []ITxSigner) ([]byte, error) {
 
 // AssembleTxWitnesses assembles final signed transaction
 func (b *TxBuilder) AssembleTxWitnesses(txRaw []byte, witnesses [][]byte) ([]byte, error) {
-	outFilePath := filepath.Join(b.baseDirectory, "tx.sig")
-	txFilePath := filepath.Join(b.baseDirectory, "tx.raw")
-	witnessesFilePaths := make([]string, len(witnesses))
-
-	for i, witness := range witnesses {
-		witnessesFilePaths[i] = filepath.Join(b.baseDirectory, fmt.Sprintf("witness-%d", i+1))
-
-		content, err := TxWitnessRaw(witness).ToJSON()
-		if err != nil {
-			return nil, err
-		}
-
-		if err := os.WriteFile(witnessesFilePaths[i], content, FilePermission); err != nil {
-			return nil, err
-		}
-	}
-
-	txBytes, err := transactionUnwitnessedRaw(txRaw).ToJSON()
-	if err != nil {
-		return nil, err
-	}
-
-	if err := os.WriteFile(txFilePath, txBytes, FilePermission); err != nil {
-		return nil, err
-	}
-
-	args := []string{
-		"transaction", "assemble",
-		"--tx-body-file", txFilePath,
-		"--out-file", outFilePath}
-
-	for _, fp := range witnessesFilePaths {
-		args = append(args, "--witness-file", fp)
-	}
-
-	if _, err = runCommand(b.cardanoCliBinary, args); err != nil {
-		return nil, err
-	}
-
-	bytes, err := os.ReadFile(outFilePath)
-	if err != nil {
-		return nil, err
-	}
-
-	return newTransactionWitnessedRawFromJSON(bytes)
+	return AssembleTx(txRaw, witnesses)
 }
 
 type txInputWithPolicyScript struct {
-	txInput      TxInput
-	policyScript IPolicyScript
-}
-
-func (txInputPS txInputWithPolicyScript) Apply(
-	args *[]string, basePath string, indx int,
-) error {
-	*args = append(*args, "--tx-in", txInputPS.txInput.String())
-
-	if txInputPS.policyScript == nil {
-		return nil
-	}
-
-	policyScriptJSON, err := txInputPS.policyScript.GetPolicyScriptJSON()
-	if err != nil {
-		return err
-	}
-
-	policyFilePath := filepath.Join(basePath, fmt.Sprintf("policy_%d.json", indx))
-	if err := os.WriteFile(policyFilePath, policyScriptJSON, FilePermission); err != nil {
-		return err
-	}
-
-	*args = append(*args, "--tx-in-script-file", policyFilePath)
-
-	return nil
+	txInput       TxInput
+	policyScript  IPolicyScript
+	utxo          *Utxo          // value of the input is known only if input is added as utxo
+	plutus        *txPlutusSpend // not nil if input is spent with plutus script
+	referenceUtxo *Utxo          // not nil if the script is not attached but read from the reference utxo
 }
 
 func (txInputPS txInputWithPolicyScript) GetWitnessCount() int {
@@ -443,39 +386,3 @@ type txTokenMintInputs struct {
 	tokens        []TokenAmount
 	policyScripts []IPolicyScript
 }
-
-func (txMint txTokenMintInputs) Apply(
-	args *[]string, basePath string,
-) error {
-	if len(txMint.tokens) == 0 {
-		return nil
-	}
-
-	var sb strings.Builder
-
-	for _, token := range txMint.tokens {
-		if sb.Len() > 0 {
-			sb.WriteRune('+')
-		}
-
-		sb.WriteString(token.String())
-	}
-
-	*args = append(*args, "--mint", sb.String())
-
-	for indx, policyScript := range txMint.policyScripts {
-		policyScriptJSON, err := policyScript.GetPolicyScriptJSON()
-		if err != nil {
-			return err
-		}
-
-		policyFilePath := filepath.Join(basePath, fmt.Sprintf("policy_mint_%d.json", indx))
-		if err := os.WriteFile(policyFilePath, policyScriptJSON, FilePermission); err != nil {
-			return err
-		}
-
-		*args = append(*args, "--minting-script-file", policyFilePath)
-	}
-
-	return nil
-}
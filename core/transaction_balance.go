@@ -0,0 +1,169 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	maxBalanceIterations = 10
+)
+
+var (
+	ErrBalanceInputValueUnknown = errors.New("input value is unknown (input must be added as utxo)")
+	ErrBalanceNotEnoughFunds    = errors.New("not enough funds for the transaction")
+)
+
+// Balance adds change output to changeAddr with everything which is not spent by the outputs
+// (lovelace and all the native tokens). Fee is calculated iteratively until it converges.
+// Change which contains only lovelace and is below min utxo value is added to the fee.
+// Certificate and proposal deposits are subtracted from the change, refunds and withdrawals are added to it.
+// Fee is calculated with the witness count set by SetWitnessCount (estimated from the inputs policy scripts
+// if it is not set). All the inputs must be added with AddUtxos or AddUtxosWithScript. Returns calculated fee
+func (b *TxBuilder) Balance(changeAddr string) (uint64, error) {
+	protocolParameters, err := b.getProtocolParameters()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := b.adjustOutputsToMinUtxo(); err != nil {
+		return 0, err
+	}
+
+	// change output must not be adjusted by the fee calculation
+	defer func(autoMinUtxo bool) {
+		b.autoMinUtxo = autoMinUtxo
+	}(b.autoMinUtxo)
+
+	b.autoMinUtxo = false
+
+	change, err := b.getChange(protocolParameters)
+	if err != nil {
+		return 0, err
+	}
+
+	changeTokens := change.GetTokens()
+
+	b.AddOutputs(TxOutput{
+		Addr:   changeAddr,
+		Tokens: changeTokens,
+	})
+
+	changeLovelace, fee, isConverged := change.Coin, uint64(0), false
+
+	// first fee is calculated with the largest possible change amount, so the fee can only decrease later.
+	// Iteration stops when newly calculated fee is not greater than the previous one
+	for i := 0; i < maxBalanceIterations && !isConverged; i++ {
+		newFee, err := b.CalculateFee(b.witnessCount)
+		if err != nil {
+			b.RemoveOutput(-1)
+
+			return 0, err
+		}
+
+		if newFee <= fee {
+			isConverged = true
+
+			continue
+		}
+
+		if newFee > changeLovelace {
+			b.RemoveOutput(-1)
+
+			return 0, fmt.Errorf("%w: (available, fee) = (%d, %d)", ErrBalanceNotEnoughFunds, changeLovelace, newFee)
+		}
+
+		fee = newFee
+
+		b.UpdateOutputAmount(-1, changeLovelace-fee)
+	}
+
+	if !isConverged {
+		b.RemoveOutput(-1)
+
+		return 0, errors.New("fee calculation did not converge")
+	}
+
+	minUtxoValue, err := GetMinUtxoForOutput(b.outputs[len(b.outputs)-1], protocolParameters.UtxoCostPerByte)
+	if err != nil {
+		b.RemoveOutput(-1)
+
+		return 0, err
+	}
+
+	if changeLovelace-fee < minUtxoValue {
+		if len(changeTokens) > 0 {
+			b.RemoveOutput(-1)
+
+			return 0, fmt.Errorf("%w: change has %d tokens but not enough lovelace: (change, min utxo) = (%d, %d)",
+				ErrBalanceNotEnoughFunds, len(changeTokens), changeLovelace-fee, minUtxoValue)
+		}
+
+		// dust is added to the fee
+		b.RemoveOutput(-1)
+
+		fee = changeLovelace
+	}
+
+	b.SetFee(fee)
+
+	return fee, nil
+}
+
+// getChange returns everything from the inputs (mints, withdrawals and certificate refunds)
+// which is not spent in the outputs, certificate and proposal deposits
+func (b *TxBuilder) getChange(protocolParameters ProtocolParameters) (Value, error) {
+	deposit, refund := b.getCertificatesDeposit(protocolParameters)
+	available, err := NewValue(refund+b.getWithdrawalsAmount(), b.mints.tokens...)
+	if err != nil {
+		return Value{}, err
+	}
+
+	for _, inp := range b.inputs {
+		if inp.utxo == nil {
+			return Value{}, fmt.Errorf("%w: %s", ErrBalanceInputValueUnknown, inp.txInput)
+		}
+
+		inputValue, err := inp.utxo.GetValue()
+		if err != nil {
+			return Value{}, fmt.Errorf("input %s: %w", inp.txInput, err)
+		}
+
+		available, err = available.Add(inputValue)
+		if err != nil {
+			return Value{}, err
+		}
+	}
+
+	outputsValue, err := GetOutputsValue(b.outputs)
+	if err != nil {
+		return Value{}, err
+	}
+
+	spent, err := outputsValue.Add(Value{Coin: deposit + b.getProposalsDeposit(protocolParameters)})
+	if err != nil {
+		return Value{}, err
+	}
+
+	change, err := available.Sub(spent)
+	if err != nil {
+		return Value{}, fmt.Errorf("%w: %w", ErrBalanceNotEnoughFunds, err)
+	}
+
+	return change, nil
+}
+
+func (b *TxBuilder) getProtocolParameters() (ProtocolParameters, error) {
+	var protocolParameters ProtocolParameters
+
+	if b.protocolParameters == nil {
+		return protocolParameters, errors.New("protocol parameters not set")
+	}
+
+	if err := json.Unmarshal(b.protocolParameters, &protocolParameters); err != nil {
+		return protocolParameters, err
+	}
+
+	return protocolParameters, nil
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxBuilder_Balance(t *testing.T) {
+	t.Parallel()
+
+	const (
+		senderAddr   = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		receiverAddr = "addr_test1wz4k6frsfd9q98rya6zjxtpcmzn83pwc8uyl9yqw25p8qqcx3e0c0"
+		policyID     = "29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8"
+	)
+
+	utxos := []Utxo{
+		{
+			Hash:   "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f",
+			Index:  0,
+			Amount: 3_000_000,
+			Tokens: []TokenAmount{NewTokenAmount(policyID, "Route3", 100)},
+		},
+		{
+			Hash:   "d1fd0d772be7741d9bfaf0b037d02d2867a987ccba3e6ba2ee9aa2a861b73145",
+			Index:  2,
+			Amount: 2_000_000,
+		},
+	}
+
+	newBuilder := func() *TxBuilder {
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		return builder.SetProtocolParameters(protocolParameters).SetTimeToLive(100)
+	}
+
+	t.Run("change with tokens", func(t *testing.T) {
+		builder := newBuilder()
+		builder.AddUtxos(utxos...).AddOutputs(
+			NewTxOutput(receiverAddr, 1_500_000, NewTokenAmount(policyID, "Route3", 30)))
+
+		fee, err := builder.Balance(senderAddr)
+		require.NoError(t, err)
+
+		require.Len(t, builder.outputs, 2)
+		require.Equal(t, fee, builder.fee)
+		require.Equal(t, uint64(5_000_000-1_500_000)-fee, builder.outputs[1].Amount)
+		require.Equal(t, []TokenAmount{NewTokenAmount(policyID, "Route3", 70)}, builder.outputs[1].Tokens)
+
+		calculatedFee, err := builder.CalculateFee(0)
+		require.NoError(t, err)
+		require.LessOrEqual(t, calculatedFee, fee)
+	})
+
+	t.Run("dust change is added to the fee", func(t *testing.T) {
+		builder := newBuilder()
+		builder.AddUtxos(utxos[1]).AddOutputs(NewTxOutput(receiverAddr, 1_500_000))
+
+		fee, err := builder.Balance(senderAddr)
+		require.NoError(t, err)
+
+		require.Len(t, builder.outputs, 1)
+		require.Equal(t, uint64(500_000), fee)
+	})
+
+	t.Run("tokens without enough lovelace", func(t *testing.T) {
+		builder := newBuilder()
+		builder.AddUtxos(utxos[0]).AddOutputs(NewTxOutput(receiverAddr, 2_500_000))
+
+		_, err := builder.Balance(senderAddr)
+		require.ErrorIs(t, err, ErrBalanceNotEnoughFunds)
+		require.Len(t, builder.outputs, 1)
+	})
+
+	t.Run("not enough funds", func(t *testing.T) {
+		builder := newBuilder()
+		builder.AddUtxos(utxos...).AddOutputs(
+			NewTxOutput(receiverAddr, 1_500_000, NewTokenAmount(policyID, "Route3", 101)))
+
+		_, err := builder.Balance(senderAddr)
+		require.ErrorIs(t, err, ErrBalanceNotEnoughFunds)
+
+		builder = newBuilder()
+		builder.AddUtxos(utxos...).AddOutputs(NewTxOutput(receiverAddr, 4_950_000))
+
+		_, err = builder.Balance(senderAddr)
+		require.ErrorIs(t, err, ErrBalanceNotEnoughFunds)
+		require.Len(t, builder.outputs, 1)
+	})
+
+	t.Run("input without value", func(t *testing.T) {
+		builder := newBuilder()
+		builder.AddUtxos(utxos...).AddInputs(NewTxInput(utxos[0].Hash, 5))
+
+		_, err := builder.Balance(senderAddr)
+		require.ErrorIs(t, err, ErrBalanceInputValueUnknown)
+	})
+}
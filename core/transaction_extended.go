@@ -2,7 +2,6 @@ package core
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/igorcrevar/go-cardano-tx/common"
 )
@@ -33,9 +32,14 @@ func (b *TxBuilder) SetProtocolParametersAndTTL(
 
 type TxInputs struct {
 	Inputs []TxInput
-	Sum    map[string]uint64
+	// Deprecated: Sum is kept for compatibility, use Value which does not depend on the token name encoding
+	Sum   map[string]uint64
+	Utxos []Utxo // chosen utxos, can be used with TxBuilder.AddUtxos
+	Value Value  // total value of the chosen utxos
 }
 
+// GetUTXOsForAmount retrieves utxos for the address and chooses them in the order returned by the provider.
+// Only tokens from tokenNames are taken from exactSum and atLeastSum (see TokenAmount.TokenName)
 func GetUTXOsForAmount(
 	ctx context.Context,
 	retriever IUTxORetriever,
@@ -43,6 +47,29 @@ func GetUTXOsForAmount(
 	tokenNames []string,
 	exactSum map[string]uint64,
 	atLeastSum map[string]uint64,
+) (TxInputs, error) {
+	exactValue, err := newValueFromSumMapForTokens(exactSum, tokenNames)
+	if err != nil {
+		return TxInputs{}, err
+	}
+
+	atLeastValue, err := newValueFromSumMapForTokens(atLeastSum, tokenNames)
+	if err != nil {
+		return TxInputs{}, err
+	}
+
+	return GetUTXOsForAmountWithSelector(
+		ctx, retriever, addr, NewSequentialCoinSelector(0), exactValue, atLeastValue)
+}
+
+// GetUTXOsForAmountWithSelector retrieves utxos for the address and chooses them with the provided coin selector
+func GetUTXOsForAmountWithSelector(
+	ctx context.Context,
+	retriever IUTxORetriever,
+	addr string,
+	selector ICoinSelector,
+	exactSum Value,
+	atLeastSum Value,
 ) (TxInputs, error) {
 	utxos, err := common.ExecuteWithRetry(ctx, func(ctx context.Context) ([]Utxo, error) {
 		return retriever.GetUtxos(ctx, addr)
@@ -51,46 +78,38 @@ func GetUTXOsForAmount(
 		return TxInputs{}, err
 	}
 
-	// Loop through utxos to find first input with enough tokens
-	// If we don't have this UTXO we need to use more of them
-	//nolint:prealloc
-	var (
-		currentSum       = map[string]uint64{}
-		chosenUTXOs      []TxInput
-		notGoodTokenName string
-	)
-
-	for _, utxo := range utxos {
-		currentSum[AdaTokenName] += utxo.Amount
+	chosenUtxos, err := selector.SelectCoins(utxos, exactSum, atLeastSum)
+	if err != nil {
+		return TxInputs{}, err
+	}
 
-		for _, token := range utxo.Tokens {
-			currentSum[token.TokenName()] += token.Amount
-		}
+	value, err := GetUtxosValue(chosenUtxos)
+	if err != nil {
+		return TxInputs{}, err
+	}
 
-		chosenUTXOs = append(chosenUTXOs, TxInput{
-			Hash:  utxo.Hash,
-			Index: utxo.Index,
-		})
+	result := TxInputs{
+		Inputs: make([]TxInput, len(chosenUtxos)),
+		Sum:    value.ToSumMap(),
+		Utxos:  chosenUtxos,
+		Value:  value,
+	}
 
-		isOk := true
+	for i, utxo := range chosenUtxos {
+		result.Inputs[i] = NewTxInput(utxo.Hash, utxo.Index)
+	}
 
-		for _, tokenName := range tokenNames {
-			if currentSum[tokenName] != exactSum[tokenName] && currentSum[tokenName] < atLeastSum[tokenName] {
-				isOk = false
-				notGoodTokenName = tokenName
+	return result, nil
+}
 
-				break
-			}
-		}
+func newValueFromSumMapForTokens(sum map[string]uint64, tokenNames []string) (Value, error) {
+	filtered := make(map[string]uint64, len(tokenNames))
 
-		if isOk {
-			return TxInputs{
-				Inputs: chosenUTXOs,
-				Sum:    currentSum,
-			}, nil
+	for _, tokenName := range tokenNames {
+		if amount, exists := sum[tokenName]; exists {
+			filtered[tokenName] = amount
 		}
 	}
 
-	return TxInputs{}, fmt.Errorf("not enough funds for the transaction: (available, exact, at least) = (%d, %d, %d)",
-		currentSum[notGoodTokenName], exactSum[notGoodTokenName], atLeastSum[notGoodTokenName])
+	return NewValueFromSumMap(filtered)
 }
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	// referenceScriptFeeSizeIncrement and referenceScriptFeeMultiplier* are conway ledger constants
+	// used for the tiered reference scripts fee
+	referenceScriptFeeSizeIncrement = 25_600
+	referenceScriptFeeMultiplierNum = 6 // 1.2 = 6/5
+	referenceScriptFeeMultiplierDen = 5
+
+	vkeyWitnessesKey = 0
+	redeemersKey     = 5
+)
+
+// CalculateMinFee calculates minimal fee for the transaction (cbor of witnessed or unwitnessed transaction).
+// Transaction should already contain all the scripts and redeemers in its witness set. Key witnesses are estimated:
+// missing ones up to vkeyWitnessCount are added as dummy witnesses of the same size.
+// referenceScriptsSize is the total size of the scripts in spent and referenced inputs (conway)
+func CalculateMinFee(
+	txRaw []byte, protocolParameters ProtocolParameters, vkeyWitnessCount int, referenceScriptsSize uint64,
+) (uint64, error) {
+	txWithDummyWitnesses, witnessSet, err := addDummyVKeyWitnesses(txRaw, vkeyWitnessCount)
+	if err != nil {
+		return 0, err
+	}
+
+	exUnitsFee, err := getExUnitsFee(protocolParameters, witnessSet[redeemersKey])
+	if err != nil {
+		return 0, err
+	}
+
+	fee := protocolParameters.TxFeeFixed + protocolParameters.TxFeePerByte*uint64(len(txWithDummyWitnesses))
+
+	return fee + exUnitsFee + GetReferenceScriptsFee(protocolParameters, referenceScriptsSize), nil
+}
+
+// GetExUnitsFee calculates fee for the execution units: ceil(priceMemory * memory + priceSteps * steps)
+func GetExUnitsFee(protocolParameters ProtocolParameters, exUnits ExUnits) uint64 {
+	if exUnits.Memory == 0 && exUnits.Steps == 0 {
+		return 0
+	}
+
+	fee := new(big.Rat).Add(
+		new(big.Rat).Mul(getPriceRat(protocolParameters.ExecutionUnitPrices.PriceMemory),
+			new(big.Rat).SetUint64(exUnits.Memory)),
+		new(big.Rat).Mul(getPriceRat(protocolParameters.ExecutionUnitPrices.PriceSteps),
+			new(big.Rat).SetUint64(exUnits.Steps)))
+
+	// ceil of the (positive) rational number
+	result, remainder := new(big.Int).QuoRem(fee.Num(), fee.Denom(), new(big.Int))
+	if remainder.Sign() != 0 {
+		result.Add(result, big.NewInt(1))
+	}
+
+	return result.Uint64()
+}
+
+// getPriceRat converts price to rational number from its shortest decimal representation
+// (0.0577 should be 577/10000 and not the closest binary fraction)
+func getPriceRat(price float64) *big.Rat {
+	result, ok := new(big.Rat).SetString(strconv.FormatFloat(price, 'f', -1, 64))
+	if !ok {
+		return new(big.Rat).SetFloat64(price)
+	}
+
+	return result
+}
+
+// GetReferenceScriptsFee calculates conway tiered fee for the reference scripts: every MinFeeRefScriptRange
+// bytes (25KiB by default) the price per byte is multiplied by MinFeeRefScriptMultiplier (1.2 by default)
+func GetReferenceScriptsFee(protocolParameters ProtocolParameters, referenceScriptsSize uint64) uint64 {
+	if referenceScriptsSize == 0 || protocolParameters.MinFeeRefScriptCostPerByte == 0 {
+		return 0
+	}
+
+	var (
+		sum        = new(big.Rat)
+		price      = getPriceRat(protocolParameters.MinFeeRefScriptCostPerByte)
+		multiplier = big.NewRat(referenceScriptFeeMultiplierNum, referenceScriptFeeMultiplierDen)
+		sizeRange  = uint64(referenceScriptFeeSizeIncrement)
+	)
+
+	if protocolParameters.MinFeeRefScriptMultiplier != 0 {
+		multiplier = getPriceRat(protocolParameters.MinFeeRefScriptMultiplier)
+	}
+
+	if protocolParameters.MinFeeRefScriptRange != 0 {
+		sizeRange = protocolParameters.MinFeeRefScriptRange
+	}
+
+	increment := new(big.Rat).SetUint64(sizeRange)
+
+	for referenceScriptsSize >= sizeRange {
+		sum.Add(sum, new(big.Rat).Mul(increment, price))
+		price.Mul(price, multiplier)
+
+		referenceScriptsSize -= sizeRange
+	}
+
+	sum.Add(sum, new(big.Rat).Mul(new(big.Rat).SetUint64(referenceScriptsSize), price))
+
+	// floor of the (positive) rational number
+	return new(big.Int).Quo(sum.Num(), sum.Denom()).Uint64()
+}
+
+// addDummyVKeyWitnesses returns transaction with dummy vkey witnesses and its decoded witness set
+func addDummyVKeyWitnesses(
+	txRaw []byte, vkeyWitnessCount int,
+) ([]byte, map[uint64]cbor.RawMessage, error) {
+	var tx []cbor.RawMessage
+
+	if err := cbor.Unmarshal(txRaw, &tx); err != nil {
+		return nil, nil, fmt.Errorf("invalid transaction cbor: %w", err)
+	} else if len(tx) < 2 {
+		return nil, nil, errors.New("invalid transaction cbor: witness set not found")
+	}
+
+	var (
+		witnessSet    map[uint64]cbor.RawMessage
+		vkeyWitnesses []cbor.RawMessage
+	)
+
+	if err := cbor.Unmarshal(tx[1], &witnessSet); err != nil {
+		return nil, nil, fmt.Errorf("invalid witness set cbor: %w", err)
+	}
+
+	if witnessSet == nil {
+		witnessSet = map[uint64]cbor.RawMessage{}
+	}
+
+	if existing, exists := witnessSet[vkeyWitnessesKey]; exists {
+		if err := cbor.Unmarshal(existing, &vkeyWitnesses); err != nil {
+			return nil, nil, fmt.Errorf("invalid vkey witnesses cbor: %w", err)
+		}
+	}
+
+	if len(vkeyWitnesses) >= vkeyWitnessCount {
+		return txRaw, witnessSet, nil
+	}
+
+	dummyWitness, err := cbor.Marshal([][]byte{make([]byte, KeySize), make([]byte, KeySize*2)})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for len(vkeyWitnesses) < vkeyWitnessCount {
+		vkeyWitnesses = append(vkeyWitnesses, dummyWitness)
+	}
+
+	witnessSet[vkeyWitnessesKey], err = cbor.Marshal(vkeyWitnesses)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx[1], err = cborEncMode.Marshal(witnessSet)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txRaw, err = cbor.Marshal(tx)
+
+	return txRaw, witnessSet, err
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateMinFee(t *testing.T) {
+	t.Parallel()
+
+	var pp ProtocolParameters
+
+	require.NoError(t, json.Unmarshal(protocolParameters, &pp))
+
+	txRaw, err := hex.DecodeString(multisigTxRawHex)
+	require.NoError(t, err)
+
+	fee, err := CalculateMinFee(txRaw, pp, 10, 0)
+	require.NoError(t, err)
+
+	// 672 bytes + witness set key + array header + 10 witnesses
+	require.Equal(t, uint64(155381+44*(672+2+10*101)), fee)
+
+	pp.MinFeeRefScriptCostPerByte = 15
+
+	feeWithRefScripts, err := CalculateMinFee(txRaw, pp, 10, 1000)
+	require.NoError(t, err)
+
+	require.Equal(t, fee+15_000, feeWithRefScripts)
+
+	_, err = CalculateMinFee([]byte{0x80}, pp, 1, 0)
+	require.Error(t, err)
+}
+
+func TestTxBuilder_CalculateFee_SignedTransaction(t *testing.T) {
+	t.Parallel()
+
+	const addr = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+
+	var pp ProtocolParameters
+
+	require.NoError(t, json.Unmarshal(protocolParameters, &pp))
+
+	wallets := make([]ITxSigner, 5)
+	keyHashes := make([]string, len(wallets))
+
+	for i := range wallets {
+		wallet, err := GenerateWallet(false)
+		require.NoError(t, err)
+
+		keyHashes[i], err = GetKeyHash(wallet.VerificationKey)
+		require.NoError(t, err)
+
+		wallets[i] = wallet
+	}
+
+	policyScript := NewPolicyScript(keyHashes, 4)
+
+	builder, err := NewTxBuilder("")
+	require.NoError(t, err)
+
+	builder.SetProtocolParameters(protocolParameters).SetTimeToLive(28096).
+		AddInputsWithScript(policyScript, NewTxInput("e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f", 0)).
+		AddOutputs(NewTxOutput(addr, 2_000_000))
+
+	// every key of the script is counted as a witness
+	fee, err := builder.CalculateFee(0)
+	require.NoError(t, err)
+
+	txRaw, _, err := builder.SetFee(fee).Build()
+	require.NoError(t, err)
+
+	signedTxRaw, err := builder.SignTx(txRaw, wallets)
+	require.NoError(t, err)
+
+	// ledger min fee of the transaction signed by all the keys is a * size + b. Fee is calculated
+	// with the largest possible fee in the draft, whose cbor is 4 bytes longer than the cbor of the final fee
+	minFee := pp.TxFeeFixed + pp.TxFeePerByte*uint64(len(signedTxRaw))
+
+	require.Equal(t, minFee+4*pp.TxFeePerByte, fee)
+}
+
+func TestGetReferenceScriptsFee(t *testing.T) {
+	t.Parallel()
+
+	pp := ProtocolParameters{MinFeeRefScriptCostPerByte: 15}
+
+	require.Equal(t, uint64(0), GetReferenceScriptsFee(pp, 0))
+	require.Equal(t, uint64(15), GetReferenceScriptsFee(pp, 1))
+	require.Equal(t, uint64(384_000), GetReferenceScriptsFee(pp, 25_600))
+	require.Equal(t, uint64(384_000+460_800+2_160), GetReferenceScriptsFee(pp, 25_600*2+100))
+	// 25_600 * 15 + 99 * 18 = 385_782
+	require.Equal(t, uint64(385_782), GetReferenceScriptsFee(pp, 25_699))
+	require.Equal(t, uint64(0), GetReferenceScriptsFee(ProtocolParameters{}, 1000))
+
+	// price is exact decimal and not the closest binary fraction (0.29999...)
+	require.Equal(t, uint64(3), GetReferenceScriptsFee(ProtocolParameters{MinFeeRefScriptCostPerByte: 0.3}, 10))
+
+	// tiers from the protocol parameters: 100 * 10 + 50 * 15
+	require.Equal(t, uint64(1_750), GetReferenceScriptsFee(ProtocolParameters{
+		MinFeeRefScriptCostPerByte: 10,
+		MinFeeRefScriptRange:       100,
+		MinFeeRefScriptMultiplier:  1.5,
+	}, 150))
+}
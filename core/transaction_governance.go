@@ -0,0 +1,458 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+type VoterType uint64
+
+const (
+	VoterCommitteeHotKeyHash    VoterType = 0
+	VoterCommitteeHotScriptHash VoterType = 1
+	VoterDRepKeyHash            VoterType = 2
+	VoterDRepScriptHash         VoterType = 3
+	VoterStakePoolKeyHash       VoterType = 4
+)
+
+type Vote uint64
+
+const (
+	VoteNo      Vote = 0
+	VoteYes     Vote = 1
+	VoteAbstain Vote = 2
+)
+
+// Voter is constitutional committee member (hot credential), DRep or stake pool operator
+type Voter struct {
+	Type VoterType
+	Hash [KeyHashSize]byte
+}
+
+// NewDRepVoter creates DRep voter from the key hash or script hash credential
+func NewDRepVoter(drepCredential CardanoAddressPayload) Voter {
+	if drepCredential.IsScript {
+		return Voter{Type: VoterDRepScriptHash, Hash: drepCredential.Payload}
+	}
+
+	return Voter{Type: VoterDRepKeyHash, Hash: drepCredential.Payload}
+}
+
+// NewCommitteeVoter creates constitutional committee voter from the hot key hash or script hash credential
+func NewCommitteeVoter(hotCredential CardanoAddressPayload) Voter {
+	if hotCredential.IsScript {
+		return Voter{Type: VoterCommitteeHotScriptHash, Hash: hotCredential.Payload}
+	}
+
+	return Voter{Type: VoterCommitteeHotKeyHash, Hash: hotCredential.Payload}
+}
+
+// NewStakePoolVoter creates stake pool operator voter. poolID is bech32 (pool1...) or hex pool key hash
+func NewStakePoolVoter(poolID string) (Voter, error) {
+	poolKeyHash, err := GetPoolKeyHash(poolID)
+	if err != nil {
+		return Voter{}, err
+	}
+
+	return Voter{Type: VoterStakePoolKeyHash, Hash: poolKeyHash}, nil
+}
+
+// IsScript returns true if the vote must be witnessed by the script
+func (v Voter) IsScript() bool {
+	return v.Type == VoterCommitteeHotScriptHash || v.Type == VoterDRepScriptHash
+}
+
+// MarshalCBOR serializes voter from the ledger cddl: [voter type, addr_keyhash / script_hash]
+func (v Voter) MarshalCBOR() ([]byte, error) {
+	if v.Type > VoterStakePoolKeyHash {
+		return nil, fmt.Errorf("invalid voter type: %d", v.Type)
+	}
+
+	return cbor.Marshal([]interface{}{v.Type, v.Hash[:]})
+}
+
+// VotingProcedure is vote with the optional anchor to the rationale
+type VotingProcedure struct {
+	Vote   Vote
+	Anchor *Anchor
+}
+
+// MarshalCBOR serializes voting_procedure from the ledger cddl: [vote, anchor / null]
+func (vp VotingProcedure) MarshalCBOR() ([]byte, error) {
+	if vp.Vote > VoteAbstain {
+		return nil, fmt.Errorf("invalid vote: %d", vp.Vote)
+	}
+
+	return cbor.Marshal([]interface{}{vp.Vote, vp.Anchor})
+}
+
+type txVote struct {
+	voter       Voter
+	govActionID GovActionID
+	procedure   VotingProcedure
+}
+
+// AddVote adds vote of the voter for the governance action. Anchor (rationale) is optional
+func (b *TxBuilder) AddVote(voter Voter, govActionID GovActionID, vote Vote, anchor *Anchor) *TxBuilder {
+	b.votes = append(b.votes, txVote{
+		voter:       voter,
+		govActionID: govActionID,
+		procedure:   VotingProcedure{Vote: vote, Anchor: anchor},
+	})
+
+	return b
+}
+
+// getVotingProcedures returns voting_procedures from the ledger cddl: { voter => { gov_action_id => voting_procedure } }
+// Voters and governance actions are sorted as in the canonical cbor
+func (b *TxBuilder) getVotingProcedures() (cbor.RawMessage, error) {
+	if len(b.votes) == 0 {
+		return nil, nil
+	}
+
+	type voterVotes struct {
+		voter []byte
+		votes map[string]cbor.RawMessage
+	}
+
+	votersMap := map[string]*voterVotes{}
+
+	for _, vote := range b.votes {
+		voter, err := cbor.Marshal(vote.voter)
+		if err != nil {
+			return nil, err
+		}
+
+		govActionID, err := cbor.Marshal(vote.govActionID)
+		if err != nil {
+			return nil, err
+		}
+
+		procedure, err := cbor.Marshal(vote.procedure)
+		if err != nil {
+			return nil, err
+		}
+
+		item, exists := votersMap[string(voter)]
+		if !exists {
+			item = &voterVotes{voter: voter, votes: map[string]cbor.RawMessage{}}
+			votersMap[string(voter)] = item
+		}
+
+		if _, exists := item.votes[string(govActionID)]; exists {
+			return nil, fmt.Errorf("duplicate vote for %s#%d", vote.govActionID.TxHash, vote.govActionID.Index)
+		}
+
+		item.votes[string(govActionID)] = procedure
+	}
+
+	result := make(cborOrderedMap, 0, len(votersMap))
+
+	for _, voter := range getSortedCborKeys(votersMap) {
+		votes := make(cborOrderedMap, 0, len(votersMap[voter].votes))
+
+		for _, govActionID := range getSortedCborKeys(votersMap[voter].votes) {
+			votes = append(votes, cborOrderedMapItem{
+				Key:   cbor.RawMessage(govActionID),
+				Value: votersMap[voter].votes[govActionID],
+			})
+		}
+
+		result = append(result, cborOrderedMapItem{Key: cbor.RawMessage(voter), Value: votes})
+	}
+
+	return result.MarshalCBOR()
+}
+
+// IGovAction is governance action which can be proposed
+type IGovAction interface {
+	GetCbor() ([]byte, error)
+}
+
+// ParameterChangeAction proposes protocol parameters update. Update is protocol_param_update map
+// from the ledger cddl (parameter index -> value)
+type ParameterChangeAction struct {
+	PrevActionID *GovActionID
+	Update       map[uint64]interface{}
+	PolicyHash   string
+}
+
+// HardForkInitiationAction proposes new protocol version
+type HardForkInitiationAction struct {
+	PrevActionID    *GovActionID
+	ProtocolVersion ProtocolParametersVersion
+}
+
+// TreasuryWithdrawalsAction proposes withdrawals from the treasury (reward address -> amount)
+type TreasuryWithdrawalsAction struct {
+	Withdrawals map[string]uint64
+	PolicyHash  string
+}
+
+// NoConfidenceAction proposes motion of no confidence in the constitutional committee
+type NoConfidenceAction struct {
+	PrevActionID *GovActionID
+}
+
+// UpdateCommitteeAction proposes removal and addition of the constitutional committee members (cold credential ->
+// epoch in which the membership expires) and the new quorum threshold (ThresholdNumerator / ThresholdDenominator)
+type UpdateCommitteeAction struct {
+	PrevActionID         *GovActionID
+	RemoveMembers        []CardanoAddressPayload
+	AddMembers           map[CardanoAddressPayload]uint64
+	ThresholdNumerator   uint64
+	ThresholdDenominator uint64
+}
+
+// NewConstitutionAction proposes new constitution with the optional guardrails script hash
+type NewConstitutionAction struct {
+	PrevActionID *GovActionID
+	Anchor       Anchor
+	ScriptHash   string
+}
+
+// InfoAction is governance action which has no effect on the chain
+type InfoAction struct{}
+
+var (
+	_ IGovAction = ParameterChangeAction{}
+	_ IGovAction = HardForkInitiationAction{}
+	_ IGovAction = TreasuryWithdrawalsAction{}
+	_ IGovAction = NoConfidenceAction{}
+	_ IGovAction = UpdateCommitteeAction{}
+	_ IGovAction = NewConstitutionAction{}
+	_ IGovAction = InfoAction{}
+)
+
+// governance action tags as defined in the ledger cddl
+const (
+	govActionParameterChangeTag     = 0
+	govActionHardForkInitiationTag  = 1
+	govActionTreasuryWithdrawalsTag = 2
+	govActionNoConfidenceTag        = 3
+	govActionUpdateCommitteeTag     = 4
+	govActionNewConstitutionTag     = 5
+	govActionInfoTag                = 6
+)
+
+func (a ParameterChangeAction) GetCbor() ([]byte, error) {
+	if len(a.Update) == 0 {
+		return nil, errors.New("parameter change action without parameters")
+	}
+
+	policyHash, err := getOptionalScriptHash(a.PolicyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return cborEncMode.Marshal([]interface{}{govActionParameterChangeTag, a.PrevActionID, a.Update, policyHash})
+}
+
+func (a HardForkInitiationAction) GetCbor() ([]byte, error) {
+	return cbor.Marshal([]interface{}{
+		govActionHardForkInitiationTag, a.PrevActionID,
+		[]uint64{a.ProtocolVersion.Major, a.ProtocolVersion.Minor},
+	})
+}
+
+func (a TreasuryWithdrawalsAction) GetCbor() ([]byte, error) {
+	if len(a.Withdrawals) == 0 {
+		return nil, errors.New("treasury withdrawals action without withdrawals")
+	}
+
+	withdrawals := make(map[cbor.ByteString]uint64, len(a.Withdrawals))
+
+	for rewardAddress, amount := range a.Withdrawals {
+		addr, _, err := getRewardAddressStake(rewardAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		withdrawals[cbor.ByteString(addr.GetBytes())] = amount
+	}
+
+	policyHash, err := getOptionalScriptHash(a.PolicyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return cborEncMode.Marshal([]interface{}{govActionTreasuryWithdrawalsTag, withdrawals, policyHash})
+}
+
+func (a NoConfidenceAction) GetCbor() ([]byte, error) {
+	return cbor.Marshal([]interface{}{govActionNoConfidenceTag, a.PrevActionID})
+}
+
+// GetCbor serializes update_committee from the ledger cddl: [4, gov_action_id / null,
+// set<committee_cold_credential>, { committee_cold_credential => epoch_no }, unit_interval]
+func (a UpdateCommitteeAction) GetCbor() ([]byte, error) {
+	if a.ThresholdDenominator == 0 || a.ThresholdNumerator > a.ThresholdDenominator {
+		return nil, fmt.Errorf("invalid committee threshold: %d/%d", a.ThresholdNumerator, a.ThresholdDenominator)
+	}
+
+	removeMembers := make(map[string]bool, len(a.RemoveMembers))
+
+	for _, credential := range a.RemoveMembers {
+		key, err := cbor.Marshal(newTxRawCredential(credential))
+		if err != nil {
+			return nil, err
+		}
+
+		removeMembers[string(key)] = true
+	}
+
+	addMembers := make(map[string]uint64, len(a.AddMembers))
+
+	for credential, epoch := range a.AddMembers {
+		key, err := cbor.Marshal(newTxRawCredential(credential))
+		if err != nil {
+			return nil, err
+		}
+
+		if removeMembers[string(key)] {
+			return nil, fmt.Errorf("committee member %s is both added and removed", credential)
+		}
+
+		addMembers[string(key)] = epoch
+	}
+
+	removed := make([]cbor.RawMessage, 0, len(removeMembers))
+	for _, key := range getSortedCborKeys(removeMembers) {
+		removed = append(removed, cbor.RawMessage(key))
+	}
+
+	added := make(cborOrderedMap, 0, len(addMembers))
+	for _, key := range getSortedCborKeys(addMembers) {
+		added = append(added, cborOrderedMapItem{Key: cbor.RawMessage(key), Value: addMembers[key]})
+	}
+
+	return cbor.Marshal([]interface{}{
+		govActionUpdateCommitteeTag, a.PrevActionID, removed, added,
+		cbor.Tag{Number: cborTagRational, Content: []uint64{a.ThresholdNumerator, a.ThresholdDenominator}},
+	})
+}
+
+func (a NewConstitutionAction) GetCbor() ([]byte, error) {
+	scriptHash, err := getOptionalScriptHash(a.ScriptHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return cbor.Marshal([]interface{}{
+		govActionNewConstitutionTag, a.PrevActionID, []interface{}{a.Anchor, scriptHash},
+	})
+}
+
+func (a InfoAction) GetCbor() ([]byte, error) {
+	return cbor.Marshal([]interface{}{govActionInfoTag})
+}
+
+// ProposalProcedure proposes governance action. Deposit is returned to the reward address when
+// the action is enacted or expired. Zero Deposit is replaced with the deposit from the protocol parameters
+type ProposalProcedure struct {
+	Deposit       uint64
+	RewardAddress string
+	Action        IGovAction
+	Anchor        Anchor
+}
+
+// AddProposals adds governance actions proposals. Deposits of the proposals are included in Balance
+func (b *TxBuilder) AddProposals(proposals ...ProposalProcedure) *TxBuilder {
+	b.proposals = append(b.proposals, proposals...)
+
+	return b
+}
+
+// getProposalProcedures returns proposal procedures from the ledger cddl: [deposit, reward_account, gov_action, anchor]
+func (b *TxBuilder) getProposalProcedures() ([]cbor.RawMessage, error) {
+	if len(b.proposals) == 0 {
+		return nil, nil
+	}
+
+	protocolParameters, err := b.getProtocolParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]cbor.RawMessage, len(b.proposals))
+
+	for i, proposal := range b.proposals {
+		if proposal.Action == nil {
+			return nil, fmt.Errorf("proposal %d: governance action is nil", i)
+		}
+
+		rewardAddr, _, err := getRewardAddressStake(proposal.RewardAddress)
+		if err != nil {
+			return nil, fmt.Errorf("proposal %d: %w", i, err)
+		}
+
+		action, err := proposal.Action.GetCbor()
+		if err != nil {
+			return nil, fmt.Errorf("proposal %d: %w", i, err)
+		}
+
+		result[i], err = cbor.Marshal([]interface{}{
+			getProposalDeposit(proposal, protocolParameters), rewardAddr.GetBytes(),
+			cbor.RawMessage(action), proposal.Anchor,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("proposal %d: %w", i, err)
+		}
+	}
+
+	return result, nil
+}
+
+// getProposalsDeposit returns total deposit of all the proposals
+func (b *TxBuilder) getProposalsDeposit(protocolParameters ProtocolParameters) (deposit uint64) {
+	for _, proposal := range b.proposals {
+		deposit += getProposalDeposit(proposal, protocolParameters)
+	}
+
+	return deposit
+}
+
+func getProposalDeposit(proposal ProposalProcedure, protocolParameters ProtocolParameters) uint64 {
+	if proposal.Deposit == 0 {
+		return protocolParameters.GovActionDeposit
+	}
+
+	return proposal.Deposit
+}
+
+// getOptionalScriptHash returns nil (serialized as null) for the empty hash
+func getOptionalScriptHash(hash string) ([]byte, error) {
+	if hash == "" {
+		return nil, nil
+	}
+
+	hashBytes, err := hex.DecodeString(hash)
+	if err != nil || len(hashBytes) != KeyHashSize {
+		return nil, fmt.Errorf("invalid script hash: %s", hash)
+	}
+
+	return hashBytes, nil
+}
+
+// getSortedCborKeys returns keys (cbor encoded) sorted as in the canonical cbor: shorter first, then bytewise
+func getSortedCborKeys[T any](items map[string]T) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) < len(keys[j])
+		}
+
+		return bytes.Compare([]byte(keys[i]), []byte(keys[j])) < 0
+	})
+
+	return keys
+}
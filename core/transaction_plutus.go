@@ -0,0 +1,363 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const maxEvaluationIterations = 5
+
+var ErrExUnitsExceedMax = errors.New("execution units exceed max tx execution units")
+
+// txPlutusSpend holds everything needed to spend script locked utxo with plutus script
+type txPlutusSpend struct {
+	script   PlutusScript
+	datum    []byte // nil if utxo holds inline datum
+	redeemer Redeemer
+}
+
+// txPlutusWitnesses are plutus related parts of the witness set and body
+type txPlutusWitnesses struct {
+	scripts        map[PlutusScriptVersion]map[string][]byte
+	redeemers      []byte
+	datums         []byte
+	scriptDataHash []byte
+}
+
+// AddPlutusScriptInput adds script locked utxo which is spent with the plutus script.
+// datum is cbor of the plutus data whose hash is in the utxo (nil if utxo holds inline datum)
+func (b *TxBuilder) AddPlutusScriptInput(script PlutusScript, utxo Utxo, datum []byte, redeemer Redeemer) *TxBuilder {
+	b.inputs = append(b.inputs, txInputWithPolicyScript{
+		txInput: NewTxInput(utxo.Hash, utxo.Index),
+		utxo:    &utxo,
+		plutus: &txPlutusSpend{
+			script:   script,
+			datum:    datum,
+			redeemer: redeemer,
+		},
+	})
+
+	return b
+}
+
+// AddCollateralUtxos adds collateral inputs. They must be key locked and contain (mostly) lovelace
+func (b *TxBuilder) AddCollateralUtxos(utxos ...Utxo) *TxBuilder {
+	b.collateralInputs = append(b.collateralInputs, utxos...)
+
+	return b
+}
+
+// SetCollateralReturnAddress sets address where everything from the collateral inputs above the total collateral
+// (fee * collateralPercentage / 100) is returned if the scripts fail. Return output is omitted
+// if it contains only lovelace below the min utxo value
+func (b *TxBuilder) SetCollateralReturnAddress(addr string) *TxBuilder {
+	b.collateralReturnAddr = addr
+
+	return b
+}
+
+// EvaluateExUnits executes plutus scripts of the transaction as it is (current outputs and fee) with
+// the evaluator (ogmios, blockfrost) and sets execution units of all redeemers.
+// Scripts which inspect outputs or the fee may need different execution units after Balance adds the change output
+// and sets the fee, so use EvaluateAndBalance to evaluate exactly the transaction which is submitted.
+// Total execution units must not exceed max tx execution units from the protocol parameters
+func (b *TxBuilder) EvaluateExUnits(ctx context.Context, evaluator ITxEvaluator) error {
+	_, err := b.evaluateExUnits(ctx, evaluator)
+
+	return err
+}
+
+// EvaluateAndBalance evaluates execution units (see EvaluateExUnits) and balances the transaction (see Balance).
+// Balanced transaction is evaluated again and balanced with the new execution units until they do not change,
+// so the scripts are evaluated with the final outputs and fee. Returns calculated fee
+func (b *TxBuilder) EvaluateAndBalance(ctx context.Context, evaluator ITxEvaluator, changeAddr string) (uint64, error) {
+	if _, err := b.evaluateExUnits(ctx, evaluator); err != nil {
+		return 0, err
+	}
+
+	outputsCount := len(b.outputs)
+
+	for i := 0; i < maxEvaluationIterations; i++ {
+		fee, err := b.Balance(changeAddr)
+		if err != nil {
+			return 0, err
+		}
+
+		isChanged, err := b.evaluateExUnits(ctx, evaluator)
+		if err != nil {
+			return 0, err
+		} else if !isChanged {
+			return fee, nil
+		}
+
+		// change output (if any) is removed so the transaction is balanced again with the new execution units
+		if len(b.outputs) > outputsCount {
+			b.RemoveOutput(-1)
+		}
+	}
+
+	return 0, errors.New("execution units evaluation did not converge")
+}
+
+// evaluateExUnits sets evaluated execution units and returns true if any of them is changed
+func (b *TxBuilder) evaluateExUnits(ctx context.Context, evaluator ITxEvaluator) (bool, error) {
+	protocolParameters, err := b.getProtocolParameters()
+	if err != nil {
+		return false, err
+	}
+
+	txRaw, err := b.buildRawTx(b.fee)
+	if err != nil {
+		return false, err
+	}
+
+	exUnitsMap, err := evaluator.EvaluateTx(ctx, txRaw)
+	if err != nil {
+		return false, err
+	}
+
+	sortedInputs, err := b.getSortedTxRawInputs()
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		total       ExUnits
+		newExUnits  = map[*txPlutusSpend]ExUnits{}
+		maxExUnits  = protocolParameters.MaxTxExecutionUnits
+		hasMaxLimit = maxExUnits.Memory > 0 || maxExUnits.Steps > 0
+	)
+
+	for _, inp := range b.inputs {
+		if inp.plutus == nil {
+			continue
+		}
+
+		index, err := getInputIndex(sortedInputs, inp.txInput)
+		if err != nil {
+			return false, err
+		}
+
+		pointer := NewRedeemerPointer(RedeemerTagSpend, index)
+
+		exUnits, exists := exUnitsMap[pointer]
+		if !exists {
+			return false, fmt.Errorf("execution units for redeemer %s (input %s) not evaluated", pointer, inp.txInput)
+		}
+
+		total.Memory += exUnits.Memory
+		total.Steps += exUnits.Steps
+		newExUnits[inp.plutus] = exUnits
+	}
+
+	if hasMaxLimit && (total.Memory > maxExUnits.Memory || total.Steps > maxExUnits.Steps) {
+		return false, fmt.Errorf("%w: (memory, steps) = (%d, %d), max = (%d, %d)", ErrExUnitsExceedMax,
+			total.Memory, total.Steps, maxExUnits.Memory, maxExUnits.Steps)
+	}
+
+	isChanged := false
+
+	for spend, exUnits := range newExUnits {
+		isChanged = isChanged || spend.redeemer.ExUnits != exUnits
+		spend.redeemer.ExUnits = exUnits
+	}
+
+	return isChanged, nil
+}
+
+// getPlutusWitnesses returns plutus scripts, redeemers, datums and script data hash.
+// inputs must be sorted the same way as in the body because redeemer points to the input by the index
+func (b *TxBuilder) getPlutusWitnesses(sortedInputs []txRawInput) (result txPlutusWitnesses, err error) {
+	var (
+		redeemers []txRawRedeemer
+		datums    = map[string][]byte{}
+		versions  []PlutusScriptVersion
+	)
+
+	result.scripts = map[PlutusScriptVersion]map[string][]byte{}
+
+	for _, inp := range b.inputs {
+		if inp.plutus == nil {
+			continue
+		}
+
+		index, err := getInputIndex(sortedInputs, inp.txInput)
+		if err != nil {
+			return result, err
+		}
+
+		if inp.referenceUtxo == nil {
+			if err := addPlutusScript(result.scripts, inp.plutus.script); err != nil {
+				return result, err
+			}
+		} else if inp.plutus.script.Version == 0 {
+			return result, fmt.Errorf("reference utxo %s#%d of input %s does not contain plutus script",
+				inp.referenceUtxo.Hash, inp.referenceUtxo.Index, inp.txInput)
+		}
+
+		if inp.plutus.datum != nil {
+			datums[GetDatumHash(inp.plutus.datum)] = inp.plutus.datum
+		}
+
+		versions = append(versions, inp.plutus.script.Version)
+		redeemers = append(redeemers, txRawRedeemer{
+			Tag:     RedeemerTagSpend,
+			Index:   index,
+			Data:    inp.plutus.redeemer.Data,
+			ExUnits: inp.plutus.redeemer.ExUnits,
+		})
+	}
+
+	if len(redeemers) == 0 && len(datums) == 0 {
+		return result, nil
+	}
+
+	if len(redeemers) > 0 {
+		sort.Slice(redeemers, func(i, j int) bool {
+			if redeemers[i].Tag != redeemers[j].Tag {
+				return redeemers[i].Tag < redeemers[j].Tag
+			}
+
+			return redeemers[i].Index < redeemers[j].Index
+		})
+
+		result.redeemers, err = cborEncMode.Marshal(redeemers)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if len(datums) > 0 {
+		result.datums, err = cborEncMode.Marshal(getSortedByHash(datums))
+		if err != nil {
+			return result, err
+		}
+	}
+
+	protocolParameters, err := b.getProtocolParameters()
+	if err != nil {
+		return result, err
+	}
+
+	result.scriptDataHash, err = getScriptDataHash(
+		result.redeemers, result.datums, versions, protocolParameters.CostModels)
+
+	return result, err
+}
+
+// getCollateral returns collateral inputs, collateral return output and total collateral for the fee.
+// Fee equal to math.MaxUint64 is used for the draft transaction: return and total get the largest possible values
+func (b *TxBuilder) getCollateral(fee uint64) ([]txRawInput, *txRawOutput, uint64, error) {
+	if len(b.collateralInputs) == 0 {
+		return nil, nil, 0, nil
+	}
+
+	inputs := make([]txRawInput, len(b.collateralInputs))
+
+	for i, utxo := range b.collateralInputs {
+		hash, err := hex.DecodeString(utxo.Hash)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("invalid collateral input hash %s: %w", utxo.Hash, err)
+		}
+
+		inputs[i] = txRawInput{Hash: hash, Index: utxo.Index}
+	}
+
+	sortTxRawInputs(inputs)
+
+	if b.collateralReturnAddr == "" {
+		return inputs, nil, 0, nil
+	}
+
+	collateralValue, err := GetUtxosValue(b.collateralInputs)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if fee == math.MaxUint64 {
+		output, err := newTxRawOutput(NewTxOutputWithValue(b.collateralReturnAddr, collateralValue))
+		if err != nil {
+			return nil, nil, 0, err
+		}
+
+		return inputs, &output, collateralValue.Coin, nil
+	}
+
+	protocolParameters, err := b.getProtocolParameters()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	totalCollateral := (fee*protocolParameters.CollateralPercentage + 99) / 100
+
+	returnValue, err := collateralValue.Sub(Value{Coin: totalCollateral})
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("not enough collateral: %w", err)
+	}
+
+	returnOutput := NewTxOutputWithValue(b.collateralReturnAddr, returnValue)
+
+	minUtxoValue, err := GetMinUtxoForOutput(returnOutput, protocolParameters.UtxoCostPerByte)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if returnValue.Coin < minUtxoValue {
+		if returnValue.HasTokens() {
+			return nil, nil, 0, fmt.Errorf("not enough collateral for the collateral return: (return, min utxo) = (%d, %d)",
+				returnValue.Coin, minUtxoValue)
+		}
+
+		// everything goes to the collateral
+		return inputs, nil, collateralValue.Coin, nil
+	}
+
+	output, err := newTxRawOutput(returnOutput)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return inputs, &output, totalCollateral, nil
+}
+
+func addPlutusScript(scripts map[PlutusScriptVersion]map[string][]byte, script PlutusScript) error {
+	hash, err := script.GetHashBytes()
+	if err != nil {
+		return err
+	}
+
+	scriptBytes, err := cbor.Marshal(script.Script)
+	if err != nil {
+		return err
+	}
+
+	if scripts[script.Version] == nil {
+		scripts[script.Version] = map[string][]byte{}
+	}
+
+	scripts[script.Version][string(hash)] = scriptBytes
+
+	return nil
+}
+
+func getInputIndex(sortedInputs []txRawInput, input TxInput) (uint32, error) {
+	hash, err := hex.DecodeString(input.Hash)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, x := range sortedInputs {
+		if bytes.Equal(x.Hash, hash) && x.Index == input.Index {
+			return uint32(i), nil //nolint:gosec
+		}
+	}
+
+	return 0, fmt.Errorf("input %s not found", input)
+}
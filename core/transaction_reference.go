@@ -0,0 +1,186 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrReferenceInputSpent = errors.New("reference input is also spent by the transaction")
+
+// AddReferenceInputs adds read only inputs. Scripts (and datums) of the reference utxos can be used
+// by the transaction and sizes of their reference scripts are included in the fee.
+// Reference inputs must not be spent by the same transaction, otherwise Build and CalculateFee fail
+// with ErrReferenceInputSpent
+func (b *TxBuilder) AddReferenceInputs(utxos ...Utxo) *TxBuilder {
+	b.referenceInputs = append(b.referenceInputs, utxos...)
+
+	return b
+}
+
+// AddInputsWithReferenceScript adds inputs locked with the native script which is stored in the reference utxo
+// instead of attaching the script to the transaction
+func (b *TxBuilder) AddInputsWithReferenceScript(referenceUtxo Utxo, inputs ...TxInput) *TxBuilder {
+	for _, inp := range inputs {
+		b.inputs = append(b.inputs, newTxInputWithReferenceScript(inp, nil, referenceUtxo))
+	}
+
+	return b
+}
+
+// AddUtxosWithReferenceScript adds utxos locked with the native script which is stored in the reference utxo
+// instead of attaching the script to the transaction
+func (b *TxBuilder) AddUtxosWithReferenceScript(referenceUtxo Utxo, utxos ...Utxo) *TxBuilder {
+	for _, utxo := range utxos {
+		utxo := utxo
+
+		b.inputs = append(b.inputs, newTxInputWithReferenceScript(NewTxInput(utxo.Hash, utxo.Index), &utxo, referenceUtxo))
+	}
+
+	return b
+}
+
+// AddPlutusScriptInputWithReference adds script locked utxo which is spent with the plutus script
+// stored in the reference utxo. See AddPlutusScriptInput for datum and redeemer
+func (b *TxBuilder) AddPlutusScriptInputWithReference(
+	referenceUtxo Utxo, utxo Utxo, datum []byte, redeemer Redeemer,
+) *TxBuilder {
+	var script PlutusScript // zero version is reported as an error during build
+
+	if referenceUtxo.ReferenceScript != nil && referenceUtxo.ReferenceScript.Plutus != nil {
+		script = *referenceUtxo.ReferenceScript.Plutus
+	}
+
+	b.inputs = append(b.inputs, txInputWithPolicyScript{
+		txInput:       NewTxInput(utxo.Hash, utxo.Index),
+		utxo:          &utxo,
+		referenceUtxo: &referenceUtxo,
+		plutus: &txPlutusSpend{
+			script:   script,
+			datum:    datum,
+			redeemer: redeemer,
+		},
+	})
+
+	return b
+}
+
+func newTxInputWithReferenceScript(input TxInput, utxo *Utxo, referenceUtxo Utxo) txInputWithPolicyScript {
+	result := txInputWithPolicyScript{
+		txInput:       input,
+		utxo:          utxo,
+		referenceUtxo: &referenceUtxo,
+	}
+
+	// native script is needed for the witness count
+	if referenceUtxo.ReferenceScript != nil && referenceUtxo.ReferenceScript.Native != nil {
+		result.policyScript = referenceUtxo.ReferenceScript.Native
+	}
+
+	return result
+}
+
+// getReferenceUtxos returns all unique reference utxos: added ones and the ones holding scripts of the inputs.
+// Conway ledger requires reference inputs to be disjoint from the spent inputs
+func (b *TxBuilder) getReferenceUtxos() ([]Utxo, error) {
+	var (
+		result = make([]Utxo, 0, len(b.referenceInputs))
+		exists = map[TxInput]bool{}
+		spent  = make(map[TxInput]bool, len(b.inputs))
+	)
+
+	for _, inp := range b.inputs {
+		spent[NewTxInput(strings.ToLower(inp.txInput.Hash), inp.txInput.Index)] = true
+	}
+
+	addUtxo := func(utxo Utxo) error {
+		key := NewTxInput(strings.ToLower(utxo.Hash), utxo.Index)
+		if spent[key] {
+			return fmt.Errorf("%w: %s", ErrReferenceInputSpent, key)
+		}
+
+		if !exists[key] {
+			exists[key] = true
+
+			result = append(result, utxo)
+		}
+
+		return nil
+	}
+
+	for _, utxo := range b.referenceInputs {
+		if err := addUtxo(utxo); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, inp := range b.inputs {
+		if inp.referenceUtxo != nil {
+			if err := addUtxo(*inp.referenceUtxo); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// getReferenceInputs returns sorted reference inputs for the transaction body
+func (b *TxBuilder) getReferenceInputs() ([]txRawInput, error) {
+	utxos, err := b.getReferenceUtxos()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(utxos) == 0 {
+		return nil, nil
+	}
+
+	inputs := make([]txRawInput, len(utxos))
+
+	for i, utxo := range utxos {
+		hash, err := hex.DecodeString(utxo.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference input hash %s: %w", utxo.Hash, err)
+		}
+
+		inputs[i] = txRawInput{Hash: hash, Index: utxo.Index}
+	}
+
+	sortTxRawInputs(inputs)
+
+	return inputs, nil
+}
+
+// getReferenceScriptsSize returns total size of the reference scripts in the spent and referenced utxos
+// which is used for the conway reference scripts fee. Spent inputs added without utxos (AddInputs) are skipped
+func (b *TxBuilder) getReferenceScriptsSize() (uint64, error) {
+	var size uint64
+
+	utxos, err := b.getReferenceUtxos()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, inp := range b.inputs {
+		if inp.utxo != nil {
+			utxos = append(utxos, *inp.utxo)
+		}
+	}
+
+	for _, utxo := range utxos {
+		if utxo.ReferenceScript == nil {
+			continue
+		}
+
+		scriptSize, err := utxo.ReferenceScript.GetSize()
+		if err != nil {
+			return 0, fmt.Errorf("reference script of %s#%d: %w", utxo.Hash, utxo.Index, err)
+		}
+
+		size += scriptSize
+	}
+
+	return size, nil
+}
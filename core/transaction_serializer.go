@@ -0,0 +1,559 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+type txRawInput struct {
+	_     struct{} `cbor:",toarray"`
+	Hash  []byte
+	Index uint32
+}
+
+// txRawOutput is transaction output. It is serialized in the legacy format ([address, amount]) unless
+// it has datum or reference script when post alonzo format ({0: address, 1: amount, 2: datum, 3: script_ref}) is used
+type txRawOutput struct {
+	Address   []byte
+	Amount    Value
+	Datum     *txRawDatumOption
+	ScriptRef *ReferenceScript
+}
+
+type txRawLegacyOutput struct {
+	_       struct{} `cbor:",toarray"`
+	Address []byte
+	Amount  Value
+}
+
+type txRawPostAlonzoOutput struct {
+	Address   []byte            `cbor:"0,keyasint"`
+	Amount    Value             `cbor:"1,keyasint"`
+	Datum     *txRawDatumOption `cbor:"2,keyasint,omitempty"`
+	ScriptRef *ReferenceScript  `cbor:"3,keyasint,omitempty"`
+}
+
+// txRawDatumOption is [0, datum_hash] or [1, #6.24(bytes .cbor plutus_data)]
+type txRawDatumOption struct {
+	_    struct{} `cbor:",toarray"`
+	Type uint64
+	Data cbor.RawMessage
+}
+
+func (o txRawOutput) MarshalCBOR() ([]byte, error) {
+	if o.Datum == nil && o.ScriptRef == nil {
+		return cborEncMode.Marshal(txRawLegacyOutput{Address: o.Address, Amount: o.Amount})
+	}
+
+	return cborEncMode.Marshal(txRawPostAlonzoOutput(o))
+}
+
+func (o *txRawOutput) UnmarshalCBOR(data []byte) error {
+	if len(data) > 0 && data[0]>>5 == cborMajorTypeMap {
+		var output txRawPostAlonzoOutput
+
+		if err := cbor.Unmarshal(data, &output); err != nil {
+			return err
+		}
+
+		*o = txRawOutput(output)
+
+		return nil
+	}
+
+	var output txRawLegacyOutput
+
+	if err := cbor.Unmarshal(data, &output); err != nil {
+		return err
+	}
+
+	*o = txRawOutput{Address: output.Address, Amount: output.Amount}
+
+	return nil
+}
+
+type txRawBody struct {
+	Inputs             []txRawInput                                  `cbor:"0,keyasint"`
+	Outputs            []txRawOutput                                 `cbor:"1,keyasint"`
+	Fee                uint64                                        `cbor:"2,keyasint"`
+	TimeToLive         uint64                                        `cbor:"3,keyasint,omitempty"`
+	Certificates       []cbor.RawMessage                             `cbor:"4,keyasint,omitempty"`
+	Withdrawals        map[cbor.ByteString]uint64                    `cbor:"5,keyasint,omitempty"`
+	AuxiliaryDataHash  []byte                                        `cbor:"7,keyasint,omitempty"`
+	ValidityStart      *uint64                                       `cbor:"8,keyasint,omitempty"`
+	Mint               map[cbor.ByteString]map[cbor.ByteString]int64 `cbor:"9,keyasint,omitempty"`
+	ScriptDataHash     []byte                                        `cbor:"11,keyasint,omitempty"`
+	Collateral         []txRawInput                                  `cbor:"13,keyasint,omitempty"`
+	CollateralReturn   *txRawOutput                                  `cbor:"16,keyasint,omitempty"`
+	TotalCollateral    uint64                                        `cbor:"17,keyasint,omitempty"`
+	ReferenceInputs    []txRawInput                                  `cbor:"18,keyasint,omitempty"`
+	VotingProcedures   cbor.RawMessage                               `cbor:"19,keyasint,omitempty"`
+	ProposalProcedures []cbor.RawMessage                             `cbor:"20,keyasint,omitempty"`
+}
+
+const nativeScriptsKey = 1
+
+type txRawWitnessSet struct {
+	NativeScripts   []cbor.RawMessage `cbor:"1,keyasint,omitempty"`
+	PlutusV1Scripts []cbor.RawMessage `cbor:"3,keyasint,omitempty"`
+	PlutusData      cbor.RawMessage   `cbor:"4,keyasint,omitempty"`
+	Redeemers       cbor.RawMessage   `cbor:"5,keyasint,omitempty"`
+	PlutusV2Scripts []cbor.RawMessage `cbor:"6,keyasint,omitempty"`
+	PlutusV3Scripts []cbor.RawMessage `cbor:"7,keyasint,omitempty"`
+}
+
+type txRaw struct {
+	_             struct{} `cbor:",toarray"`
+	Body          cbor.RawMessage
+	WitnessSet    cbor.RawMessage
+	IsValid       bool
+	AuxiliaryData cbor.RawMessage // nil is serialized as null
+}
+
+// GetTxHash calculates transaction hash (blake2b-256 of the body) from witnessed or unwitnessed transaction cbor
+func GetTxHash(txRaw []byte) (string, error) {
+	txHash, err := GetTxHashBytes(txRaw)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(txHash), nil
+}
+
+// GetTxHashBytes calculates transaction hash (blake2b-256 of the body) from witnessed or unwitnessed transaction cbor
+func GetTxHashBytes(txRaw []byte) ([]byte, error) {
+	var tx []cbor.RawMessage
+
+	if err := cbor.Unmarshal(txRaw, &tx); err != nil {
+		return nil, fmt.Errorf("invalid transaction cbor: %w", err)
+	} else if len(tx) == 0 {
+		return nil, errors.New("invalid transaction cbor: body not found")
+	}
+
+	txHash := blake2b.Sum256(tx[0])
+
+	return txHash[:], nil
+}
+
+// buildRawTx serializes transaction with all the data from the builder and the provided fee
+func (b *TxBuilder) buildRawTx(fee uint64) ([]byte, error) {
+	sortedInputs, err := b.getSortedTxRawInputs()
+	if err != nil {
+		return nil, err
+	}
+
+	validityStart, timeToLive, err := b.getValidityInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	body := txRawBody{
+		Inputs:        sortedInputs,
+		Outputs:       make([]txRawOutput, len(b.outputs)),
+		Fee:           fee,
+		TimeToLive:    timeToLive,
+		ValidityStart: validityStart,
+	}
+	scripts := map[string][]byte{}
+
+	for _, inp := range b.inputs {
+		switch {
+		case inp.referenceUtxo != nil && inp.plutus == nil && inp.policyScript == nil:
+			return nil, fmt.Errorf("reference utxo %s#%d of input %s does not contain native script",
+				inp.referenceUtxo.Hash, inp.referenceUtxo.Index, inp.txInput)
+		case inp.policyScript != nil && inp.referenceUtxo == nil:
+			if err := addNativeScript(scripts, inp.policyScript); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	body.ReferenceInputs, err = b.getReferenceInputs()
+	if err != nil {
+		return nil, err
+	}
+
+	body.Certificates, err = b.getCertificates(scripts)
+	if err != nil {
+		return nil, err
+	}
+
+	body.Withdrawals, err = b.getWithdrawals(scripts)
+	if err != nil {
+		return nil, err
+	}
+
+	body.VotingProcedures, err = b.getVotingProcedures()
+	if err != nil {
+		return nil, err
+	}
+
+	body.ProposalProcedures, err = b.getProposalProcedures()
+	if err != nil {
+		return nil, err
+	}
+
+	plutusWitnesses, err := b.getPlutusWitnesses(body.Inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	body.ScriptDataHash = plutusWitnesses.scriptDataHash
+
+	body.Collateral, body.CollateralReturn, body.TotalCollateral, err = b.getCollateral(fee)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, out := range b.outputs {
+		output, err := newTxRawOutput(out)
+		if err != nil {
+			return nil, err
+		}
+
+		body.Outputs[i] = output
+	}
+
+	if len(b.mints.tokens) > 0 {
+		body.Mint = map[cbor.ByteString]map[cbor.ByteString]int64{}
+
+		for _, token := range b.mints.tokens {
+			policyID, err := getPolicyIDKey(token.PolicyID)
+			if err != nil {
+				return nil, err
+			}
+
+			if body.Mint[policyID] == nil {
+				body.Mint[policyID] = map[cbor.ByteString]int64{}
+			}
+
+			amount := body.Mint[policyID][cbor.ByteString(token.Name)]
+			if token.Amount > math.MaxInt64 || amount > math.MaxInt64-int64(token.Amount) {
+				return nil, fmt.Errorf("mint amount of %s exceeds %d", token.TokenName(), int64(math.MaxInt64))
+			}
+
+			body.Mint[policyID][cbor.ByteString(token.Name)] = amount + int64(token.Amount)
+		}
+
+		for _, policyScript := range b.mints.policyScripts {
+			if err := addNativeScript(scripts, policyScript); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var auxiliaryData []byte
+
+	if b.metadata != nil {
+		var err error
+
+		auxiliaryData, err = newAuxiliaryDataFromMetadataJSON(b.metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		auxiliaryDataHash := blake2b.Sum256(auxiliaryData)
+		body.AuxiliaryDataHash = auxiliaryDataHash[:]
+	}
+
+	bodyBytes, err := cborEncMode.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	witnessSetBytes, err := cborEncMode.Marshal(txRawWitnessSet{
+		NativeScripts:   getSortedByHash(scripts),
+		PlutusV1Scripts: getSortedByHash(plutusWitnesses.scripts[PlutusV1]),
+		PlutusData:      plutusWitnesses.datums,
+		Redeemers:       plutusWitnesses.redeemers,
+		PlutusV2Scripts: getSortedByHash(plutusWitnesses.scripts[PlutusV2]),
+		PlutusV3Scripts: getSortedByHash(plutusWitnesses.scripts[PlutusV3]),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cborEncMode.Marshal(txRaw{
+		Body:          bodyBytes,
+		WitnessSet:    witnessSetBytes,
+		IsValid:       true,
+		AuxiliaryData: auxiliaryData,
+	})
+}
+
+// getSortedTxRawInputs returns inputs in the same order as in the transaction body
+func (b *TxBuilder) getSortedTxRawInputs() ([]txRawInput, error) {
+	inputs := make([]txRawInput, len(b.inputs))
+
+	for i, inp := range b.inputs {
+		hash, err := hex.DecodeString(inp.txInput.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input hash %s: %w", inp.txInput.Hash, err)
+		}
+
+		inputs[i] = txRawInput{Hash: hash, Index: inp.txInput.Index}
+	}
+
+	sortTxRawInputs(inputs)
+
+	return inputs, nil
+}
+
+// sortTxRawInputs sorts inputs by hash and index (inputs are a set in the ledger so they must be sorted)
+func sortTxRawInputs(inputs []txRawInput) {
+	sort.Slice(inputs, func(i, j int) bool {
+		if cmp := bytes.Compare(inputs[i].Hash, inputs[j].Hash); cmp != 0 {
+			return cmp < 0
+		}
+
+		return inputs[i].Index < inputs[j].Index
+	})
+}
+
+func newTxRawOutput(out TxOutput) (txRawOutput, error) {
+	addr, err := NewCardanoAddressFromString(out.Addr)
+	if err != nil {
+		return txRawOutput{}, fmt.Errorf("invalid output address %s: %w", out.Addr, err)
+	}
+
+	datum, err := newTxRawDatumOption(out.DatumHash, out.InlineDatum)
+	if err != nil {
+		return txRawOutput{}, fmt.Errorf("output %s: %w", out.Addr, err)
+	}
+
+	amount, err := out.GetValue()
+	if err != nil {
+		return txRawOutput{}, fmt.Errorf("output %s: %w", out.Addr, err)
+	}
+
+	return txRawOutput{
+		Address:   addr.GetBytes(),
+		Amount:    amount,
+		Datum:     datum,
+		ScriptRef: out.ReferenceScript,
+	}, nil
+}
+
+func newTxRawDatumOption(datumHash string, inlineDatum []byte) (*txRawDatumOption, error) {
+	switch {
+	case datumHash != "" && inlineDatum != nil:
+		return nil, errors.New("output can not have both datum hash and inline datum")
+	case datumHash != "":
+		hash, err := hex.DecodeString(datumHash)
+		if err != nil || len(hash) != DatumHashSize {
+			return nil, fmt.Errorf("invalid datum hash: %s", datumHash)
+		}
+
+		data, err := cbor.Marshal(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		return &txRawDatumOption{Type: datumOptionHashType, Data: data}, nil
+	case inlineDatum != nil:
+		data, err := cbor.Marshal(cbor.Tag{Number: cborTagEncodedData, Content: inlineDatum})
+		if err != nil {
+			return nil, err
+		}
+
+		return &txRawDatumOption{Type: datumOptionInlineType, Data: data}, nil
+	default:
+		return nil, nil //nolint:nilnil
+	}
+}
+
+func getPolicyIDKey(policyID string) (cbor.ByteString, error) {
+	policyIDBytes, err := hex.DecodeString(policyID)
+	if err != nil || len(policyIDBytes) != KeyHashSize {
+		return "", fmt.Errorf("invalid policy id: %s", policyID)
+	}
+
+	return cbor.ByteString(policyIDBytes), nil
+}
+
+// addNativeScript adds serialized script into the map where key is the hash of the script
+func addNativeScript(scripts map[string][]byte, policyScript IPolicyScript) error {
+	script, err := getNativeScriptCbor(policyScript)
+	if err != nil {
+		return err
+	}
+
+	return addNativeScriptCbor(scripts, script)
+}
+
+func addNativeScriptCbor(scripts map[string][]byte, script []byte) error {
+	hash, err := getNativeScriptHash(script)
+	if err != nil {
+		return err
+	}
+
+	scripts[string(hash)] = script
+
+	return nil
+}
+
+// getSortedByHash returns cbor items (scripts, witnesses) sorted by their hashes
+// (ledger keeps them in the map or set ordered by the hash)
+func getSortedByHash(items map[string][]byte) []cbor.RawMessage {
+	hashes := make([]string, 0, len(items))
+	for hash := range items {
+		hashes = append(hashes, hash)
+	}
+
+	sort.Strings(hashes)
+
+	result := make([]cbor.RawMessage, len(hashes))
+	for i, hash := range hashes {
+		result[i] = items[hash]
+	}
+
+	return result
+}
+
+func getNativeScriptCbor(policyScript IPolicyScript) ([]byte, error) {
+	ps, err := toPolicyScript(policyScript)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ps.Validate(); err != nil {
+		return nil, err
+	}
+
+	return ps.MarshalCBOR()
+}
+
+func toPolicyScript(policyScript IPolicyScript) (PolicyScript, error) {
+	switch ps := policyScript.(type) {
+	case *PolicyScript:
+		return *ps, nil
+	case PolicyScript:
+		return ps, nil
+	}
+
+	// arbitrary implementation of the IPolicyScript - use its json representation
+	scriptJSON, err := policyScript.GetPolicyScriptJSON()
+	if err != nil {
+		return PolicyScript{}, err
+	}
+
+	var ps PolicyScript
+
+	err = json.Unmarshal(scriptJSON, &ps)
+
+	return ps, err
+}
+
+// AssembleTx adds vkey witnesses (as created by CreateTxWitness) and native scripts into the witness set
+// of the transaction. Body of the transaction is preserved byte by byte so the hash stays the same
+func AssembleTx(txRaw []byte, witnesses [][]byte, policyScripts ...IPolicyScript) ([]byte, error) {
+	var tx []cbor.RawMessage
+
+	if err := cbor.Unmarshal(txRaw, &tx); err != nil {
+		return nil, fmt.Errorf("invalid transaction cbor: %w", err)
+	} else if len(tx) < 2 {
+		return nil, errors.New("invalid transaction cbor: witness set not found")
+	}
+
+	var witnessSet map[uint64]cbor.RawMessage
+
+	if err := cbor.Unmarshal(tx[1], &witnessSet); err != nil {
+		return nil, fmt.Errorf("invalid witness set cbor: %w", err)
+	}
+
+	if witnessSet == nil {
+		witnessSet = map[uint64]cbor.RawMessage{}
+	}
+
+	vkeyWitnesses, err := mergeVKeyWitnesses(witnessSet[vkeyWitnessesKey], witnesses)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vkeyWitnesses) > 0 {
+		witnessSet[vkeyWitnessesKey], err = cbor.Marshal(vkeyWitnesses)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(policyScripts) > 0 {
+		scripts := map[string][]byte{}
+
+		if existing, exists := witnessSet[nativeScriptsKey]; exists {
+			var existingScripts []cbor.RawMessage
+
+			if err := cbor.Unmarshal(existing, &existingScripts); err != nil {
+				return nil, fmt.Errorf("invalid native scripts cbor: %w", err)
+			}
+
+			for _, script := range existingScripts {
+				if err := addNativeScriptCbor(scripts, script); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		for _, policyScript := range policyScripts {
+			if err := addNativeScript(scripts, policyScript); err != nil {
+				return nil, err
+			}
+		}
+
+		witnessSet[nativeScriptsKey], err = cbor.Marshal(getSortedByHash(scripts))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tx[1], err = cborEncMode.Marshal(witnessSet)
+	if err != nil {
+		return nil, err
+	}
+
+	return cbor.Marshal(tx)
+}
+
+// mergeVKeyWitnesses merges existing and new vkey witnesses. Ledger keeps them in the set
+// ordered by the key hash - duplicates are ignored
+func mergeVKeyWitnesses(existing cbor.RawMessage, witnesses [][]byte) ([]cbor.RawMessage, error) {
+	var allWitnesses []cbor.RawMessage
+
+	if existing != nil {
+		if err := cbor.Unmarshal(existing, &allWitnesses); err != nil {
+			return nil, fmt.Errorf("invalid vkey witnesses cbor: %w", err)
+		}
+	}
+
+	for _, witness := range witnesses {
+		allWitnesses = append(allWitnesses, witness)
+	}
+
+	witnessesMap := make(map[string][]byte, len(allWitnesses))
+
+	for _, witness := range allWitnesses {
+		_, vKey, err := TxWitnessRaw(witness).GetSignatureAndVKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid witness: %w", err)
+		}
+
+		keyHash, err := GetKeyHashBytes(vKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, exists := witnessesMap[string(keyHash)]; !exists {
+			witnessesMap[string(keyHash)] = witness
+		}
+	}
+
+	return getSortedByHash(witnessesMap), nil
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// multisigTxRawHex is the transaction created by cardano-cli build-raw (see Test_TransactionBuilder)
+const multisigTxRawHex = "84a50083825820098236134e0f2077a6434dd9d7727126fa8b3627bcab3ae030a194d46eded73e00825820d1fd0d772be7741d9bfaf0b037d02d2867a987ccba3e6ba2ee9aa2a861b7314502825820e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f00018382581d60244877c1aeefc7fd5405a6e14d927d91758d45e37c20fa2ac89cb1671a000f424082581d704aaad0f0626a8ce7b097497e542055b6520842ade881f980e002ae661a001e847682581d703ea4c4aef89a27f111e78464d7d6717b099f85ce27109ee9e5fbddec1a001a79bf021a00040ac103196dc0075820802e4d6f15ce98826886a5451e94855e77aae779cb341d3aab1e3bae4fb2f78da10182830304858200581c47344d5bd7b2fea56336ba789579705a944760032585ef64084c92db8200581c6837232854849427dae7c45892032d7ded136c5beb13c68fda635d878200581cd215701e2eb17c741b9d306cba553f9fbaaca1e12a5925a065b90fa88200581cf01018c1d8da54c2f557679243b09af1c4dd4d9c671512b01fa5f92b8200581cf0f4837b3a306752a2b3e52394168bc7391de3dce11364b723cc55cf830304858200581c06b4c7f5254d6395b527ac3de60c1d77194df7431d85fe55ca8f107d8200581c2368e8113bd5f32d713751791d29acee9e1b5a425b0454b963b2558b8200581c79df3577e4c7d7da04872c2182b8d8829d7b477912dbf35d89287c398200581ccba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e418200581cd6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21f5d90103a100a200a36a6665655369676e65727305677369676e657273056474797065656d756c746904a26463697479684e6f76692053616464636f6d706845746865726e616c"
+
+func TestTxBuilder_BuildRawTx(t *testing.T) {
+	t.Parallel()
+
+	const (
+		testNetMagic        = 203
+		ttl                 = uint64(28096)
+		fee                 = uint64(264897)
+		multisigPolicyID    = "4aaad0f0626a8ce7b097497e542055b6520842ade881f980e002ae66"
+		feeMultisigPolicyID = "3ea4c4aef89a27f111e78464d7d6717b099f85ce27109ee9e5fbddec"
+	)
+
+	policyScriptMultiSig := NewPolicyScript([]string{
+		"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21",
+		"cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41",
+		"79df3577e4c7d7da04872c2182b8d8829d7b477912dbf35d89287c39",
+		"2368e8113bd5f32d713751791d29acee9e1b5a425b0454b963b2558b",
+		"06b4c7f5254d6395b527ac3de60c1d77194df7431d85fe55ca8f107d",
+	}, 4)
+	policyScriptFeeMultiSig := NewPolicyScript([]string{
+		"f0f4837b3a306752a2b3e52394168bc7391de3dce11364b723cc55cf",
+		"47344d5bd7b2fea56336ba789579705a944760032585ef64084c92db",
+		"f01018c1d8da54c2f557679243b09af1c4dd4d9c671512b01fa5f92b",
+		"6837232854849427dae7c45892032d7ded136c5beb13c68fda635d87",
+		"d215701e2eb17c741b9d306cba553f9fbaaca1e12a5925a065b90fa8",
+	}, 4)
+
+	multiSigAddr, err := NewPolicyScriptAddress(TestNetNetwork, multisigPolicyID)
+	require.NoError(t, err)
+
+	multiSigFeeAddr, err := NewPolicyScriptAddress(TestNetNetwork, feeMultisigPolicyID)
+	require.NoError(t, err)
+
+	metadataBytes, err := json.Marshal(map[uint64]interface{}{
+		0: map[string]interface{}{
+			"type":       "multi",
+			"signers":    5,
+			"feeSigners": 5,
+		},
+		4: map[string]interface{}{
+			"comp": "Ethernal",
+			"city": "Novi Sad",
+		},
+	})
+	require.NoError(t, err)
+
+	builder, err := NewTxBuilder(ResolveCardanoCliBinary(TestNetNetwork))
+	require.NoError(t, err)
+
+	defer builder.Dispose()
+
+	builder.SetTimeToLive(ttl).SetMetaData(metadataBytes).SetTestNetMagic(testNetMagic).SetFee(fee)
+	builder.AddOutputs(TxOutput{
+		Addr:   "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u",
+		Amount: 1_000_000,
+	}, TxOutput{
+		Addr:   multiSigAddr.String(),
+		Amount: 1_999_990,
+	}, TxOutput{
+		Addr:   multiSigFeeAddr.String(),
+		Amount: 2_000_000 - fee,
+	})
+	builder.AddInputsWithScript(policyScriptMultiSig,
+		NewTxInput("e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f", 0),
+		NewTxInput("d1fd0d772be7741d9bfaf0b037d02d2867a987ccba3e6ba2ee9aa2a861b73145", 2))
+	builder.AddInputsWithScript(policyScriptFeeMultiSig,
+		NewTxInput("098236134e0f2077a6434dd9d7727126fa8b3627bcab3ae030a194d46eded73e", 0))
+
+	txRaw, err := builder.buildRawTx(fee)
+	require.NoError(t, err)
+
+	require.Equal(t, multisigTxRawHex, hex.EncodeToString(txRaw))
+}
+
+func TestTxBuilder_BuildRawTx_MintAmount(t *testing.T) {
+	t.Parallel()
+
+	const policyID = "29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8"
+
+	build := func(amounts ...uint64) error {
+		tokens := make([]TokenAmount, len(amounts))
+		for i, amount := range amounts {
+			tokens[i] = NewTokenAmount(policyID, "token", amount)
+		}
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		builder.AddInputs(NewTxInput("e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f", 0)).
+			AddTokenMints([]IPolicyScript{NewPolicyScript([]string{policyID}, 1)}, tokens)
+
+		_, err = builder.buildRawTx(200_000)
+
+		return err
+	}
+
+	require.NoError(t, build(math.MaxInt64))
+	require.NoError(t, build(math.MaxInt64-1, 1))
+	require.ErrorContains(t, build(math.MaxInt64+1), "exceeds")
+	require.ErrorContains(t, build(math.MaxInt64, 1), "exceeds")
+}
+
+func TestGetTxHash(t *testing.T) {
+	t.Parallel()
+
+	txRaw, err := hex.DecodeString(multisigTxRawHex)
+	require.NoError(t, err)
+
+	txHash, err := GetTxHash(txRaw)
+	require.NoError(t, err)
+
+	require.Equal(t, "1b9298c51f4dc05c04cae37104124cfb76e9f98f04a7f6b8179cfe02913152ec", txHash)
+
+	_, err = GetTxHash([]byte{0x80})
+	require.Error(t, err)
+
+	_, err = GetTxHash([]byte{0x01, 0x02})
+	require.Error(t, err)
+}
+
+func TestAssembleTx(t *testing.T) {
+	t.Parallel()
+
+	txRaw, err := hex.DecodeString(multisigTxRawHex)
+	require.NoError(t, err)
+
+	txHash, err := GetTxHash(txRaw)
+	require.NoError(t, err)
+
+	wallets := make([]*Wallet, 3)
+	witnesses := make([][]byte, len(wallets))
+
+	for i := range wallets {
+		wallets[i], err = GenerateWallet(false)
+		require.NoError(t, err)
+
+		witnesses[i], err = CreateTxWitness(txHash, wallets[i])
+		require.NoError(t, err)
+	}
+
+	txSigned, err := AssembleTx(txRaw, witnesses[:2])
+	require.NoError(t, err)
+
+	// add one more witness and one witness which is already there
+	txSigned, err = AssembleTx(txSigned, witnesses[1:])
+	require.NoError(t, err)
+
+	txSignedHash, err := GetTxHash(txSigned)
+	require.NoError(t, err)
+
+	require.Equal(t, txHash, txSignedHash)
+
+	var (
+		tx         []cbor.RawMessage
+		witnessSet struct {
+			VKeyWitnesses [][][]byte        `cbor:"0,keyasint"`
+			NativeScripts []cbor.RawMessage `cbor:"1,keyasint"`
+		}
+	)
+
+	require.NoError(t, cbor.Unmarshal(txSigned, &tx))
+	require.NoError(t, cbor.Unmarshal(tx[1], &witnessSet))
+
+	require.Len(t, witnessSet.NativeScripts, 2)
+	require.Len(t, witnessSet.VKeyWitnesses, len(wallets))
+
+	txHashBytes, err := hex.DecodeString(txHash)
+	require.NoError(t, err)
+
+	for _, witness := range witnessSet.VKeyWitnesses {
+		require.NoError(t, VerifyMessage(txHashBytes, witness[0], witness[1]))
+	}
+
+	keyHash, err := GetKeyHash(wallets[0].VerificationKey)
+	require.NoError(t, err)
+
+	// one new native script and one which already exists
+	txSigned, err = AssembleTx(txSigned, nil, NewPolicyScript([]string{keyHash}, 1), NewPolicyScript([]string{
+		"f0f4837b3a306752a2b3e52394168bc7391de3dce11364b723cc55cf",
+		"47344d5bd7b2fea56336ba789579705a944760032585ef64084c92db",
+		"f01018c1d8da54c2f557679243b09af1c4dd4d9c671512b01fa5f92b",
+		"6837232854849427dae7c45892032d7ded136c5beb13c68fda635d87",
+		"d215701e2eb17c741b9d306cba553f9fbaaca1e12a5925a065b90fa8",
+	}, 4))
+	require.NoError(t, err)
+
+	require.NoError(t, cbor.Unmarshal(txSigned, &tx))
+	require.NoError(t, cbor.Unmarshal(tx[1], &witnessSet))
+
+	require.Len(t, witnessSet.NativeScripts, 3)
+	require.Len(t, witnessSet.VKeyWitnesses, len(wallets))
+	require.Equal(t, txRaw[1:len(tx[0])+1], []byte(tx[0]))
+
+	_, err = AssembleTx(txRaw, [][]byte{{0x01}})
+	require.Error(t, err)
+}
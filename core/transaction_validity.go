@@ -0,0 +1,71 @@
+package core
+
+import "fmt"
+
+// SetValidityIntervalStart sets the first slot in which transaction is valid (zero means not set).
+// Start is set automatically if attached native scripts contain after timelocks
+func (b *TxBuilder) SetValidityIntervalStart(slot uint64) *TxBuilder {
+	b.validityStart = slot
+
+	return b
+}
+
+// getValidityInterval returns validity interval start and time to live (invalid hereafter) of the transaction.
+// Interval set on the builder is narrowed so timelocks of all the attached native scripts are satisfied.
+// Start is nil if it is not set and no after timelock requires it
+func (b *TxBuilder) getValidityInterval() (*uint64, uint64, error) {
+	interval := validityInterval{
+		start:     b.validityStart,
+		hasStart:  b.validityStart > 0,
+		hereafter: b.timeToLive,
+	}
+
+	for _, policyScript := range b.getAttachedPolicyScripts() {
+		ps, err := toPolicyScript(policyScript)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var ok bool
+
+		interval, ok = ps.satisfyValidityInterval(interval)
+		if !ok {
+			return nil, 0, fmt.Errorf(
+				"timelocks of the native scripts can not be satisfied: validity start %d, time to live %d",
+				interval.start, interval.hereafter)
+		}
+	}
+
+	if !interval.hasStart {
+		return nil, interval.hereafter, nil
+	}
+
+	return &interval.start, interval.hereafter, nil
+}
+
+// getAttachedPolicyScripts returns native scripts of the inputs, mints, certificates and withdrawals
+func (b *TxBuilder) getAttachedPolicyScripts() (result []IPolicyScript) {
+	for _, inp := range b.inputs {
+		if inp.policyScript != nil {
+			result = append(result, inp.policyScript)
+		}
+	}
+
+	if len(b.mints.tokens) > 0 {
+		result = append(result, b.mints.policyScripts...)
+	}
+
+	for _, cert := range b.certificates {
+		if cert.policyScript != nil {
+			result = append(result, cert.policyScript)
+		}
+	}
+
+	for _, withdrawal := range b.withdrawals {
+		if withdrawal.policyScript != nil {
+			result = append(result, withdrawal.policyScript)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxBuilder_ValidityInterval(t *testing.T) {
+	t.Parallel()
+
+	const (
+		addr     = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		keyHash1 = "d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"
+		keyHash2 = "cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41"
+	)
+
+	utxo := Utxo{
+		Hash:   "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f",
+		Index:  0,
+		Amount: 10_000_000,
+	}
+
+	type decodedTx struct {
+		_          struct{} `cbor:",toarray"`
+		Body       map[int]cbor.RawMessage
+		WitnessSet map[int]cbor.RawMessage
+		IsValid    bool
+		Aux        cbor.RawMessage
+	}
+
+	// returns body of the built transaction with the minted token
+	buildBody := func(t *testing.T, ps PolicyScript, start, ttl uint64) (map[int]cbor.RawMessage, error) {
+		t.Helper()
+
+		script, err := ps.MarshalCBOR()
+		require.NoError(t, err)
+
+		policyID, err := GetKeyHash(append([]byte{nativeScriptHashPrefix}, script...))
+		require.NoError(t, err)
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		txRaw, _, err := builder.SetProtocolParameters(protocolParameters).
+			SetValidityIntervalStart(start).SetTimeToLive(ttl).AddUtxos(utxo).
+			AddTokenMints([]IPolicyScript{ps}, []TokenAmount{NewTokenAmount(policyID, "campaign", 1)}).
+			AddOutputs(NewTxOutput(addr, 9_800_000, NewTokenAmount(policyID, "campaign", 1))).
+			SetFee(200_000).Build()
+		if err != nil {
+			return nil, err
+		}
+
+		var tx decodedTx
+
+		require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+
+		return tx.Body, nil
+	}
+
+	// returns validity interval start and time to live of the built transaction with the minted token
+	build := func(t *testing.T, ps PolicyScript, start, ttl uint64) (uint64, uint64, error) {
+		t.Helper()
+
+		body, err := buildBody(t, ps, start, ttl)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		var validityStart, value uint64
+
+		if bytes, exists := body[8]; exists {
+			require.NoError(t, cbor.Unmarshal(bytes, &validityStart))
+		}
+
+		if bytes, exists := body[3]; exists {
+			require.NoError(t, cbor.Unmarshal(bytes, &value))
+		}
+
+		return validityStart, value, nil
+	}
+
+	campaign := NewAllPolicyScript(NewSigPolicyScript(keyHash1), NewAfterPolicyScript(1000), NewBeforePolicyScript(2000))
+
+	cases := []struct {
+		name       string
+		ps         PolicyScript
+		start, ttl uint64
+		expStart   uint64
+		expTTL     uint64
+	}{
+		{"interval not set", campaign, 0, 0, 1000, 2000},
+		{"interval inside timelocks", campaign, 1200, 1500, 1200, 1500},
+		{"interval narrowed", campaign, 500, 3000, 1000, 2000},
+		{
+			"any prefers script without timelock",
+			NewAnyPolicyScript(NewBeforePolicyScript(2000), NewSigPolicyScript(keyHash1)), 0, 3000, 0, 3000,
+		},
+		{
+			"any uses timelock",
+			NewAnyPolicyScript(NewAfterPolicyScript(5000), NewBeforePolicyScript(2000)), 0, 3000, 0, 2000,
+		},
+		{
+			"at least",
+			NewAtLeastPolicyScript(2, NewSigPolicyScript(keyHash1), NewAfterPolicyScript(100),
+				NewSigPolicyScript(keyHash2)), 0, 3000, 0, 3000,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			start, ttl, err := build(t, c.ps, c.start, c.ttl)
+			require.NoError(t, err)
+			require.Equal(t, c.expStart, start)
+			require.Equal(t, c.expTTL, ttl)
+		})
+	}
+
+	t.Run("timelocks can not be satisfied", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := build(t, campaign, 2500, 0)
+		require.ErrorContains(t, err, "can not be satisfied")
+
+		_, _, err = build(t, NewAllPolicyScript(NewAfterPolicyScript(2000), NewBeforePolicyScript(1000)), 0, 0)
+		require.ErrorContains(t, err, "can not be satisfied")
+	})
+
+	t.Run("invalid script", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := NewAtLeastPolicyScript(3, NewSigPolicyScript(keyHash1), NewSigPolicyScript(hex.EncodeToString([]byte{1})))
+
+		_, _, err := build(t, invalid, 0, 0)
+		require.Error(t, err)
+	})
+
+	t.Run("after slot zero sets validity start", func(t *testing.T) {
+		t.Parallel()
+
+		body, err := buildBody(t, NewAllPolicyScript(NewSigPolicyScript(keyHash1), NewAfterPolicyScript(0)), 0, 3000)
+		require.NoError(t, err)
+		require.Equal(t, cbor.RawMessage{0x00}, body[8])
+
+		body, err = buildBody(t, NewAllPolicyScript(NewSigPolicyScript(keyHash1)), 0, 3000)
+		require.NoError(t, err)
+		require.NotContains(t, body, 8)
+	})
+}
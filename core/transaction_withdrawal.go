@@ -0,0 +1,154 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+type txWithdrawal struct {
+	rewardAddress string
+	amount        uint64
+	policyScript  IPolicyScript // not nil if reward address is script address
+}
+
+// AddWithdrawal withdraws amount of rewards from the reward (stake) address. Withdrawal from the key hash
+// address must be witnessed by the stake key. Withdrawals are included in Balance
+func (b *TxBuilder) AddWithdrawal(rewardAddress string, amount uint64) *TxBuilder {
+	b.withdrawals = append(b.withdrawals, txWithdrawal{
+		rewardAddress: rewardAddress,
+		amount:        amount,
+	})
+
+	return b
+}
+
+// AddWithdrawalWithScript withdraws amount of rewards from the script hash reward address.
+// Native script is attached to the transaction and its signers are included in the fee estimation
+func (b *TxBuilder) AddWithdrawalWithScript(
+	policyScript IPolicyScript, rewardAddress string, amount uint64,
+) *TxBuilder {
+	b.withdrawals = append(b.withdrawals, txWithdrawal{
+		rewardAddress: rewardAddress,
+		amount:        amount,
+		policyScript:  policyScript,
+	})
+
+	return b
+}
+
+// getWithdrawals returns withdrawals for the transaction body (reward address bytes -> amount)
+// and adds native scripts of the script withdrawals into the scripts
+func (b *TxBuilder) getWithdrawals(scripts map[string][]byte) (map[cbor.ByteString]uint64, error) {
+	if len(b.withdrawals) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[cbor.ByteString]uint64, len(b.withdrawals))
+
+	for _, withdrawal := range b.withdrawals {
+		addr, stake, err := getRewardAddressStake(withdrawal.rewardAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		key := cbor.ByteString(addr.GetBytes())
+		if _, exists := result[key]; exists {
+			return nil, fmt.Errorf("duplicate withdrawal from %s", withdrawal.rewardAddress)
+		}
+
+		result[key] = withdrawal.amount
+
+		if withdrawal.policyScript == nil {
+			continue
+		}
+
+		if !stake.IsScript {
+			return nil, fmt.Errorf("reward address %s is not script address", withdrawal.rewardAddress)
+		}
+
+		script, err := getNativeScriptCbor(withdrawal.policyScript)
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := getNativeScriptHash(script)
+		if err != nil {
+			return nil, err
+		}
+
+		if !bytes.Equal(hash, stake.Payload[:]) {
+			return nil, fmt.Errorf("script hash %x does not match reward address %s", hash, withdrawal.rewardAddress)
+		}
+
+		if err := addNativeScriptCbor(scripts, script); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// getWithdrawalsAmount returns sum of all the withdrawals
+func (b *TxBuilder) getWithdrawalsAmount() (result uint64) {
+	for _, withdrawal := range b.withdrawals {
+		result += withdrawal.amount
+	}
+
+	return result
+}
+
+// getCredentialsWitnessCount returns number of witnesses needed for the certificates, withdrawals and votes:
+// unique key hashes and signers of the certificates and withdrawals native scripts
+func (b *TxBuilder) getCredentialsWitnessCount() int {
+	keyHashes := map[[KeyHashSize]byte]bool{}
+	count := 0
+
+	for _, cert := range b.certificates {
+		if cert.policyScript != nil {
+			count += cert.policyScript.GetCount()
+
+			continue
+		}
+
+		if credential := cert.certificate.GetWitnessCredential(); credential != nil && !credential.IsScript {
+			keyHashes[credential.Payload] = true
+		}
+	}
+
+	for _, withdrawal := range b.withdrawals {
+		if withdrawal.policyScript != nil {
+			count += withdrawal.policyScript.GetCount()
+
+			continue
+		}
+
+		// invalid address is reported by Build
+		if _, stake, err := getRewardAddressStake(withdrawal.rewardAddress); err == nil && !stake.IsScript {
+			keyHashes[stake.Payload] = true
+		}
+	}
+
+	for _, vote := range b.votes {
+		if !vote.voter.IsScript() {
+			keyHashes[vote.voter.Hash] = true
+		}
+	}
+
+	return count + len(keyHashes)
+}
+
+func getRewardAddressStake(rewardAddress string) (*CardanoAddress, CardanoAddressPayload, error) {
+	addr, err := NewCardanoAddressFromString(rewardAddress)
+	if err != nil {
+		return nil, CardanoAddressPayload{}, fmt.Errorf("invalid reward address %s: %w", rewardAddress, err)
+	}
+
+	info := addr.GetInfo()
+	if info.AddressType != RewardAddress || info.Stake == nil {
+		return nil, CardanoAddressPayload{}, fmt.Errorf("address %s is not reward address", rewardAddress)
+	}
+
+	return addr, *info.Stake, nil
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxBuilder_Withdrawals(t *testing.T) {
+	t.Parallel()
+
+	const (
+		addr       = "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u"
+		utxoAmount = uint64(3_000_000)
+		rewards    = uint64(1_234_567)
+	)
+
+	wallet, err := GenerateWallet(true)
+	require.NoError(t, err)
+
+	rewardAddr, err := NewRewardAddress(TestNetNetwork, wallet.StakeVerificationKey)
+	require.NoError(t, err)
+
+	policyScript := NewPolicyScript([]string{
+		"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21",
+		"cba89c7084bf0ce4bf404346b668a7e83c8c9c250d1cafd8d8996e41",
+		"79df3577e4c7d7da04872c2182b8d8829d7b477912dbf35d89287c39",
+	}, 2)
+
+	policyScriptBytes, err := policyScript.MarshalCBOR()
+	require.NoError(t, err)
+
+	scriptHash, err := GetKeyHashBytes(append([]byte{nativeScriptHashPrefix}, policyScriptBytes...))
+	require.NoError(t, err)
+
+	scriptRewardAddr, err := CardanoAddressInfo{
+		AddressType: RewardAddress,
+		Network:     TestNetNetwork,
+		Stake: &CardanoAddressPayload{
+			Payload:  [KeyHashSize]byte(scriptHash),
+			IsScript: true,
+		},
+	}.ToCardanoAddress()
+	require.NoError(t, err)
+
+	utxo := Utxo{
+		Hash:   "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f",
+		Index:  0,
+		Amount: utxoAmount,
+	}
+
+	var pp ProtocolParameters
+
+	require.NoError(t, json.Unmarshal(protocolParameters, &pp))
+
+	type decodedTx struct {
+		_          struct{} `cbor:",toarray"`
+		Body       map[int]cbor.RawMessage
+		WitnessSet map[int]cbor.RawMessage
+		IsValid    bool
+		Aux        cbor.RawMessage
+	}
+
+	newBuilder := func(t *testing.T) *TxBuilder {
+		t.Helper()
+
+		builder, err := NewTxBuilder("")
+		require.NoError(t, err)
+
+		return builder.SetProtocolParameters(protocolParameters).AddUtxos(utxo)
+	}
+
+	t.Run("key hash reward address", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newBuilder(t).AddWithdrawal(rewardAddr.String(), rewards)
+
+		fee, err := builder.Balance(addr)
+		require.NoError(t, err)
+		require.Equal(t, utxoAmount+rewards-fee, builder.outputs[0].Amount)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		var (
+			tx          decodedTx
+			withdrawals map[cbor.ByteString]uint64
+		)
+
+		require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+		require.NoError(t, cbor.Unmarshal(tx.Body[5], &withdrawals))
+		require.Equal(t, map[cbor.ByteString]uint64{cbor.ByteString(rewardAddr.GetBytes()): rewards}, withdrawals)
+
+		// payment key and stake key witnesses
+		expectedFee, err := CalculateMinFee(txRaw, pp, 2, 0)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, fee, expectedFee)
+		require.LessOrEqual(t, fee, expectedFee+10*44)
+	})
+
+	t.Run("script hash reward address", func(t *testing.T) {
+		t.Parallel()
+
+		builder := newBuilder(t).AddWithdrawalWithScript(policyScript, scriptRewardAddr.String(), rewards)
+
+		fee, err := builder.Balance(addr)
+		require.NoError(t, err)
+		require.Equal(t, utxoAmount+rewards-fee, builder.outputs[0].Amount)
+
+		txRaw, _, err := builder.Build()
+		require.NoError(t, err)
+
+		var (
+			tx      decodedTx
+			scripts []cbor.RawMessage
+		)
+
+		require.NoError(t, cbor.Unmarshal(txRaw, &tx))
+		require.Contains(t, tx.Body, 5)
+		require.NoError(t, cbor.Unmarshal(tx.WitnessSet[nativeScriptsKey], &scripts))
+		require.Equal(t, []cbor.RawMessage{policyScriptBytes}, scripts)
+
+		// payment key and policy script signers
+		expectedFee, err := CalculateMinFee(txRaw, pp, 1+policyScript.GetCount(), 0)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, fee, expectedFee)
+		require.LessOrEqual(t, fee, expectedFee+10*44)
+	})
+
+	t.Run("invalid withdrawals", func(t *testing.T) {
+		t.Parallel()
+
+		otherScript := NewPolicyScript([]string{"d6b67f93ffa4e2651271cc9bcdbdedb2539911266b534d9c163cba21"}, 1)
+
+		for _, c := range []struct {
+			builder *TxBuilder
+			err     string
+		}{
+			{newBuilder(t).AddWithdrawal(addr, rewards), "is not reward address"},
+			{newBuilder(t).AddWithdrawal("stake_test1invalid", rewards), "invalid reward address"},
+			{
+				newBuilder(t).AddWithdrawal(rewardAddr.String(), rewards).AddWithdrawal(rewardAddr.String(), 1),
+				"duplicate withdrawal",
+			},
+			{newBuilder(t).AddWithdrawalWithScript(policyScript, rewardAddr.String(), rewards), "is not script address"},
+			{newBuilder(t).AddWithdrawalWithScript(otherScript, scriptRewardAddr.String(), rewards), "does not match"},
+		} {
+			_, _, err := c.builder.AddOutputs(NewTxOutput(addr, 1_000_000)).SetFee(200_000).Build()
+			require.ErrorContains(t, err, c.err)
+		}
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 type blockFrostQueryUtxoResponse struct {
@@ -20,6 +21,9 @@ type blockFrostQueryUtxoResponse struct {
 		Unit     string `json:"unit"`
 		Quantity string `json:"quantity"`
 	} `json:"amount"`
+	DataHash            *string `json:"data_hash"`
+	InlineDatum         *string `json:"inline_datum"`
+	ReferenceScriptHash *string `json:"reference_script_hash"`
 }
 
 type TxProviderBlockFrost struct {
@@ -27,7 +31,11 @@ type TxProviderBlockFrost struct {
 	projectID string
 }
 
-var _ ITxProvider = (*TxProviderBlockFrost)(nil)
+var (
+	_ ITxProvider       = (*TxProviderBlockFrost)(nil)
+	_ ITxEvaluator      = (*TxProviderBlockFrost)(nil)
+	_ IRewardsRetriever = (*TxProviderBlockFrost)(nil)
+)
 
 func NewTxProviderBlockFrost(url string, projectID string) *TxProviderBlockFrost {
 	return &TxProviderBlockFrost{
@@ -119,32 +127,154 @@ func (b *TxProviderBlockFrost) GetUtxos(ctx context.Context, addr string) ([]Utx
 			if x.Unit == AdaTokenName {
 				amount = tmpAmount
 			} else {
-				policyID, name := x.Unit[0:KeyHashSize*2], x.Unit[KeyHashSize*2:]
+				if len(x.Unit) < KeyHashSize*2 {
+					return nil, fmt.Errorf("invalid asset unit: %s", x.Unit)
+				}
 
-				realName, err := hex.DecodeString(name)
-				if err == nil {
-					name = string(realName)
+				token, err := NewTokenAmountWithHexName(x.Unit[:KeyHashSize*2], x.Unit[KeyHashSize*2:], tmpAmount)
+				if err != nil {
+					return nil, err
 				}
 
-				tokens = append(tokens, TokenAmount{
-					PolicyID: policyID,
-					Name:     name,
-					Amount:   tmpAmount,
-				})
+				tokens = append(tokens, token)
 			}
 		}
 
-		response[i] = Utxo{
-			Hash:   bfUtxo.Hash,
-			Index:  bfUtxo.Index,
-			Amount: amount,
-			Tokens: tokens,
+		response[i], err = NewUtxo(bfUtxo.Hash, bfUtxo.Index, amount, tokens...)
+		if err != nil {
+			return nil, err
+		}
+
+		if bfUtxo.InlineDatum != nil {
+			response[i].InlineDatum, err = decodeInlineDatumHex(*bfUtxo.InlineDatum)
+			if err != nil {
+				return nil, err
+			}
+		} else if bfUtxo.DataHash != nil {
+			// blockfrost returns hash of the inline datum too so the hash is used only without inline datum
+			response[i].DatumHash = *bfUtxo.DataHash
+		}
+
+		if bfUtxo.ReferenceScriptHash != nil {
+			response[i].ReferenceScript, err = b.getReferenceScript(ctx, *bfUtxo.ReferenceScriptHash)
+			if err != nil {
+				return nil, fmt.Errorf("reference script of %s#%d: %w", bfUtxo.Hash, bfUtxo.Index, err)
+			}
 		}
 	}
 
 	return response, nil
 }
 
+// getReferenceScript retrieves script by its hash. Cbor is available only for plutus scripts
+// so native scripts are retrieved as json
+func (b *TxProviderBlockFrost) getReferenceScript(ctx context.Context, hash string) (*ReferenceScript, error) {
+	var script struct {
+		Type string `json:"type"`
+	}
+
+	if err := b.getJSON(ctx, "/scripts/"+hash, &script); err != nil {
+		return nil, err
+	}
+
+	var scriptType uint64
+
+	switch script.Type {
+	case "timelock":
+		var nativeScript struct {
+			JSON PolicyScript `json:"json"`
+		}
+
+		if err := b.getJSON(ctx, "/scripts/"+hash+"/json", &nativeScript); err != nil {
+			return nil, err
+		}
+
+		result := NewNativeReferenceScript(nativeScript.JSON)
+		if !hasReferenceScriptHash(result, hash) {
+			return nil, fmt.Errorf("reference script does not match hash %s", hash)
+		}
+
+		return result, nil
+	case "plutusV1":
+		scriptType = uint64(PlutusV1)
+	case "plutusV2":
+		scriptType = uint64(PlutusV2)
+	case "plutusV3":
+		scriptType = uint64(PlutusV3)
+	default:
+		return nil, fmt.Errorf("unsupported script type: %s", script.Type)
+	}
+
+	var plutusScript struct {
+		Cbor string `json:"cbor"`
+	}
+
+	if err := b.getJSON(ctx, "/scripts/"+hash+"/cbor", &plutusScript); err != nil {
+		return nil, err
+	}
+
+	scriptBytes, err := hex.DecodeString(plutusScript.Cbor)
+	if err != nil {
+		return nil, err
+	}
+
+	return newReferenceScriptFromBytes(scriptType, scriptBytes, hash)
+}
+
+func (b *TxProviderBlockFrost) getJSON(ctx context.Context, path string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", b.url+path, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("project_id", b.projectID)
+
+	resp, err := new(http.Client).Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return getErrorFromResponse(resp)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (b *TxProviderBlockFrost) GetRewardsBalance(ctx context.Context, rewardAddress string) (uint64, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/accounts/%s", b.url, rewardAddress), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("project_id", b.projectID)
+
+	resp, err := new(http.Client).Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, nil // reward address is not registered
+	} else if resp.StatusCode != http.StatusOK {
+		return 0, getErrorFromResponse(resp)
+	}
+
+	var bfResponse struct {
+		WithdrawableAmount string `json:"withdrawable_amount"`
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(&bfResponse); err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(bfResponse.WithdrawableAmount, 0, 64)
+}
+
 func (b *TxProviderBlockFrost) GetTip(ctx context.Context) (QueryTipData, error) {
 	// Create a request with the JSON payload
 	req, err := http.NewRequestWithContext(ctx, "GET", b.url+"/blocks/latest", nil)
@@ -211,6 +341,73 @@ func (b *TxProviderBlockFrost) SubmitTx(ctx context.Context, txSigned []byte) er
 	return nil
 }
 
+// EvaluateTx implements ITxEvaluator. Blockfrost returns ogmios v5 response
+// ({"result": {"EvaluationResult": {"spend:0": {"memory": 1, "steps": 2}}}})
+func (b *TxProviderBlockFrost) EvaluateTx(ctx context.Context, txRaw []byte) (map[RedeemerPointer]ExUnits, error) {
+	// Create a request with the hex encoded transaction
+	req, err := http.NewRequestWithContext(
+		ctx, "POST", b.url+"/utils/txs/evaluate", strings.NewReader(hex.EncodeToString(txRaw)))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/cbor")
+	req.Header.Set("project_id", b.projectID)
+
+	// Make the HTTP request
+	resp, err := new(http.Client).Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, getErrorFromResponse(resp)
+	}
+
+	var bfResponse struct {
+		Result struct {
+			EvaluationResult map[string]struct {
+				Memory uint64 `json:"memory"`
+				Steps  uint64 `json:"steps"`
+			} `json:"EvaluationResult"`
+			EvaluationFailure json.RawMessage `json:"EvaluationFailure"`
+		} `json:"result"`
+		Fault json.RawMessage `json:"fault"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&bfResponse); err != nil {
+		return nil, err
+	}
+
+	if bfResponse.Result.EvaluationFailure != nil {
+		return nil, fmt.Errorf("blockfrost evaluate tx error: %s", string(bfResponse.Result.EvaluationFailure))
+	} else if bfResponse.Fault != nil {
+		return nil, fmt.Errorf("blockfrost evaluate tx error: %s", string(bfResponse.Fault))
+	}
+
+	result := make(map[RedeemerPointer]ExUnits, len(bfResponse.Result.EvaluationResult))
+
+	for key, budget := range bfResponse.Result.EvaluationResult {
+		purpose, indexStr, _ := strings.Cut(key, ":")
+
+		index, err := strconv.ParseUint(indexStr, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redeemer pointer %s: %w", key, err)
+		}
+
+		pointer, err := newRedeemerPointerFromPurpose(purpose, uint32(index))
+		if err != nil {
+			return nil, err
+		}
+
+		result[pointer] = NewExUnits(budget.Memory, budget.Steps)
+	}
+
+	return result, nil
+}
+
 func (b *TxProviderBlockFrost) GetTxByHash(ctx context.Context, hash string) (map[string]interface{}, error) {
 	// Create a request with the JSON payload
 	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/txs/%s", b.url, hash), nil)
@@ -271,6 +468,32 @@ func convertProtocolParameters(bytes []byte) ([]byte, error) {
 		MaxCollateralInputs uint64                      `json:"max_collateral_inputs"`
 		MaxValSize          string                      `json:"max_val_size"`
 		CostModels          map[string]map[string]int64 `json:"cost_models"`
+		CostModelsRaw       map[string][]int64          `json:"cost_models_raw"`
+
+		MinFeeRefScriptCostPerByte float64 `json:"min_fee_ref_script_cost_per_byte"`
+
+		DRepDeposit              string  `json:"drep_deposit"`
+		DRepActivity             string  `json:"drep_activity"`
+		GovActionDeposit         string  `json:"gov_action_deposit"`
+		GovActionLifetime        string  `json:"gov_action_lifetime"`
+		CommitteeMinSize         string  `json:"committee_min_size"`
+		CommitteeMaxTermLength   string  `json:"committee_max_term_length"`
+		DvtMotionNoConfidence    float64 `json:"dvt_motion_no_confidence"`
+		DvtCommitteeNormal       float64 `json:"dvt_committee_normal"`
+		DvtCommitteeNoConfidence float64 `json:"dvt_committee_no_confidence"`
+		DvtUpdateToConstitution  float64 `json:"dvt_update_to_constitution"`
+		DvtHardForkInitiation    float64 `json:"dvt_hard_fork_initiation"`
+		DvtPPNetworkGroup        float64 `json:"dvt_p_p_network_group"`
+		DvtPPEconomicGroup       float64 `json:"dvt_p_p_economic_group"`
+		DvtPPTechnicalGroup      float64 `json:"dvt_p_p_technical_group"`
+		DvtPPGovGroup            float64 `json:"dvt_p_p_gov_group"`
+		DvtTreasuryWithdrawal    float64 `json:"dvt_treasury_withdrawal"`
+		PvtMotionNoConfidence    float64 `json:"pvt_motion_no_confidence"`
+		PvtCommitteeNormal       float64 `json:"pvt_committee_normal"`
+		PvtCommitteeNoConfidence float64 `json:"pvt_committee_no_confidence"`
+		PvtHardForkInitiation    float64 `json:"pvt_hard_fork_initiation"`
+		PvtPPSecurityGroup       float64 `json:"pvt_p_p_security_group"`
+		PvtppSecurityGroup       float64 `json:"pvtpp_security_group"` // older name of the same parameter
 	}
 
 	if err := json.Unmarshal(bytes, &bfpp); err != nil {
@@ -310,9 +533,46 @@ func convertProtocolParameters(bytes []byte) ([]byte, error) {
 		MaxCollateralInputs: bfpp.MaxCollateralInputs,
 		MaxValueSize:        strToUInt64(bfpp.MaxValSize),
 		CostModels:          map[string][]int64{},
+
+		MinFeeRefScriptCostPerByte: bfpp.MinFeeRefScriptCostPerByte,
+
+		DRepDeposit:  strToUInt64(bfpp.DRepDeposit),
+		DRepActivity: strToUInt64(bfpp.DRepActivity),
+		DRepVotingThresholds: ProtocolParametersDRepVotingThresholds{
+			MotionNoConfidence:    bfpp.DvtMotionNoConfidence,
+			CommitteeNormal:       bfpp.DvtCommitteeNormal,
+			CommitteeNoConfidence: bfpp.DvtCommitteeNoConfidence,
+			UpdateToConstitution:  bfpp.DvtUpdateToConstitution,
+			HardForkInitiation:    bfpp.DvtHardForkInitiation,
+			PPNetworkGroup:        bfpp.DvtPPNetworkGroup,
+			PPEconomicGroup:       bfpp.DvtPPEconomicGroup,
+			PPTechnicalGroup:      bfpp.DvtPPTechnicalGroup,
+			PPGovGroup:            bfpp.DvtPPGovGroup,
+			TreasuryWithdrawal:    bfpp.DvtTreasuryWithdrawal,
+		},
+		PoolVotingThresholds: ProtocolParametersPoolVotingThresholds{
+			MotionNoConfidence:    bfpp.PvtMotionNoConfidence,
+			CommitteeNormal:       bfpp.PvtCommitteeNormal,
+			CommitteeNoConfidence: bfpp.PvtCommitteeNoConfidence,
+			HardForkInitiation:    bfpp.PvtHardForkInitiation,
+			PPSecurityGroup:       max(bfpp.PvtPPSecurityGroup, bfpp.PvtppSecurityGroup),
+		},
+		GovActionDeposit:       strToUInt64(bfpp.GovActionDeposit),
+		GovActionLifetime:      strToUInt64(bfpp.GovActionLifetime),
+		CommitteeMinSize:       strToUInt64(bfpp.CommitteeMinSize),
+		CommitteeMaxTermLength: strToUInt64(bfpp.CommitteeMaxTermLength),
+	}
+
+	// raw cost models are ordered as the ledger expects them (needed for the script data hash)
+	for scriptName, values := range bfpp.CostModelsRaw {
+		pp.CostModels[scriptName] = values
 	}
 
 	for scriptName, mapValue := range bfpp.CostModels {
+		if _, exists := pp.CostModels[scriptName]; exists {
+			continue
+		}
+
 		ints := make([]int64, len(mapValue))
 
 		for k, v := range mapValue {
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -18,7 +20,10 @@ type TxProviderCli struct {
 	cardanoCliBinary string
 }
 
-var _ ITxProvider = (*TxProviderCli)(nil)
+var (
+	_ ITxProvider       = (*TxProviderCli)(nil)
+	_ IRewardsRetriever = (*TxProviderCli)(nil)
+)
 
 func NewTxProviderCli(testNetMagic uint, socketPath string, cardanoCliBinary string) (*TxProviderCli, error) {
 	baseDirectory, err := os.MkdirTemp("", "cardano-txs")
@@ -53,79 +58,205 @@ func (b *TxProviderCli) GetProtocolParameters(_ context.Context) ([]byte, error)
 }
 
 func (b *TxProviderCli) GetUtxos(_ context.Context, addr string) ([]Utxo, error) {
+	// json output (written to the file) contains datums and reference scripts
+	outFile, err := os.CreateTemp(b.baseDirectory, "utxos-*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	outFilePath := outFile.Name()
+
+	outFile.Close()
+
+	defer os.Remove(outFilePath)
+
 	args := append([]string{
 		"query", "utxo",
 		"--socket-path", b.socketPath,
 		"--address", addr,
+		"--out-file", outFilePath,
 	}, getTestNetMagicArgs(b.testNetMagic)...)
 
-	output, err := runCommand(b.cardanoCliBinary, args)
+	if _, err := runCommand(b.cardanoCliBinary, args); err != nil {
+		return nil, err
+	}
+
+	output, err := os.ReadFile(outFilePath)
 	if err != nil {
 		return nil, err
 	}
 
-	rows := strings.Split(strings.Trim(output, "\n"), "\n")[2:]
-	inputs := make([]Utxo, len(rows))
+	return parseUtxosCliOutput(string(output))
+}
 
-	for i, x := range rows {
-		j, cnt, parts := 0, 0, strings.Split(x, " ")
+// parseUtxosCliOutput parses json output of the cardano-cli query utxo command
+func parseUtxosCliOutput(output string) ([]Utxo, error) {
+	var cliUtxos map[string]struct {
+		Value           map[string]json.RawMessage `json:"value"`
+		DatumHash       *string                    `json:"datumhash"`
+		InlineDatum     json.RawMessage            `json:"inlineDatum"`
+		InlineDatumHash *string                    `json:"inlineDatumhash"`
+		InlineDatumRaw  *string                    `json:"inlineDatumRaw"`
+		ReferenceScript *struct {
+			Script json.RawMessage `json:"script"`
+		} `json:"referenceScript"`
+	}
+
+	if err := json.Unmarshal([]byte(output), &cliUtxos); err != nil {
+		return nil, err
+	}
+
+	inputs := make([]Utxo, 0, len(cliUtxos))
+
+	for key, cliUtxo := range cliUtxos {
+		hash, indexStr, _ := strings.Cut(key, "#")
 
-		for j < len(parts) {
-			partStr := parts[j]
-			j++
+		index, err := strconv.ParseUint(indexStr, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid utxo %s: %w", key, err)
+		}
+
+		var (
+			amount uint64
+			tokens []TokenAmount
+		)
+
+		for policyID, value := range cliUtxo.Value {
+			if policyID == AdaTokenName {
+				if err := json.Unmarshal(value, &amount); err != nil {
+					return nil, err
+				}
 
-			if partStr == "" {
 				continue
 			}
 
-			cnt++
-			switch cnt {
-			case 1:
-				inputs[i].Hash = partStr
-			case 2:
-				outputIndex, err := strconv.ParseUint(partStr, 0, 64)
+			var assets map[string]uint64
+
+			if err := json.Unmarshal(value, &assets); err != nil {
+				return nil, err
+			}
+
+			for hexName, tokenAmount := range assets {
+				token, err := NewTokenAmountWithHexName(policyID, hexName, tokenAmount)
 				if err != nil {
 					return nil, err
 				}
 
-				inputs[i].Index = uint32(outputIndex) //nolint:gosec
-			default:
-				if partStr == "" || partStr == "+" || strings.Contains(partStr, "Datum") {
-					continue
-				}
+				tokens = append(tokens, token)
+			}
+		}
 
-				amount, err := strconv.ParseUint(partStr, 10, 64)
-				if err != nil {
-					continue
-				}
+		// tokens of the utxo are sorted by the value
+		utxo, err := NewUtxo(hash, uint32(index), amount, tokens...)
+		if err != nil {
+			return nil, err
+		}
 
-				if j < len(parts) {
-					if parts[j] == AdaTokenName {
-						inputs[i].Amount = amount
-
-						j++
-					} else if tokenData := strings.Split(parts[j], "."); len(tokenData) == 2 {
-						realName, err := hex.DecodeString(tokenData[1])
-						if err == nil {
-							tokenData[1] = string(realName)
-						}
-
-						inputs[i].Tokens = append(inputs[i].Tokens, TokenAmount{
-							PolicyID: tokenData[0],
-							Name:     tokenData[1],
-							Amount:   amount,
-						})
-
-						j++
-					}
-				}
+		switch {
+		case cliUtxo.InlineDatumRaw != nil:
+			utxo.InlineDatum, err = decodeInlineDatumHex(*cliUtxo.InlineDatumRaw)
+			if err != nil {
+				return nil, err
+			}
+		case len(cliUtxo.InlineDatum) > 0 && string(cliUtxo.InlineDatum) != "null":
+			// older versions print only json representation of the inline datum. Re-encoded datum is used
+			// only if it is byte exact, i.e. its hash matches the inline datum hash printed by the cli
+			utxo.InlineDatum, err = reencodeCliInlineDatum(cliUtxo.InlineDatum, cliUtxo.InlineDatumHash)
+			if err != nil {
+				return nil, fmt.Errorf("utxo %s: %w", key, err)
 			}
+		case cliUtxo.DatumHash != nil:
+			utxo.DatumHash = *cliUtxo.DatumHash
 		}
+
+		if cliUtxo.ReferenceScript != nil {
+			utxo.ReferenceScript, err = newReferenceScriptFromCliEnvelope(cliUtxo.ReferenceScript.Script)
+			if err != nil {
+				return nil, fmt.Errorf("reference script of %s: %w", key, err)
+			}
+		}
+
+		inputs = append(inputs, utxo)
 	}
 
+	// json object is not ordered so the utxos are sorted as in the text output
+	sort.Slice(inputs, func(i, j int) bool {
+		if inputs[i].Hash != inputs[j].Hash {
+			return inputs[i].Hash < inputs[j].Hash
+		}
+
+		return inputs[i].Index < inputs[j].Index
+	})
+
 	return inputs, nil
 }
 
+// newReferenceScriptFromCliEnvelope parses reference script printed by the cli as text envelope
+func newReferenceScriptFromCliEnvelope(envelope json.RawMessage) (*ReferenceScript, error) {
+	var data struct {
+		Type    string `json:"type"`
+		CborHex string `json:"cborHex"`
+	}
+
+	if err := json.Unmarshal(envelope, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Type != "SimpleScript" {
+		script, err := NewPlutusScriptFromEnvelope(envelope)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewPlutusReferenceScript(script), nil
+	}
+
+	script, err := hex.DecodeString(data.CborHex)
+	if err != nil {
+		return nil, err
+	}
+
+	return newReferenceScriptFromBytes(scriptRefNativeType, script, "")
+}
+
+func reencodeCliInlineDatum(datumJSON json.RawMessage, datumHash *string) ([]byte, error) {
+	if datumHash == nil {
+		return nil, errors.New("raw inline datum is not available and its hash is unknown")
+	}
+
+	datum, err := NewPlutusDataFromJSON(datumJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := datum.MarshalCBOR()
+	if err != nil {
+		return nil, err
+	}
+
+	if actualHash := GetDatumHash(result); !strings.EqualFold(actualHash, *datumHash) {
+		return nil, fmt.Errorf("raw inline datum is not available and re-encoded datum hash %s does not match %s",
+			actualHash, *datumHash)
+	}
+
+	return result, nil
+}
+
+func (b *TxProviderCli) GetRewardsBalance(_ context.Context, rewardAddress string) (uint64, error) {
+	args := append([]string{
+		"query", "stake-address-info",
+		"--socket-path", b.socketPath,
+		"--address", rewardAddress,
+	}, getTestNetMagicArgs(b.testNetMagic)...)
+
+	res, err := runCommand(b.cardanoCliBinary, args)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseStakeAddressInfoCliOutput(res)
+}
+
 func (b *TxProviderCli) GetTip(_ context.Context) (QueryTipData, error) {
 	args := append([]string{
 		"query", "tip",
@@ -179,3 +310,22 @@ func (b *TxProviderCli) SubmitTx(_ context.Context, txSigned []byte) error {
 func (b *TxProviderCli) GetTxByHash(ctx context.Context, hash string) (map[string]interface{}, error) {
 	panic("not implemented") //nolint:gocritic
 }
+
+// parseStakeAddressInfoCliOutput returns rewards from the stake-address-info output
+// (empty array if the address is not registered)
+func parseStakeAddressInfoCliOutput(output string) (uint64, error) {
+	var infos []struct {
+		RewardAccountBalance uint64 `json:"rewardAccountBalance"`
+	}
+
+	if err := json.Unmarshal([]byte(output), &infos); err != nil {
+		return 0, err
+	}
+
+	rewards := uint64(0)
+	for _, info := range infos {
+		rewards += info.RewardAccountBalance
+	}
+
+	return rewards, nil
+}
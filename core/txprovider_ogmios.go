@@ -17,7 +17,11 @@ type TxProviderOgmios struct {
 	url string
 }
 
-var _ ITxProvider = (*TxProviderOgmios)(nil)
+var (
+	_ ITxProvider       = (*TxProviderOgmios)(nil)
+	_ ITxEvaluator      = (*TxProviderOgmios)(nil)
+	_ IRewardsRetriever = (*TxProviderOgmios)(nil)
+)
 
 func NewTxProviderOgmios(url string) *TxProviderOgmios {
 	return &TxProviderOgmios{
@@ -54,6 +58,9 @@ func (o *TxProviderOgmios) GetProtocolParameters(ctx context.Context) ([]byte, e
 		return v
 	}
 
+	drepThresholds := params.Result.DelegateRepresentativeVotingThresholds
+	poolThresholds := params.Result.StakePoolVotingThresholds
+
 	pp := ProtocolParameters{
 		ProtocolVersion:      NewProtocolParametersVersion(params.Result.Version.Major, params.Result.Version.Minor),
 		MaxBlockHeaderSize:   params.Result.MaxBlockHeaderSize.Bytes,
@@ -82,6 +89,36 @@ func (o *TxProviderOgmios) GetProtocolParameters(ctx context.Context) ([]byte, e
 		MaxCollateralInputs: params.Result.MaxCollateralInputs,
 		MaxValueSize:        params.Result.MaxValueSize.Bytes,
 		CostModels:          map[string][]int64{},
+
+		MinFeeRefScriptCostPerByte: params.Result.MinFeeReferenceScripts.Base,
+		MinFeeRefScriptRange:       params.Result.MinFeeReferenceScripts.Range,
+		MinFeeRefScriptMultiplier:  params.Result.MinFeeReferenceScripts.Multiplier,
+
+		DRepDeposit:  params.Result.DelegateRepresentativeDeposit.Ada.Lovelace,
+		DRepActivity: params.Result.DelegateRepresentativeMaxIdleTime,
+		DRepVotingThresholds: ProtocolParametersDRepVotingThresholds{
+			MotionNoConfidence:    asFloat(drepThresholds.NoConfidence),
+			CommitteeNormal:       asFloat(drepThresholds.ConstitutionalCommittee.Default),
+			CommitteeNoConfidence: asFloat(drepThresholds.ConstitutionalCommittee.StateOfNoConfidence),
+			UpdateToConstitution:  asFloat(drepThresholds.Constitution),
+			HardForkInitiation:    asFloat(drepThresholds.HardForkInitiation),
+			PPNetworkGroup:        asFloat(drepThresholds.ProtocolParametersUpdate.Network),
+			PPEconomicGroup:       asFloat(drepThresholds.ProtocolParametersUpdate.Economic),
+			PPTechnicalGroup:      asFloat(drepThresholds.ProtocolParametersUpdate.Technical),
+			PPGovGroup:            asFloat(drepThresholds.ProtocolParametersUpdate.Governance),
+			TreasuryWithdrawal:    asFloat(drepThresholds.TreasuryWithdrawals),
+		},
+		PoolVotingThresholds: ProtocolParametersPoolVotingThresholds{
+			MotionNoConfidence:    asFloat(poolThresholds.NoConfidence),
+			CommitteeNormal:       asFloat(poolThresholds.ConstitutionalCommittee.Default),
+			CommitteeNoConfidence: asFloat(poolThresholds.ConstitutionalCommittee.StateOfNoConfidence),
+			HardForkInitiation:    asFloat(poolThresholds.HardForkInitiation),
+			PPSecurityGroup:       asFloat(poolThresholds.ProtocolParametersUpdate.Security),
+		},
+		GovActionDeposit:       params.Result.GovernanceActionDeposit.Ada.Lovelace,
+		GovActionLifetime:      params.Result.GovernanceActionLifetime,
+		CommitteeMinSize:       params.Result.ConstitutionalCommitteeMinSize,
+		CommitteeMaxTermLength: params.Result.ConstitutionalCommitteeMaxTermLength,
 	}
 
 	for scriptName, values := range params.Result.PlutusCostModels {
@@ -153,31 +190,71 @@ func (o *TxProviderOgmios) GetUtxos(ctx context.Context, addr string) ([]Utxo, e
 				adaValue = nameValueMap[AdaTokenName]
 			} else {
 				for name, value := range nameValueMap {
-					realName, err := hex.DecodeString(name)
-					if err == nil {
-						name = string(realName)
+					token, err := NewTokenAmountWithHexName(policyID, name, value)
+					if err != nil {
+						return nil, err
 					}
 
-					tokens = append(tokens, TokenAmount{
-						PolicyID: policyID,
-						Name:     name,
-						Amount:   value,
-					})
+					tokens = append(tokens, token)
 				}
 			}
 		}
 
-		retVal[i] = Utxo{
-			Hash:   utxo.Transaction.ID,
-			Index:  utxo.Index,
-			Amount: adaValue,
-			Tokens: tokens,
+		inlineDatum, err := decodeInlineDatumHex(utxo.Datum)
+		if err != nil {
+			return nil, err
+		}
+
+		retVal[i], err = NewUtxo(utxo.Transaction.ID, utxo.Index, adaValue, tokens...)
+		if err != nil {
+			return nil, err
+		}
+
+		retVal[i].InlineDatum = inlineDatum
+
+		if inlineDatum == nil {
+			retVal[i].DatumHash = utxo.DatumHash
+		}
+
+		if utxo.Script != nil {
+			retVal[i].ReferenceScript, err = newReferenceScriptFromOgmios(*utxo.Script)
+			if err != nil {
+				return nil, fmt.Errorf("reference script of %s#%d: %w", utxo.Transaction.ID, utxo.Index, err)
+			}
 		}
 	}
 
 	return retVal, nil
 }
 
+// GetRewardsBalance implements IRewardsRetriever.
+func (o *TxProviderOgmios) GetRewardsBalance(ctx context.Context, rewardAddress string) (uint64, error) {
+	_, stake, err := getRewardAddressStake(rewardAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	params := ogmiosQueryRewardAccountSummariesParams{}
+	if stake.IsScript {
+		params.Scripts = []string{stake.String()}
+	} else {
+		params.Keys = []string{stake.String()}
+	}
+
+	response, err := executeHTTPOgmios[ogmiosQueryRewardAccountSummariesResponse](
+		ctx, o.url, ogmiosQueryRewardAccountSummariesRequest{
+			Jsonrpc: ogmiosJSONRPCVersion,
+			Method:  "queryLedgerState/rewardAccountSummaries",
+			Params:  params,
+		}, false,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return response.getRewards()
+}
+
 // Expects TxCborString
 func (o *TxProviderOgmios) SubmitTx(ctx context.Context, txSigned []byte) error {
 	response, err := executeHTTPOgmios[ogmiosSubmitTransactionResponse](
@@ -203,6 +280,30 @@ func (o *TxProviderOgmios) SubmitTx(ctx context.Context, txSigned []byte) error
 	return nil
 }
 
+// EvaluateTx implements ITxEvaluator.
+func (o *TxProviderOgmios) EvaluateTx(ctx context.Context, txRaw []byte) (map[RedeemerPointer]ExUnits, error) {
+	response, err := executeHTTPOgmios[ogmiosEvaluateTransactionResponse](
+		ctx, o.url, ogmiosEvaluateTransaction{
+			Jsonrpc: ogmiosJSONRPCVersion,
+			Method:  "evaluateTransaction",
+			Params: ogmiosSubmitTransactionParams{
+				Transaction: ogmiosSubmitTransactionParamsTransaction{
+					CBOR: hex.EncodeToString(txRaw),
+				},
+			},
+		}, false,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error.Message != "" {
+		return nil, fmt.Errorf("ogmios evaluate tx error: %s: %s", response.Error.Message, string(response.Error.Data))
+	}
+
+	return response.Result.toExUnits()
+}
+
 func (o *TxProviderOgmios) GetTxByHash(ctx context.Context, hash string) (map[string]interface{}, error) {
 	panic("not implemented") //nolint:gocritic
 }
@@ -254,3 +355,27 @@ func getErrorFromResponseOgmios(resp *http.Response) error {
 
 	return fmt.Errorf("status code %d: %s", resp.StatusCode, msg)
 }
+
+func newReferenceScriptFromOgmios(script ogmiosScript) (*ReferenceScript, error) {
+	var scriptType uint64
+
+	switch script.Language {
+	case "native":
+		scriptType = scriptRefNativeType
+	case "plutus:v1":
+		scriptType = uint64(PlutusV1)
+	case "plutus:v2":
+		scriptType = uint64(PlutusV2)
+	case "plutus:v3":
+		scriptType = uint64(PlutusV3)
+	default:
+		return nil, fmt.Errorf("unsupported script language: %s", script.Language)
+	}
+
+	scriptBytes, err := hex.DecodeString(script.Cbor)
+	if err != nil {
+		return nil, err
+	}
+
+	return newReferenceScriptFromBytes(scriptType, scriptBytes, "")
+}
@@ -1,5 +1,7 @@
 package core
 
+import "encoding/json"
+
 type ogmiosQueryStateRequest struct {
 	Jsonrpc string      `json:"jsonrpc"`
 	Method  string      `json:"method"`
@@ -24,13 +26,22 @@ type ogmiosQueryUtxoResponse struct {
 		Transaction struct {
 			ID string `json:"id"`
 		} `json:"transaction"`
-		Index   uint32                       `json:"index"`
-		Address string                       `json:"address"`
-		Value   map[string]map[string]uint64 `json:"value"`
+		Index     uint32                       `json:"index"`
+		Address   string                       `json:"address"`
+		Value     map[string]map[string]uint64 `json:"value"`
+		DatumHash string                       `json:"datumHash"`
+		Datum     string                       `json:"datum"` // inline datum cbor
+		Script    *ogmiosScript                `json:"script"`
 	} `json:"result"`
 	ID interface{} `json:"id"`
 }
 
+// ogmiosScript is reference script of the utxo. Language is native, plutus:v1, plutus:v2 or plutus:v3
+type ogmiosScript struct {
+	Language string `json:"language"`
+	Cbor     string `json:"cbor"`
+}
+
 type ogmiosQueryProtocolParamsResponse struct {
 	Jsonrpc string `json:"jsonrpc"`
 	Method  string `json:"method"`
@@ -92,9 +103,49 @@ type ogmiosQueryProtocolParamsResponse struct {
 		MaxValueSize struct {
 			Bytes uint64 `json:"bytes"`
 		} `json:"maxValueSize"`
-		CollateralPercentage uint64 `json:"collateralPercentage"`
-		MaxCollateralInputs  uint64 `json:"maxCollateralInputs"`
-		Version              struct {
+		MinFeeReferenceScripts struct {
+			Range      uint64  `json:"range"`
+			Base       float64 `json:"base"`
+			Multiplier float64 `json:"multiplier"`
+		} `json:"minFeeReferenceScripts"`
+		CollateralPercentage          uint64 `json:"collateralPercentage"`
+		MaxCollateralInputs           uint64 `json:"maxCollateralInputs"`
+		DelegateRepresentativeDeposit struct {
+			Ada struct {
+				Lovelace uint64 `json:"lovelace"`
+			} `json:"ada"`
+		} `json:"delegateRepresentativeDeposit"`
+		DelegateRepresentativeMaxIdleTime      uint64 `json:"delegateRepresentativeMaxIdleTime"`
+		DelegateRepresentativeVotingThresholds struct {
+			NoConfidence             string                                  `json:"noConfidence"`
+			ConstitutionalCommittee  ogmiosConstitutionalCommitteeThresholds `json:"constitutionalCommittee"`
+			Constitution             string                                  `json:"constitution"`
+			HardForkInitiation       string                                  `json:"hardForkInitiation"`
+			ProtocolParametersUpdate struct {
+				Network    string `json:"network"`
+				Economic   string `json:"economic"`
+				Technical  string `json:"technical"`
+				Governance string `json:"governance"`
+			} `json:"protocolParametersUpdate"`
+			TreasuryWithdrawals string `json:"treasuryWithdrawals"`
+		} `json:"delegateRepresentativeVotingThresholds"`
+		StakePoolVotingThresholds struct {
+			NoConfidence             string                                  `json:"noConfidence"`
+			ConstitutionalCommittee  ogmiosConstitutionalCommitteeThresholds `json:"constitutionalCommittee"`
+			HardForkInitiation       string                                  `json:"hardForkInitiation"`
+			ProtocolParametersUpdate struct {
+				Security string `json:"security"`
+			} `json:"protocolParametersUpdate"`
+		} `json:"stakePoolVotingThresholds"`
+		GovernanceActionDeposit struct {
+			Ada struct {
+				Lovelace uint64 `json:"lovelace"`
+			} `json:"ada"`
+		} `json:"governanceActionDeposit"`
+		GovernanceActionLifetime             uint64 `json:"governanceActionLifetime"`
+		ConstitutionalCommitteeMinSize       uint64 `json:"constitutionalCommitteeMinSize"`
+		ConstitutionalCommitteeMaxTermLength uint64 `json:"constitutionalCommitteeMaxTermLength"`
+		Version                              struct {
 			Major uint64 `json:"major"`
 			Minor uint64 `json:"minor"`
 		} `json:"version"`
@@ -102,6 +153,11 @@ type ogmiosQueryProtocolParamsResponse struct {
 	ID interface{} `json:"id"`
 }
 
+type ogmiosConstitutionalCommitteeThresholds struct {
+	Default             string `json:"default"`
+	StateOfNoConfidence string `json:"stateOfNoConfidence"`
+}
+
 type ogmiosQueryTipResponse struct {
 	Jsonrpc string `json:"jsonrpc"`
 	Method  string `json:"method"`
@@ -152,3 +208,105 @@ type ogmiosQueryNetworkBlockHeightResponse struct {
 	Result  uint64      `json:"result"`
 	ID      interface{} `json:"id"`
 }
+
+type ogmiosEvaluateTransaction struct {
+	Jsonrpc string                        `json:"jsonrpc"`
+	Method  string                        `json:"method"`
+	Params  ogmiosSubmitTransactionParams `json:"params"`
+	ID      interface{}                   `json:"id"`
+}
+
+type ogmiosEvaluateTransactionResult []struct {
+	Validator struct {
+		Purpose string `json:"purpose"`
+		Index   uint32 `json:"index"`
+	} `json:"validator"`
+	Budget struct {
+		Memory uint64 `json:"memory"`
+		CPU    uint64 `json:"cpu"`
+	} `json:"budget"`
+}
+
+func (r ogmiosEvaluateTransactionResult) toExUnits() (map[RedeemerPointer]ExUnits, error) {
+	result := make(map[RedeemerPointer]ExUnits, len(r))
+
+	for _, item := range r {
+		pointer, err := newRedeemerPointerFromPurpose(item.Validator.Purpose, item.Validator.Index)
+		if err != nil {
+			return nil, err
+		}
+
+		result[pointer] = NewExUnits(item.Budget.Memory, item.Budget.CPU)
+	}
+
+	return result, nil
+}
+
+type ogmiosEvaluateTransactionResponse struct {
+	Jsonrpc string                          `json:"jsonrpc"`
+	Method  string                          `json:"method"`
+	Result  ogmiosEvaluateTransactionResult `json:"result"`
+	Error   struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	} `json:"error"`
+	ID interface{} `json:"id"`
+}
+
+type ogmiosQueryRewardAccountSummariesParams struct {
+	Keys    []string `json:"keys,omitempty"`
+	Scripts []string `json:"scripts,omitempty"`
+}
+
+type ogmiosQueryRewardAccountSummariesRequest struct {
+	Jsonrpc string                                  `json:"jsonrpc"`
+	Method  string                                  `json:"method"`
+	Params  ogmiosQueryRewardAccountSummariesParams `json:"params"`
+	ID      interface{}                             `json:"id"`
+}
+
+type ogmiosRewardAccountSummary struct {
+	Rewards struct {
+		Ada struct {
+			Lovelace uint64 `json:"lovelace"`
+		} `json:"ada"`
+	} `json:"rewards"`
+}
+
+type ogmiosQueryRewardAccountSummariesResponse struct {
+	Jsonrpc string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	// object keyed by the credential (older ogmios versions) or array of summaries
+	Result json.RawMessage `json:"result"`
+	ID     interface{}     `json:"id"`
+}
+
+func (r ogmiosQueryRewardAccountSummariesResponse) getRewards() (uint64, error) {
+	var summaries []ogmiosRewardAccountSummary
+
+	if len(r.Result) == 0 || string(r.Result) == "null" {
+		return 0, nil
+	} else if r.Result[0] == '[' {
+		if err := json.Unmarshal(r.Result, &summaries); err != nil {
+			return 0, err
+		}
+	} else {
+		var summariesMap map[string]ogmiosRewardAccountSummary
+
+		if err := json.Unmarshal(r.Result, &summariesMap); err != nil {
+			return 0, err
+		}
+
+		for _, summary := range summariesMap {
+			summaries = append(summaries, summary)
+		}
+	}
+
+	rewards := uint64(0)
+	for _, summary := range summaries {
+		rewards += summary.Rewards.Ada.Lovelace
+	}
+
+	return rewards, nil
+}
@@ -0,0 +1,614 @@
+package core
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxProviders_GetUtxosAssetNames(t *testing.T) {
+	t.Parallel()
+
+	const (
+		txHash   = "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"
+		policyID = "29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8"
+		cip68Hex = "000de1404e616d69" // (222) label + Nami, not valid utf-8
+		likeHex  = "63616665"         // cafe
+	)
+
+	expectedTokens := []TokenAmount{
+		NewTokenAmount(policyID, "", 7),
+		NewTokenAmount(policyID, "\x00\x0d\xe1\x40Nami", 1),
+		NewTokenAmount(policyID, "cafe", 20),
+	}
+
+	checkUtxos := func(t *testing.T, utxos []Utxo) {
+		t.Helper()
+
+		require.Len(t, utxos, 1)
+		require.Equal(t, txHash, utxos[0].Hash)
+		require.Equal(t, uint32(1), utxos[0].Index)
+		require.Equal(t, uint64(5_000_000), utxos[0].Amount)
+		value, err := utxos[0].GetValue()
+		require.NoError(t, err)
+		require.Equal(t, expectedTokens, value.GetTokens())
+		require.Equal(t, []string{policyID + ".", policyID + "." + cip68Hex, policyID + "." + likeHex}, []string{
+			expectedTokens[0].TokenName(), expectedTokens[1].TokenName(), expectedTokens[2].TokenName(),
+		})
+
+		// json round trip
+		bytes, err := json.Marshal(utxos)
+		require.NoError(t, err)
+
+		var jsonUtxos []Utxo
+
+		require.NoError(t, json.Unmarshal(bytes, &jsonUtxos))
+		require.Equal(t, utxos, jsonUtxos)
+
+		// cbor round trip
+		bytes, err = cbor.Marshal(value)
+		require.NoError(t, err)
+
+		var cborValue Value
+
+		require.NoError(t, cbor.Unmarshal(bytes, &cborValue))
+		require.Equal(t, expectedTokens, cborValue.GetTokens())
+	}
+
+	t.Run("ogmios", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","method":"queryLedgerState/utxo","result":[{
+				"transaction":{"id":"%s"},"index":1,"address":"addr",
+				"value":{"ada":{"lovelace":5000000},"%s":{"":7,"%s":1,"%s":20}}}]}`,
+				txHash, policyID, cip68Hex, likeHex)
+		}))
+		defer server.Close()
+
+		utxos, err := NewTxProviderOgmios(server.URL).GetUtxos(context.Background(), "addr")
+		require.NoError(t, err)
+
+		checkUtxos(t, utxos)
+	})
+
+	t.Run("blockfrost", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, `[{"address":"addr","tx_hash":"%s","tx_index":1,"output_index":1,"amount":[
+				{"unit":"lovelace","quantity":"5000000"},
+				{"unit":"%s","quantity":"7"},
+				{"unit":"%s%s","quantity":"1"},
+				{"unit":"%s%s","quantity":"20"}]}]`,
+				txHash, policyID, policyID, cip68Hex, policyID, likeHex)
+		}))
+		defer server.Close()
+
+		utxos, err := NewTxProviderBlockFrost(server.URL, "").GetUtxos(context.Background(), "addr")
+		require.NoError(t, err)
+
+		checkUtxos(t, utxos)
+	})
+
+	t.Run("cli", func(t *testing.T) {
+		t.Parallel()
+
+		output := fmt.Sprintf(`{"%s#1": {"address": "addr", "datum": null, "value": {
+			"lovelace": 5000000, "%s": {"": 7, "%s": 1, "%s": 20}}}}`,
+			txHash, policyID, cip68Hex, likeHex)
+
+		utxos, err := parseUtxosCliOutput(output)
+		require.NoError(t, err)
+
+		checkUtxos(t, utxos)
+	})
+
+	t.Run("invalid hex name", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, `[{"tx_hash":"%s","tx_index":1,"amount":[{"unit":"%sxyz","quantity":"1"}]}]`,
+				txHash, policyID)
+		}))
+		defer server.Close()
+
+		_, err := NewTxProviderBlockFrost(server.URL, "").GetUtxos(context.Background(), "addr")
+		require.ErrorContains(t, err, "invalid hex asset name")
+	})
+}
+
+func TestTxProviders_EvaluateTx(t *testing.T) {
+	t.Parallel()
+
+	txRaw := []byte{0x84, 0xa0, 0xa0, 0xf5, 0xf6}
+	expected := map[RedeemerPointer]ExUnits{
+		NewRedeemerPointer(RedeemerTagSpend, 1): NewExUnits(1700, 476468),
+		NewRedeemerPointer(RedeemerTagMint, 0):  NewExUnits(10, 20),
+	}
+
+	t.Run("ogmios", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var request ogmiosEvaluateTransaction
+
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil ||
+				request.Method != "evaluateTransaction" || request.Params.Transaction.CBOR != hex.EncodeToString(txRaw) {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":{"code":-32602,"message":"invalid request"}}`)
+
+				return
+			}
+
+			fmt.Fprint(w, `{"jsonrpc":"2.0","method":"evaluateTransaction","result":[
+				{"validator":{"purpose":"spend","index":1},"budget":{"memory":1700,"cpu":476468}},
+				{"validator":{"purpose":"mint","index":0},"budget":{"memory":10,"cpu":20}}]}`)
+		}))
+		defer server.Close()
+
+		exUnits, err := NewTxProviderOgmios(server.URL).EvaluateTx(context.Background(), txRaw)
+		require.NoError(t, err)
+		require.Equal(t, expected, exUnits)
+
+		_, err = NewTxProviderOgmios(server.URL).EvaluateTx(context.Background(), []byte{0x80})
+		require.ErrorContains(t, err, "invalid request")
+	})
+
+	t.Run("ogmios script failure", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"jsonrpc":"2.0","method":"evaluateTransaction","error":{
+				"code":3010,"message":"Some scripts of the transactions terminated with error(s).",
+				"data":[{"validator":{"purpose":"spend","index":1},"error":{"code":3012}}]}}`)
+		}))
+		defer server.Close()
+
+		_, err := NewTxProviderOgmios(server.URL).EvaluateTx(context.Background(), txRaw)
+		require.ErrorContains(t, err, "terminated with error")
+	})
+
+	t.Run("blockfrost", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			switch {
+			case r.URL.Path != "/utils/txs/evaluate" || r.Header.Get("project_id") != "id":
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"status_code":403,"message":"Invalid project token."}`)
+			case string(body) != hex.EncodeToString(txRaw):
+				fmt.Fprint(w, `{"type":"jsonwsp/response","result":{"EvaluationFailure":{"ScriptFailures":{}}}}`)
+			default:
+				fmt.Fprint(w, `{"type":"jsonwsp/response","methodname":"EvaluateTx","result":{"EvaluationResult":{
+					"spend:1":{"memory":1700,"steps":476468},"mint:0":{"memory":10,"steps":20}}}}`)
+			}
+		}))
+		defer server.Close()
+
+		exUnits, err := NewTxProviderBlockFrost(server.URL, "id").EvaluateTx(context.Background(), txRaw)
+		require.NoError(t, err)
+		require.Equal(t, expected, exUnits)
+
+		_, err = NewTxProviderBlockFrost(server.URL, "id").EvaluateTx(context.Background(), []byte{0x80})
+		require.ErrorContains(t, err, "ScriptFailures")
+
+		_, err = NewTxProviderBlockFrost(server.URL, "other").EvaluateTx(context.Background(), txRaw)
+		require.ErrorContains(t, err, "Invalid project token")
+	})
+}
+
+func TestTxProviders_GetUtxosDatums(t *testing.T) {
+	t.Parallel()
+
+	const (
+		txHash    = "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"
+		datumHash = "923918e403bf43c34b4ef6b48eb2ee04babed17320d8d1b9ff9ad086e86f44ec"
+		datumHex  = "d8799f4101ff" // Constr 0 [#01]
+		// blake2b-256 of datumHex
+		inlineDatumHash = "7263312986f37ee243c75262c478a177f828e108573d350e5ee166a05a136d32"
+	)
+
+	checkUtxos := func(t *testing.T, utxos []Utxo) {
+		t.Helper()
+
+		require.Len(t, utxos, 3)
+		require.Equal(t, datumHash, utxos[0].DatumHash)
+		require.Nil(t, utxos[0].InlineDatum)
+		require.Equal(t, "", utxos[1].DatumHash)
+		require.Equal(t, datumHex, hex.EncodeToString(utxos[1].InlineDatum))
+		require.Equal(t, Utxo{Hash: txHash, Index: 2, Value: Value{Coin: 3}, Amount: 3}, utxos[2])
+	}
+
+	t.Run("ogmios", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","method":"queryLedgerState/utxo","result":[
+				{"transaction":{"id":"%[1]s"},"index":0,"value":{"ada":{"lovelace":1}},"datumHash":"%[2]s"},
+				{"transaction":{"id":"%[1]s"},"index":1,"value":{"ada":{"lovelace":2}},"datum":"%[3]s"},
+				{"transaction":{"id":"%[1]s"},"index":2,"value":{"ada":{"lovelace":3}}}]}`,
+				txHash, datumHash, datumHex)
+		}))
+		defer server.Close()
+
+		utxos, err := NewTxProviderOgmios(server.URL).GetUtxos(context.Background(), "addr")
+		require.NoError(t, err)
+
+		checkUtxos(t, utxos)
+	})
+
+	t.Run("blockfrost", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, `[
+				{"tx_hash":"%[1]s","tx_index":0,"amount":[{"unit":"lovelace","quantity":"1"}],
+					"data_hash":"%[2]s","inline_datum":null},
+				{"tx_hash":"%[1]s","tx_index":1,"amount":[{"unit":"lovelace","quantity":"2"}],
+					"data_hash":"%[2]s","inline_datum":"%[3]s"},
+				{"tx_hash":"%[1]s","tx_index":2,"amount":[{"unit":"lovelace","quantity":"3"}],
+					"data_hash":null,"inline_datum":null}]`,
+				txHash, datumHash, datumHex)
+		}))
+		defer server.Close()
+
+		utxos, err := NewTxProviderBlockFrost(server.URL, "").GetUtxos(context.Background(), "addr")
+		require.NoError(t, err)
+
+		checkUtxos(t, utxos)
+	})
+
+	t.Run("cli", func(t *testing.T) {
+		t.Parallel()
+
+		output := fmt.Sprintf(`{
+			"%[1]s#2": {"value": {"lovelace": 3}, "datum": null},
+			"%[1]s#0": {"value": {"lovelace": 1}, "datumhash": "%[2]s"},
+			"%[1]s#1": {"value": {"lovelace": 2}, "inlineDatumhash": "%[4]s", "inlineDatumRaw": "%[3]s",
+				"inlineDatum": {"constructor": 0, "fields": [{"bytes": "01"}]}}}`,
+			txHash, datumHash, datumHex, inlineDatumHash)
+
+		utxos, err := parseUtxosCliOutput(output)
+		require.NoError(t, err)
+
+		checkUtxos(t, utxos)
+
+		// cardano-cli writes json output to the --out-file
+		outputPath := filepath.Join(t.TempDir(), "utxos.json")
+		require.NoError(t, os.WriteFile(outputPath, []byte(output), FilePermission))
+
+		cliBinary := filepath.Join(t.TempDir(), "cardano-cli")
+		require.NoError(t, os.WriteFile(cliBinary, []byte(`#!/bin/sh
+while [ $# -gt 0 ]; do
+	if [ "$1" = "--out-file" ]; then cp "`+outputPath+`" "$2"; fi
+	shift
+done
+`), 0o700))
+
+		provider, err := NewTxProviderCli(0, "socket", cliBinary)
+		require.NoError(t, err)
+
+		defer provider.Dispose()
+
+		utxos, err = provider.GetUtxos(context.Background(), "addr")
+		require.NoError(t, err)
+
+		checkUtxos(t, utxos)
+
+		// older cli versions without raw inline datum
+		output = strings.ReplaceAll(output, `"inlineDatumRaw": "`+datumHex+`",`, "")
+
+		utxos, err = parseUtxosCliOutput(output)
+		require.NoError(t, err)
+
+		checkUtxos(t, utxos)
+
+		// re-encoded datum is not byte exact or it can not be checked
+		_, err = parseUtxosCliOutput(strings.ReplaceAll(output, `"inlineDatumhash": "`+inlineDatumHash+`"`,
+			`"inlineDatumhash": "`+strings.Repeat("00", 32)+`"`))
+		require.ErrorContains(t, err, "does not match")
+
+		_, err = parseUtxosCliOutput(strings.ReplaceAll(output, `"inlineDatumhash": "`+inlineDatumHash+`",`, ""))
+		require.ErrorContains(t, err, "raw inline datum is not available")
+	})
+}
+
+func TestTxProviders_GetUtxosReferenceScripts(t *testing.T) {
+	t.Parallel()
+
+	const (
+		txHash  = "e99a5bde15aa05f24fcc04b7eabc1520d3397283b1ee720de9fe2653abbb0c9f"
+		keyHash = "29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8"
+		// always succeeds script as it is in the witness set and wrapped once more as in the text envelope
+		plutusHex         = "4d01000033222220051200120011"
+		plutusEnvelopeHex = "4e" + plutusHex
+	)
+
+	nativeScript := NewSigPolicyScript(keyHash)
+
+	nativeCbor, err := nativeScript.MarshalCBOR()
+	require.NoError(t, err)
+
+	nativeHash, err := nativeScript.GetPolicyID()
+	require.NoError(t, err)
+
+	plutusBytes, err := hex.DecodeString(plutusHex)
+	require.NoError(t, err)
+
+	plutusScript := NewPlutusScript(PlutusV2, plutusBytes)
+
+	plutusHash, err := plutusScript.GetHash()
+	require.NoError(t, err)
+
+	checkUtxos := func(t *testing.T, utxos []Utxo) {
+		t.Helper()
+
+		require.Len(t, utxos, 3)
+		require.Equal(t, NewNativeReferenceScript(nativeScript), utxos[0].ReferenceScript)
+		require.Equal(t, NewPlutusReferenceScript(plutusScript), utxos[1].ReferenceScript)
+		require.Nil(t, utxos[2].ReferenceScript)
+	}
+
+	t.Run("ogmios", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","method":"queryLedgerState/utxo","result":[
+				{"transaction":{"id":"%[1]s"},"index":0,"value":{"ada":{"lovelace":1}},
+					"script":{"language":"native","json":{"clause":"signature","from":"%[2]s"},"cbor":"%[3]s"}},
+				{"transaction":{"id":"%[1]s"},"index":1,"value":{"ada":{"lovelace":2}},
+					"script":{"language":"plutus:v2","cbor":"%[4]s"}},
+				{"transaction":{"id":"%[1]s"},"index":2,"value":{"ada":{"lovelace":3}}}]}`,
+				txHash, keyHash, hex.EncodeToString(nativeCbor), plutusHex)
+		}))
+		defer server.Close()
+
+		utxos, err := NewTxProviderOgmios(server.URL).GetUtxos(context.Background(), "addr")
+		require.NoError(t, err)
+
+		checkUtxos(t, utxos)
+	})
+
+	t.Run("blockfrost", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/addresses/addr/utxos":
+				fmt.Fprintf(w, `[
+					{"tx_hash":"%[1]s","tx_index":0,"amount":[{"unit":"lovelace","quantity":"1"}],
+						"reference_script_hash":"%[2]s"},
+					{"tx_hash":"%[1]s","tx_index":1,"amount":[{"unit":"lovelace","quantity":"2"}],
+						"reference_script_hash":"%[3]s"},
+					{"tx_hash":"%[1]s","tx_index":2,"amount":[{"unit":"lovelace","quantity":"3"}],
+						"reference_script_hash":null}]`,
+					txHash, nativeHash, plutusHash)
+			case "/scripts/" + nativeHash:
+				fmt.Fprintf(w, `{"script_hash":"%s","type":"timelock","serialised_size":null}`, nativeHash)
+			case "/scripts/" + nativeHash + "/json":
+				fmt.Fprintf(w, `{"json":{"type":"sig","keyHash":"%s"}}`, keyHash)
+			case "/scripts/" + plutusHash:
+				fmt.Fprintf(w, `{"script_hash":"%s","type":"plutusV2","serialised_size":14}`, plutusHash)
+			case "/scripts/" + plutusHash + "/cbor":
+				fmt.Fprintf(w, `{"cbor":"%s"}`, plutusEnvelopeHex)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"status_code":404,"message":"The requested component has not been found."}`)
+			}
+		}))
+		defer server.Close()
+
+		utxos, err := NewTxProviderBlockFrost(server.URL, "").GetUtxos(context.Background(), "addr")
+		require.NoError(t, err)
+
+		checkUtxos(t, utxos)
+	})
+
+	t.Run("cli", func(t *testing.T) {
+		t.Parallel()
+
+		output := fmt.Sprintf(`{
+			"%[1]s#0": {"value": {"lovelace": 1}, "referenceScript": {"scriptLanguage": "SimpleScriptLanguage",
+				"script": {"type": "SimpleScript", "description": "", "cborHex": "%[2]s"}}},
+			"%[1]s#1": {"value": {"lovelace": 2}, "referenceScript": {"scriptLanguage": "PlutusScriptLanguage PlutusScriptV2",
+				"script": {"type": "PlutusScriptV2", "description": "", "cborHex": "%[3]s"}}},
+			"%[1]s#2": {"value": {"lovelace": 3}, "referenceScript": null}}`,
+			txHash, hex.EncodeToString(nativeCbor), plutusEnvelopeHex)
+
+		utxos, err := parseUtxosCliOutput(output)
+		require.NoError(t, err)
+
+		checkUtxos(t, utxos)
+	})
+}
+
+func TestTxProviders_GetRewardsBalance(t *testing.T) {
+	t.Parallel()
+
+	wallet, err := GenerateWallet(true)
+	require.NoError(t, err)
+
+	rewardAddr, err := NewRewardAddress(TestNetNetwork, wallet.StakeVerificationKey)
+	require.NoError(t, err)
+
+	stakeCredential, err := NewStakeCredentialFromKey(wallet.StakeVerificationKey)
+	require.NoError(t, err)
+
+	t.Run("ogmios", func(t *testing.T) {
+		t.Parallel()
+
+		for _, result := range []string{
+			fmt.Sprintf(`{"%s":{"delegate":{"id":"pool1"},"rewards":{"ada":{"lovelace":1500000}}}}`, stakeCredential),
+			`[{"delegate":{"id":"pool1"},"rewards":{"ada":{"lovelace":1500000}}}]`,
+		} {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Contains(t, string(body), "queryLedgerState/rewardAccountSummaries")
+				require.Contains(t, string(body), `"keys":["`+stakeCredential.String()+`"]`)
+
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","method":"queryLedgerState/rewardAccountSummaries","result":%s}`, result)
+			}))
+
+			rewards, err := NewTxProviderOgmios(server.URL).GetRewardsBalance(context.Background(), rewardAddr.String())
+
+			server.Close()
+
+			require.NoError(t, err)
+			require.Equal(t, uint64(1_500_000), rewards)
+		}
+
+		_, err := NewTxProviderOgmios("").GetRewardsBalance(
+			context.Background(), "addr_test1vqjysa7p4mhu0l25qknwznvj0kghtr29ud7zp732ezwtzec0w8g3u")
+		require.ErrorContains(t, err, "is not reward address")
+	})
+
+	t.Run("blockfrost", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/accounts/"+rewardAddr.String() {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			fmt.Fprint(w, `{"stake_address":"stake","active":true,"withdrawable_amount":"2500000"}`)
+		}))
+		t.Cleanup(server.Close)
+
+		provider := NewTxProviderBlockFrost(server.URL, "")
+
+		rewards, err := provider.GetRewardsBalance(context.Background(), rewardAddr.String())
+		require.NoError(t, err)
+		require.Equal(t, uint64(2_500_000), rewards)
+
+		rewards, err = provider.GetRewardsBalance(context.Background(), "stake_test_unknown")
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), rewards)
+	})
+
+	t.Run("cli", func(t *testing.T) {
+		t.Parallel()
+
+		rewards, err := parseStakeAddressInfoCliOutput(`[{"address":"stake_test1","delegation":"pool1",
+			"rewardAccountBalance":3500000,"delegationDeposit":2000000}]`)
+		require.NoError(t, err)
+		require.Equal(t, uint64(3_500_000), rewards)
+
+		rewards, err = parseStakeAddressInfoCliOutput(`[]`)
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), rewards)
+	})
+}
+
+func TestTxProviders_GetProtocolParametersGovernance(t *testing.T) {
+	t.Parallel()
+
+	expectedDRepThresholds := ProtocolParametersDRepVotingThresholds{
+		MotionNoConfidence:    0.67,
+		CommitteeNormal:       0.67,
+		CommitteeNoConfidence: 0.6,
+		UpdateToConstitution:  0.75,
+		HardForkInitiation:    0.6,
+		PPNetworkGroup:        0.67,
+		PPEconomicGroup:       0.67,
+		PPTechnicalGroup:      0.67,
+		PPGovGroup:            0.75,
+		TreasuryWithdrawal:    0.67,
+	}
+	expectedPoolThresholds := ProtocolParametersPoolVotingThresholds{
+		MotionNoConfidence:    0.51,
+		CommitteeNormal:       0.51,
+		CommitteeNoConfidence: 0.51,
+		HardForkInitiation:    0.51,
+		PPSecurityGroup:       0.51,
+	}
+
+	checkProtocolParameters := func(t *testing.T, ppBytes []byte) {
+		t.Helper()
+
+		var pp ProtocolParameters
+
+		require.NoError(t, json.Unmarshal(ppBytes, &pp))
+		require.Equal(t, uint64(500_000_000), pp.DRepDeposit)
+		require.Equal(t, uint64(20), pp.DRepActivity)
+		require.Equal(t, uint64(100_000_000_000), pp.GovActionDeposit)
+		require.Equal(t, uint64(6), pp.GovActionLifetime)
+		require.Equal(t, uint64(7), pp.CommitteeMinSize)
+		require.Equal(t, uint64(146), pp.CommitteeMaxTermLength)
+		require.Equal(t, expectedDRepThresholds, pp.DRepVotingThresholds)
+		require.Equal(t, expectedPoolThresholds, pp.PoolVotingThresholds)
+	}
+
+	t.Run("ogmios", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"jsonrpc":"2.0","method":"queryLedgerState/protocolParameters","result":{
+				"delegateRepresentativeDeposit":{"ada":{"lovelace":500000000}},
+				"delegateRepresentativeMaxIdleTime":20,
+				"delegateRepresentativeVotingThresholds":{
+					"noConfidence":"67/100",
+					"constitutionalCommittee":{"default":"67/100","stateOfNoConfidence":"3/5"},
+					"constitution":"3/4","hardForkInitiation":"3/5",
+					"protocolParametersUpdate":{"network":"67/100","economic":"67/100","technical":"67/100","governance":"3/4"},
+					"treasuryWithdrawals":"67/100"},
+				"stakePoolVotingThresholds":{
+					"noConfidence":"51/100",
+					"constitutionalCommittee":{"default":"51/100","stateOfNoConfidence":"51/100"},
+					"hardForkInitiation":"51/100",
+					"protocolParametersUpdate":{"security":"51/100"}},
+				"governanceActionDeposit":{"ada":{"lovelace":100000000000}},
+				"governanceActionLifetime":6,
+				"constitutionalCommitteeMinSize":7,
+				"constitutionalCommitteeMaxTermLength":146,
+				"minFeeReferenceScripts":{"range":25600,"base":15,"multiplier":1.2}}}`)
+		}))
+		t.Cleanup(server.Close)
+
+		ppBytes, err := NewTxProviderOgmios(server.URL).GetProtocolParameters(context.Background())
+		require.NoError(t, err)
+
+		checkProtocolParameters(t, ppBytes)
+
+		var pp ProtocolParameters
+
+		require.NoError(t, json.Unmarshal(ppBytes, &pp))
+		require.Equal(t, float64(15), pp.MinFeeRefScriptCostPerByte)
+		require.Equal(t, uint64(25_600), pp.MinFeeRefScriptRange)
+		require.Equal(t, 1.2, pp.MinFeeRefScriptMultiplier)
+	})
+
+	t.Run("blockfrost", func(t *testing.T) {
+		t.Parallel()
+
+		ppBytes, err := convertProtocolParameters([]byte(`{
+			"drep_deposit":"500000000","drep_activity":"20",
+			"gov_action_deposit":"100000000000","gov_action_lifetime":"6",
+			"committee_min_size":"7","committee_max_term_length":"146",
+			"dvt_motion_no_confidence":0.67,"dvt_committee_normal":0.67,"dvt_committee_no_confidence":0.6,
+			"dvt_update_to_constitution":0.75,"dvt_hard_fork_initiation":0.6,"dvt_p_p_network_group":0.67,
+			"dvt_p_p_economic_group":0.67,"dvt_p_p_technical_group":0.67,"dvt_p_p_gov_group":0.75,
+			"dvt_treasury_withdrawal":0.67,
+			"pvt_motion_no_confidence":0.51,"pvt_committee_normal":0.51,"pvt_committee_no_confidence":0.51,
+			"pvt_hard_fork_initiation":0.51,"pvtpp_security_group":0.51}`))
+		require.NoError(t, err)
+
+		checkProtocolParameters(t, ppBytes)
+	})
+}
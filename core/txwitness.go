@@ -38,21 +38,6 @@ func (w TxWitnessRaw) GetSignatureAndVKey() ([]byte, []byte, error) {
 
 type transactionUnwitnessedRaw []byte
 
-func newTransactionUnwitnessedRawFromJSON(bytes []byte) (transactionUnwitnessedRaw, error) {
-	var data map[string]interface{}
-
-	if err := json.Unmarshal(bytes, &data); err != nil {
-		return nil, err
-	}
-
-	// a little hack so we have always correct witness key and description for json
-	// (cardano-cli can return error otherwise)
-	txUnwitnessedJSONType = data["type"].(string)        //nolint:forcetypeassert
-	txUnwitnessedJSONDesc = data["description"].(string) //nolint:forcetypeassert
-
-	return hex.DecodeString(data["cborHex"].(string)) //nolint:forcetypeassert
-}
-
 func (tx transactionUnwitnessedRaw) ToJSON() ([]byte, error) {
 	return json.Marshal(map[string]interface{}{
 		"type":        txUnwitnessedJSONType,
@@ -63,21 +48,6 @@ func (tx transactionUnwitnessedRaw) ToJSON() ([]byte, error) {
 
 type transactionWitnessedRaw []byte
 
-func newTransactionWitnessedRawFromJSON(bytes []byte) (transactionWitnessedRaw, error) {
-	var data map[string]interface{}
-
-	if err := json.Unmarshal(bytes, &data); err != nil {
-		return nil, err
-	}
-
-	// a little hack so we have always correct witness key and description for json
-	// (cardano-cli can return error otherwise)
-	txWitnessedJSONType = data["type"].(string)        //nolint:forcetypeassert
-	txWitnessedJSONDesc = data["description"].(string) //nolint:forcetypeassert
-
-	return hex.DecodeString(data["cborHex"].(string)) //nolint:forcetypeassert
-}
-
 func (tx transactionWitnessedRaw) ToJSON() ([]byte, error) {
 	return json.Marshal(map[string]interface{}{
 		"type":        txWitnessedJSONType,
@@ -2,16 +2,20 @@ package core
 
 import (
 	"context"
+	"fmt"
 )
 
-// GetUtxosSum returns sum for tokens in utxos (including lovelace)
+// GetUtxosSum returns sum for tokens in utxos (including lovelace).
+// Sums are not checked for overflow, use GetUtxosValue for that
 func GetUtxosSum(utxos []Utxo) map[string]uint64 {
 	result := map[string]uint64{}
 
 	for _, utxo := range utxos {
-		result[AdaTokenName] += utxo.Amount
+		amount, tokens := utxo.getAmountAndTokens()
 
-		for _, token := range utxo.Tokens {
+		result[AdaTokenName] += amount
+
+		for _, token := range tokens {
 			result[token.TokenName()] += token.Amount
 		}
 	}
@@ -19,14 +23,34 @@ func GetUtxosSum(utxos []Utxo) map[string]uint64 {
 	return result
 }
 
-// GetOutputsSum returns sum or tokens in outputs (including lovelace)
+// GetUtxosValue returns total value of the utxos or ErrValueOverflow if it does not fit into uint64
+func GetUtxosValue(utxos []Utxo) (result Value, err error) {
+	for _, utxo := range utxos {
+		value, err := utxo.GetValue()
+		if err != nil {
+			return Value{}, fmt.Errorf("utxo %s#%d: %w", utxo.Hash, utxo.Index, err)
+		}
+
+		result, err = result.Add(value)
+		if err != nil {
+			return Value{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// GetOutputsSum returns sum or tokens in outputs (including lovelace).
+// Sums are not checked for overflow, use GetOutputsValue for that
 func GetOutputsSum(outputs []TxOutput) map[string]uint64 {
 	result := map[string]uint64{}
 
 	for _, output := range outputs {
-		result[AdaTokenName] += output.Amount
+		amount, tokens := output.getAmountAndTokens()
 
-		for _, token := range output.Tokens {
+		result[AdaTokenName] += amount
+
+		for _, token := range tokens {
 			result[token.TokenName()] += token.Amount
 		}
 	}
@@ -34,6 +58,23 @@ func GetOutputsSum(outputs []TxOutput) map[string]uint64 {
 	return result
 }
 
+// GetOutputsValue returns total value of the outputs or ErrValueOverflow if it does not fit into uint64
+func GetOutputsValue(outputs []TxOutput) (result Value, err error) {
+	for _, output := range outputs {
+		value, err := output.GetValue()
+		if err != nil {
+			return Value{}, fmt.Errorf("output %s: %w", output.Addr, err)
+		}
+
+		result, err = result.Add(value)
+		if err != nil {
+			return Value{}, err
+		}
+	}
+
+	return result, nil
+}
+
 // IsTxInUtxos checks whether a specified transaction hash (txHash)
 // exists within the UTXOs associated with the given address (addr).
 func IsTxInUtxos(ctx context.Context, utxoRetriever IUTxORetriever, addr string, txHash string) (bool, error) {
@@ -0,0 +1,488 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+var (
+	ErrValueUnderflow = errors.New("value underflow")
+	ErrValueOverflow  = errors.New("value overflow")
+)
+
+// Value is multi-asset value: lovelace (coin) and quantities of native tokens grouped by policy id and asset name.
+// Policy id is hex encoded (stored lowercase) and asset name is raw (not hex encoded) as in TokenAmount
+type Value struct {
+	Coin       uint64
+	MultiAsset map[string]map[string]uint64
+}
+
+// NewValue creates value from lovelace amount and tokens. Tokens with same policy id and name are summed
+// and ErrValueOverflow is returned if their sum does not fit into uint64
+func NewValue(coin uint64, tokens ...TokenAmount) (Value, error) {
+	result := Value{Coin: coin}
+
+	for _, token := range tokens {
+		if err := result.addAsset(token.PolicyID, token.Name, token.Amount); err != nil {
+			return Value{}, err
+		}
+	}
+
+	return result.Normalize(), nil
+}
+
+// NewValueFromSumMap creates value from the map where keys are full token names (see TokenAmount.TokenName)
+// and lovelace is under AdaTokenName
+func NewValueFromSumMap(sum map[string]uint64) (Value, error) {
+	result := Value{Coin: sum[AdaTokenName]}
+
+	for tokenName, amount := range sum {
+		if tokenName == AdaTokenName {
+			continue
+		}
+
+		token, err := NewTokenAmountWithFullName(tokenName, amount, true)
+		if err != nil {
+			return Value{}, err
+		}
+
+		result.setAsset(token.PolicyID, token.Name, token.Amount)
+	}
+
+	return result.Normalize(), nil
+}
+
+// NewUtxo creates utxo with the value of lovelace amount and tokens. Deprecated Amount and Tokens are set too
+func NewUtxo(hash string, index uint32, amount uint64, tokens ...TokenAmount) (Utxo, error) {
+	value, err := NewValue(amount, tokens...)
+	if err != nil {
+		return Utxo{}, fmt.Errorf("utxo %s#%d: %w", hash, index, err)
+	}
+
+	return Utxo{
+		Hash:   hash,
+		Index:  index,
+		Value:  value,
+		Amount: value.Coin,
+		Tokens: value.getLegacyTokens(),
+	}, nil
+}
+
+// GetValue returns value of the utxo: Value if it is set, otherwise value of Amount and Tokens
+func (u Utxo) GetValue() (Value, error) {
+	return getValueOrLegacy(u.Value, u.Amount, u.Tokens)
+}
+
+// GetValue returns value of the output: Value if it is set, otherwise value of Amount and Tokens
+func (o TxOutput) GetValue() (Value, error) {
+	return getValueOrLegacy(o.Value, o.Amount, o.Tokens)
+}
+
+// NewTxOutputWithValue creates output from the value. Deprecated Amount and Tokens are set too
+func NewTxOutputWithValue(addr string, value Value) TxOutput {
+	output := NewTxOutput(addr, value.Coin, value.getLegacyTokens()...)
+	output.Value = value.Normalize()
+
+	return output
+}
+
+// getLegacyTokens returns tokens of the value for deprecated Tokens fields (nil if there are no tokens)
+func (v Value) getLegacyTokens() []TokenAmount {
+	if len(v.MultiAsset) == 0 {
+		return nil
+	}
+
+	return v.GetTokens()
+}
+
+func (u Utxo) getAmountAndTokens() (uint64, []TokenAmount) {
+	if u.Value.IsZero() {
+		return u.Amount, u.Tokens
+	}
+
+	return u.Value.Coin, u.Value.GetTokens()
+}
+
+func (o TxOutput) getAmountAndTokens() (uint64, []TokenAmount) {
+	if o.Value.IsZero() {
+		return o.Amount, o.Tokens
+	}
+
+	return o.Value.Coin, o.Value.GetTokens()
+}
+
+func (o TxOutput) getLovelace() uint64 {
+	amount, _ := o.getAmountAndTokens()
+
+	return amount
+}
+
+// setLovelace sets lovelace of the value (if it is used) and the deprecated amount so they stay in sync
+func (o *TxOutput) setLovelace(amount uint64) {
+	if !o.Value.IsZero() {
+		o.Value.Coin = amount
+	}
+
+	o.Amount = amount
+}
+
+// getValueOrLegacy returns value if it is set, otherwise value of the deprecated amount and tokens.
+// If both are set they must be equal
+func getValueOrLegacy(value Value, amount uint64, tokens []TokenAmount) (Value, error) {
+	legacyValue, err := NewValue(amount, tokens...)
+	if err != nil {
+		return Value{}, err
+	}
+
+	if value.IsZero() {
+		return legacyValue, nil
+	}
+
+	value = value.Normalize()
+
+	if !legacyValue.IsZero() && !legacyValue.Equal(value) {
+		return Value{}, fmt.Errorf("amount and tokens (%s) do not match value (%s)", legacyValue, value)
+	}
+
+	return value, nil
+}
+
+// GetTokens returns native tokens sorted by policy id and asset name
+func (v Value) GetTokens() []TokenAmount {
+	result := []TokenAmount{}
+
+	for policyID, assets := range v.MultiAsset {
+		for name, amount := range assets {
+			if amount > 0 {
+				result = append(result, NewTokenAmount(policyID, name, amount))
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PolicyID != result[j].PolicyID {
+			return result[i].PolicyID < result[j].PolicyID
+		}
+
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// GetAsset returns quantity of the native token. Policy id is case insensitive
+func (v Value) GetAsset(policyID string, name string) uint64 {
+	return v.MultiAsset[strings.ToLower(policyID)][name]
+}
+
+// ToSumMap converts value to the map where keys are full token names (see TokenAmount.TokenName)
+// and lovelace is under AdaTokenName
+func (v Value) ToSumMap() map[string]uint64 {
+	result := map[string]uint64{
+		AdaTokenName: v.Coin,
+	}
+
+	for _, token := range v.GetTokens() {
+		result[token.TokenName()] = token.Amount
+	}
+
+	return result
+}
+
+// Clone returns deep copy of the value. Policy ids are merged as in Normalize
+func (v Value) Clone() Value {
+	result := Value{Coin: v.Coin}
+
+	for policyID, assets := range v.MultiAsset {
+		for name, amount := range assets {
+			result.mergeAsset(policyID, name, amount)
+		}
+	}
+
+	return result
+}
+
+// Normalize returns copy of the value without zero quantities and empty policies.
+// Policy ids which differ only in case are merged (sum larger than math.MaxUint64 is math.MaxUint64)
+func (v Value) Normalize() Value {
+	result := Value{Coin: v.Coin}
+
+	for policyID, assets := range v.MultiAsset {
+		for name, amount := range assets {
+			if amount == 0 {
+				continue
+			}
+
+			result.mergeAsset(policyID, name, amount)
+		}
+	}
+
+	return result
+}
+
+// IsZero returns true if value does not contain neither lovelace nor any token
+func (v Value) IsZero() bool {
+	return v.Coin == 0 && !v.HasTokens()
+}
+
+// HasTokens returns true if value contains at least one token with non zero quantity
+func (v Value) HasTokens() bool {
+	for _, assets := range v.MultiAsset {
+		for _, amount := range assets {
+			if amount > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Add returns sum of two values. ErrValueOverflow is returned if any quantity of the sum does not fit into uint64
+func (v Value) Add(other Value) (Value, error) {
+	coin, carry := bits.Add64(v.Coin, other.Coin, 0)
+	if carry != 0 {
+		return Value{}, fmt.Errorf("%w: %s (first, second) = (%d, %d)",
+			ErrValueOverflow, AdaTokenName, v.Coin, other.Coin)
+	}
+
+	result := v.Clone()
+	result.Coin = coin
+
+	for policyID, assets := range other.MultiAsset {
+		for name, amount := range assets {
+			if err := result.addAsset(policyID, name, amount); err != nil {
+				return Value{}, err
+			}
+		}
+	}
+
+	return result.Normalize(), nil
+}
+
+// Sub returns difference of two values. ErrValueUnderflow is returned if any quantity of other is greater
+func (v Value) Sub(other Value) (Value, error) {
+	if v.Coin < other.Coin {
+		return Value{}, fmt.Errorf("%w: %s (available, required) = (%d, %d)",
+			ErrValueUnderflow, AdaTokenName, v.Coin, other.Coin)
+	}
+
+	result := v.Clone()
+	result.Coin -= other.Coin
+
+	for _, token := range other.GetTokens() {
+		available := result.GetAsset(token.PolicyID, token.Name)
+		if available < token.Amount {
+			return Value{}, fmt.Errorf("%w: %s (available, required) = (%d, %d)",
+				ErrValueUnderflow, token.TokenName(), available, token.Amount)
+		}
+
+		result.setAsset(token.PolicyID, token.Name, available-token.Amount)
+	}
+
+	return result.Normalize(), nil
+}
+
+// Compare compares two values. Values are only partially ordered so second return value is false
+// if some quantities are greater and some are lower. Otherwise result is -1, 0 or 1
+func (v Value) Compare(other Value) (int, bool) {
+	isGreaterOrEqual, isLowerOrEqual := v.GreaterOrEqual(other), other.GreaterOrEqual(v)
+
+	switch {
+	case isGreaterOrEqual && isLowerOrEqual:
+		return 0, true
+	case isGreaterOrEqual:
+		return 1, true
+	case isLowerOrEqual:
+		return -1, true
+	default:
+		return 0, false
+	}
+}
+
+// GreaterOrEqual returns true if lovelace and every token quantity are greater or equal to the ones in other
+func (v Value) GreaterOrEqual(other Value) bool {
+	if v.Coin < other.Coin {
+		return false
+	}
+
+	for policyID, assets := range other.MultiAsset {
+		for name, amount := range assets {
+			if v.GetAsset(policyID, name) < amount {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Equal returns true if both values contain the same quantities
+func (v Value) Equal(other Value) bool {
+	cmp, ok := v.Compare(other)
+
+	return ok && cmp == 0
+}
+
+// String returns value in cardano-cli format: lovelace+amount policyID.hexName+...
+func (v Value) String() string {
+	result := fmt.Sprintf("%d", v.Coin)
+
+	for _, token := range v.GetTokens() {
+		result += "+" + token.String()
+	}
+
+	return result
+}
+
+// MarshalCBOR encodes value as the ledger does: coin or [coin, multiasset]
+func (v Value) MarshalCBOR() ([]byte, error) {
+	tokens := v.GetTokens()
+	if len(tokens) == 0 {
+		return cborEncMode.Marshal(v.Coin)
+	}
+
+	multiAsset := map[cbor.ByteString]map[cbor.ByteString]uint64{}
+
+	for _, token := range tokens {
+		policyID, err := getPolicyIDKey(token.PolicyID)
+		if err != nil {
+			return nil, err
+		}
+
+		if multiAsset[policyID] == nil {
+			multiAsset[policyID] = map[cbor.ByteString]uint64{}
+		}
+
+		multiAsset[policyID][cbor.ByteString(token.Name)] += token.Amount
+	}
+
+	return cborEncMode.Marshal([]interface{}{v.Coin, multiAsset})
+}
+
+func (v *Value) UnmarshalCBOR(data []byte) error {
+	var coin uint64
+
+	if err := cbor.Unmarshal(data, &coin); err == nil {
+		*v = Value{Coin: coin}
+
+		return nil
+	}
+
+	var raw struct {
+		_          struct{} `cbor:",toarray"`
+		Coin       uint64
+		MultiAsset map[cbor.ByteString]map[cbor.ByteString]uint64
+	}
+
+	if err := cbor.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid value cbor: %w", err)
+	}
+
+	result := Value{Coin: raw.Coin}
+
+	for policyID, assets := range raw.MultiAsset {
+		for name, amount := range assets {
+			result.setAsset(hex.EncodeToString([]byte(policyID)), string(name), amount)
+		}
+	}
+
+	*v = result.Normalize()
+
+	return nil
+}
+
+type valueJSON struct {
+	Coin       uint64                       `json:"coin"`
+	MultiAsset map[string]map[string]uint64 `json:"multiAsset,omitempty"`
+}
+
+// MarshalJSON encodes value as {"coin": ..., "multiAsset": {policyID: {hexName: quantity}}}
+func (v Value) MarshalJSON() ([]byte, error) {
+	result := valueJSON{Coin: v.Coin}
+
+	for _, token := range v.GetTokens() {
+		if result.MultiAsset == nil {
+			result.MultiAsset = map[string]map[string]uint64{}
+		}
+
+		if result.MultiAsset[token.PolicyID] == nil {
+			result.MultiAsset[token.PolicyID] = map[string]uint64{}
+		}
+
+		result.MultiAsset[token.PolicyID][hex.EncodeToString([]byte(token.Name))] = token.Amount
+	}
+
+	return json.Marshal(result)
+}
+
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var raw valueJSON
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	result := Value{Coin: raw.Coin}
+
+	for policyID, assets := range raw.MultiAsset {
+		for hexName, amount := range assets {
+			name, err := hex.DecodeString(hexName)
+			if err != nil {
+				return fmt.Errorf("invalid asset name %s: %w", hexName, err)
+			}
+
+			if err := result.addAsset(policyID, string(name), amount); err != nil {
+				return err
+			}
+		}
+	}
+
+	*v = result.Normalize()
+
+	return nil
+}
+
+func (v *Value) addAsset(policyID string, name string, amount uint64) error {
+	current := v.GetAsset(policyID, name)
+
+	sum, carry := bits.Add64(current, amount, 0)
+	if carry != 0 {
+		return fmt.Errorf("%w: %s (first, second) = (%d, %d)",
+			ErrValueOverflow, NewTokenAmount(policyID, name, 0).TokenName(), current, amount)
+	}
+
+	v.setAsset(policyID, name, sum)
+
+	return nil
+}
+
+// mergeAsset adds quantity of the native token, sum larger than math.MaxUint64 is math.MaxUint64
+func (v *Value) mergeAsset(policyID string, name string, amount uint64) {
+	if err := v.addAsset(policyID, name, amount); err != nil {
+		v.setAsset(policyID, name, math.MaxUint64)
+	}
+}
+
+// setAsset sets quantity of the native token. Policy ids are stored lowercase (as hex encoded by the ledger)
+func (v *Value) setAsset(policyID string, name string, amount uint64) {
+	policyID = strings.ToLower(policyID)
+
+	if v.MultiAsset == nil {
+		v.MultiAsset = map[string]map[string]uint64{}
+	}
+
+	if v.MultiAsset[policyID] == nil {
+		v.MultiAsset[policyID] = map[string]uint64{}
+	}
+
+	v.MultiAsset[policyID][name] = amount
+}
@@ -0,0 +1,223 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValue(t *testing.T) {
+	t.Parallel()
+
+	const (
+		policyID1 = "29f8873beb52e126f207a2dfd50f7cff556806b5b4cba9834a7b26a8"
+		policyID2 = "72f3d1e6c885e4d0bdcf5250513778dbaa851c0b4bfe3ed4e1bcceb0"
+	)
+
+	value1 := mustNewValue(t, 1_000_000,
+		NewTokenAmount(policyID1, "Route3", 50),
+		NewTokenAmount(policyID2, "Kash_Token", 10),
+		NewTokenAmount(policyID1, "Route3", 4),
+		NewTokenAmount(policyID2, "zero", 0),
+	)
+	value2 := mustNewValue(t, 300_000, NewTokenAmount(policyID1, "Route3", 54))
+
+	t.Run("normalization", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, uint64(54), value1.GetAsset(policyID1, "Route3"))
+		require.Equal(t, []TokenAmount{
+			NewTokenAmount(policyID1, "Route3", 54),
+			NewTokenAmount(policyID2, "Kash_Token", 10),
+		}, value1.GetTokens())
+		require.Len(t, value1.MultiAsset[policyID2], 1)
+
+		require.True(t, Value{}.IsZero())
+		require.True(t, Value{MultiAsset: map[string]map[string]uint64{policyID1: {"a": 0}}}.IsZero())
+		require.False(t, mustNewValue(t, 0, NewTokenAmount(policyID1, "a", 1)).IsZero())
+		require.Nil(t, Value{MultiAsset: map[string]map[string]uint64{policyID1: {}}}.Normalize().MultiAsset)
+	})
+
+	t.Run("arithmetic", func(t *testing.T) {
+		t.Parallel()
+
+		sum, err := value1.Add(value2)
+		require.NoError(t, err)
+
+		require.Equal(t, uint64(1_300_000), sum.Coin)
+		require.Equal(t, uint64(108), sum.GetAsset(policyID1, "Route3"))
+		require.Equal(t, uint64(54), value1.GetAsset(policyID1, "Route3")) // original is not changed
+
+		diff, err := value1.Sub(value2)
+		require.NoError(t, err)
+		require.True(t, diff.Equal(mustNewValue(t, 700_000, NewTokenAmount(policyID2, "Kash_Token", 10))))
+
+		_, err = value2.Sub(value1)
+		require.ErrorIs(t, err, ErrValueUnderflow)
+
+		_, err = Value{Coin: math.MaxUint64}.Add(value2)
+		require.ErrorIs(t, err, ErrValueOverflow)
+
+		_, err = value1.Add(mustNewValue(t, 0, NewTokenAmount(policyID1, "Route3", math.MaxUint64-53)))
+		require.ErrorIs(t, err, ErrValueOverflow)
+
+		_, err = NewValue(0, NewTokenAmount(policyID1, "a", math.MaxUint64), NewTokenAmount(policyID1, "a", 1))
+		require.ErrorIs(t, err, ErrValueOverflow)
+
+		_, err = Value{Coin: 2_000_000}.Sub(value2)
+		require.ErrorIs(t, err, ErrValueUnderflow)
+		require.ErrorContains(t, err, policyID1+".526f75746533")
+	})
+
+	t.Run("comparison", func(t *testing.T) {
+		t.Parallel()
+
+		cmp, ok := value1.Compare(value1.Clone())
+		require.True(t, ok)
+		require.Equal(t, 0, cmp)
+
+		cmp, ok = value1.Compare(value2)
+		require.True(t, ok)
+		require.Equal(t, 1, cmp)
+
+		cmp, ok = value2.Compare(value1)
+		require.True(t, ok)
+		require.Equal(t, -1, cmp)
+
+		_, ok = Value{Coin: 2_000_000}.Compare(value2)
+		require.False(t, ok)
+
+		require.True(t, value1.GreaterOrEqual(value2))
+		require.False(t, value2.GreaterOrEqual(value1))
+	})
+
+	t.Run("sum map", func(t *testing.T) {
+		t.Parallel()
+
+		sumMap := value1.ToSumMap()
+
+		require.Equal(t, map[string]uint64{
+			AdaTokenName:                        1_000_000,
+			policyID1 + ".526f75746533":         54,
+			policyID2 + ".4b6173685f546f6b656e": 10,
+		}, sumMap)
+
+		value, err := NewValueFromSumMap(sumMap)
+		require.NoError(t, err)
+		require.True(t, value.Equal(value1))
+	})
+
+	t.Run("cbor", func(t *testing.T) {
+		t.Parallel()
+
+		bytes, err := cbor.Marshal(Value{Coin: 1_000_000})
+		require.NoError(t, err)
+		require.Equal(t, "1a000f4240", hex.EncodeToString(bytes))
+
+		bytes, err = cbor.Marshal(value1)
+		require.NoError(t, err)
+
+		var value Value
+
+		require.NoError(t, cbor.Unmarshal(bytes, &value))
+		require.Equal(t, value1, value)
+
+		require.NoError(t, cbor.Unmarshal([]byte{0x1a, 0x00, 0x0f, 0x42, 0x40}, &value))
+		require.Equal(t, Value{Coin: 1_000_000}, value)
+
+		_, err = cbor.Marshal(mustNewValue(t, 1, NewTokenAmount("01", "a", 1)))
+		require.ErrorContains(t, err, "invalid policy id")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		bytes, err := json.Marshal(value2)
+		require.NoError(t, err)
+		require.Equal(t, `{"coin":300000,"multiAsset":{"`+policyID1+`":{"526f75746533":54}}}`, string(bytes))
+
+		var value Value
+
+		require.NoError(t, json.Unmarshal(bytes, &value))
+		require.Equal(t, value2, value)
+
+		require.NoError(t, json.Unmarshal([]byte(`{"coin":5}`), &value))
+		require.Equal(t, Value{Coin: 5}, value)
+
+		upperPolicyID1 := strings.ToUpper(policyID1)
+
+		require.NoError(t, json.Unmarshal([]byte(
+			`{"coin":300000,"multiAsset":{"`+upperPolicyID1+`":{"526f75746533":50},"`+policyID1+`":{"526f75746533":4}}}`), &value))
+		require.Equal(t, value2, value)
+	})
+
+	t.Run("policy id case", func(t *testing.T) {
+		t.Parallel()
+
+		upperPolicyID1 := strings.ToUpper(policyID1)
+
+		value := mustNewValue(t, 300_000,
+			NewTokenAmount(upperPolicyID1, "Route3", 50),
+			NewTokenAmount(policyID1, "Route3", 4),
+		)
+
+		require.Equal(t, value2, value)
+		require.Equal(t, uint64(54), value.GetAsset(upperPolicyID1, "Route3"))
+
+		value, err := value.Sub(mustNewValue(t, 0, NewTokenAmount(upperPolicyID1, "Route3", 54)))
+		require.NoError(t, err)
+		require.Equal(t, Value{Coin: 300_000}, value)
+	})
+
+	t.Run("utxo and output", func(t *testing.T) {
+		t.Parallel()
+
+		utxo, err := NewUtxo("0x1", 2, 300_000, NewTokenAmount(policyID1, "Route3", 54))
+		require.NoError(t, err)
+		require.Equal(t, value2, utxo.Value)
+		require.Equal(t, uint64(300_000), utxo.Amount)
+		require.Equal(t, []TokenAmount{NewTokenAmount(policyID1, "Route3", 54)}, utxo.Tokens)
+
+		value, err := utxo.GetValue()
+		require.NoError(t, err)
+		require.Equal(t, value2, value)
+
+		// legacy fields are used when value is not set
+		value, err = Utxo{Amount: 300_000, Tokens: utxo.Tokens}.GetValue()
+		require.NoError(t, err)
+		require.Equal(t, value2, value)
+
+		utxo.Amount = 1
+
+		_, err = utxo.GetValue()
+		require.ErrorContains(t, err, "do not match value")
+
+		output := NewTxOutputWithValue("addr", value2)
+
+		value, err = output.GetValue()
+		require.NoError(t, err)
+		require.Equal(t, value2, value)
+
+		output.Value = Value{Coin: 1_000}
+		output.Amount = 1_000
+		output.Tokens = nil
+
+		value, err = output.GetValue()
+		require.NoError(t, err)
+		require.Equal(t, Value{Coin: 1_000}, value)
+	})
+}
+
+func mustNewValue(t *testing.T, coin uint64, tokens ...TokenAmount) Value {
+	t.Helper()
+
+	value, err := NewValue(coin, tokens...)
+	require.NoError(t, err)
+
+	return value
+}
@@ -3,9 +3,11 @@ package core
 import (
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"os"
 
 	"github.com/fxamacker/cbor/v2"
+	"github.com/igorcrevar/go-cardano-tx/core/bech32"
 )
 
 const (
@@ -21,8 +23,45 @@ const (
 	PaymentSigningKeyShelleyDesc      = "Payment Signing Key"
 	PaymentVerificationKeyShelley     = "PaymentVerificationKeyShelley_ed25519"
 	PaymentVerificationKeyShelleyDesc = "Payment Verification Key"
+
+	// extended (BIP32-Ed25519) keys: kL‖kR‖publicKey‖chainCode and publicKey‖chainCode
+	PaymentExtendedSigningKeyShelley      = "PaymentExtendedSigningKeyShelley_ed25519_bip32"
+	PaymentExtendedVerificationKeyShelley = "PaymentExtendedVerificationKeyShelley_ed25519_bip32"
+	StakeExtendedSigningKeyShelley        = "StakeExtendedSigningKeyShelley_ed25519_bip32"
+	StakeExtendedVerificationKeyShelley   = "StakeExtendedVerificationKeyShelley_ed25519_bip32"
+
+	DRepSigningKey                   = "DRepSigningKey_ed25519"
+	DRepSigningKeyDesc               = "Delegated Representative Signing Key"
+	DRepVerificationKey              = "DRepVerificationKey_ed25519"
+	DRepVerificationKeyDesc          = "Delegated Representative Verification Key"
+	CommitteeHotSigningKey           = "ConstitutionalCommitteeHotSigningKey_ed25519"
+	CommitteeHotSigningKeyDesc       = "Constitutional Committee Hot Signing Key"
+	CommitteeHotVerificationKey      = "ConstitutionalCommitteeHotVerificationKey_ed25519"
+	CommitteeHotVerificationKeyDesc  = "Constitutional Committee Hot Verification Key"
+	CommitteeColdSigningKey          = "ConstitutionalCommitteeColdSigningKey_ed25519"
+	CommitteeColdSigningKeyDesc      = "Constitutional Committee Cold Signing Key"
+	CommitteeColdVerificationKey     = "ConstitutionalCommitteeColdVerificationKey_ed25519"
+	CommitteeColdVerificationKeyDesc = "Constitutional Committee Cold Verification Key"
 )
 
+// keyBech32Prefixes maps key type to the bech32 prefix of the key (CIP-5 and CIP-105)
+var keyBech32Prefixes = map[string]string{
+	PaymentSigningKeyShelley:              "addr_sk",
+	PaymentVerificationKeyShelley:         "addr_vk",
+	StakeSigningKeyShelley:                "stake_sk",
+	StakeVerificationKeyShelley:           "stake_vk",
+	PaymentExtendedSigningKeyShelley:      "addr_xsk",
+	PaymentExtendedVerificationKeyShelley: "addr_xvk",
+	StakeExtendedSigningKeyShelley:        "stake_xsk",
+	StakeExtendedVerificationKeyShelley:   "stake_xvk",
+	DRepSigningKey:                        DRepSigningKeyPrefix,
+	DRepVerificationKey:                   DRepVerificationKeyPrefix,
+	CommitteeHotSigningKey:                CommitteeHotSigningKeyPrefix,
+	CommitteeHotVerificationKey:           CommitteeHotVerificationKeyPrefix,
+	CommitteeColdSigningKey:               CommitteeColdSigningKeyPrefix,
+	CommitteeColdVerificationKey:          CommitteeColdVerificationKeyPrefix,
+}
+
 type Wallet struct {
 	VerificationKey      []byte `json:"vkey"`
 	SigningKey           []byte `json:"skey"`
@@ -47,6 +86,35 @@ func NewStakeWallet(verificationKey []byte, signingKey []byte,
 	}
 }
 
+// NewWalletWithValidation creates wallet like NewWallet but validates the keys. Verification key must have
+// 32 bytes (chain code of the extended verification key must be removed, see Key.GetVerificationKey)
+// and signing key is either ed25519 seed or extended key
+func NewWalletWithValidation(verificationKey []byte, signingKey []byte) (*Wallet, error) {
+	verificationKey, signingKey, err := getValidatedWalletKeys(verificationKey, signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWallet(verificationKey, signingKey), nil
+}
+
+// NewStakeWalletWithValidation creates wallet with the stake key. Keys are validated in the same way
+// as in NewWalletWithValidation
+func NewStakeWalletWithValidation(verificationKey []byte, signingKey []byte,
+	stakeVerificationKey []byte, stakeSigningKey []byte) (*Wallet, error) {
+	verificationKey, signingKey, err := getValidatedWalletKeys(verificationKey, signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	stakeVerificationKey, stakeSigningKey, err = getValidatedWalletKeys(stakeVerificationKey, stakeSigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("stake key: %w", err)
+	}
+
+	return NewStakeWallet(verificationKey, signingKey, stakeVerificationKey, stakeSigningKey), nil
+}
+
 // GenerateWallet generates wallet
 func GenerateWallet(isStake bool) (*Wallet, error) {
 	signingKey, verificationKey, err := GenerateKeyPair()
@@ -74,6 +142,31 @@ func (w Wallet) GetTransactionVerificationKey() []byte {
 	return w.VerificationKey
 }
 
+// GetStakeSigner returns signer for the stake key (certificates and withdrawals must be witnessed by it)
+func (w Wallet) GetStakeSigner() ITxSigner {
+	return &Wallet{
+		VerificationKey: w.StakeVerificationKey,
+		SigningKey:      w.StakeSigningKey,
+	}
+}
+
+// getValidatedWalletKeys pads short keys and checks sizes of the verification key and the signing key
+func getValidatedWalletKeys(verificationKey, signingKey []byte) ([]byte, []byte, error) {
+	verificationKey, signingKey = PadKeyToSize(verificationKey), PadKeyToSize(signingKey)
+
+	if len(verificationKey) != KeySize {
+		return nil, nil, fmt.Errorf("invalid verification key size: %d", len(verificationKey))
+	}
+
+	if len(signingKey) != KeySize {
+		if _, err := getExtendedSigningKey(signingKey); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return verificationKey, signingKey, nil
+}
+
 type Key struct {
 	Type        string `json:"type"`
 	Description string `json:"description"`
@@ -112,6 +205,71 @@ func (k Key) GetKeyBytes() ([]byte, error) {
 	return GetKeyBytes(k.Hex)
 }
 
+// GetSigner returns signer of the signing key. Extended signing keys (PaymentExtendedSigningKeyShelley_ed25519_bip32,
+// StakeExtendedSigningKeyShelley_ed25519_bip32) sign without the seed
+func (k Key) GetSigner() (ITxSigner, error) {
+	bytes, err := k.GetKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	switch k.Type {
+	case PaymentExtendedSigningKeyShelley, StakeExtendedSigningKeyShelley:
+		return NewExtendedSigner(bytes)
+	case PaymentSigningKeyShelley, StakeSigningKeyShelley, DRepSigningKey,
+		CommitteeHotSigningKey, CommitteeColdSigningKey:
+		if len(bytes) != KeySize {
+			return nil, fmt.Errorf("invalid signing key size: %d", len(bytes))
+		}
+
+		return NewWallet(GetVerificationKeyFromSigningKey(bytes), bytes), nil
+	default:
+		return nil, fmt.Errorf("key type %s is not signing key", k.Type)
+	}
+}
+
+// GetVerificationKey returns 32 bytes verification key. Chain code of the extended verification keys
+// (PaymentExtendedVerificationKeyShelley_ed25519_bip32, StakeExtendedVerificationKeyShelley_ed25519_bip32) is removed
+func (k Key) GetVerificationKey() ([]byte, error) {
+	bytes, err := k.GetKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	switch k.Type {
+	case PaymentExtendedVerificationKeyShelley, StakeExtendedVerificationKeyShelley:
+		if len(bytes) != KeySize+ChainCodeSize {
+			return nil, fmt.Errorf("invalid extended verification key size: %d", len(bytes))
+		}
+
+		return bytes[:KeySize], nil
+	case PaymentVerificationKeyShelley, StakeVerificationKeyShelley, DRepVerificationKey,
+		CommitteeHotVerificationKey, CommitteeColdVerificationKey:
+		if len(bytes) != KeySize {
+			return nil, fmt.Errorf("invalid verification key size: %d", len(bytes))
+		}
+
+		return bytes, nil
+	default:
+		return nil, fmt.Errorf("key type %s is not verification key", k.Type)
+	}
+}
+
+// Bech32 returns bech32 representation of the key (addr_vk1..., drep_vk1..., cc_hot_sk1...)
+func (k Key) Bech32() (string, error) {
+	prefix, exists := keyBech32Prefixes[k.Type]
+	if !exists {
+		return "", fmt.Errorf("bech32 prefix of the key type %s is unknown", k.Type)
+	}
+
+	bytes, err := k.GetKeyBytes()
+	if err != nil {
+		return "", err
+	}
+
+	return bech32.EncodeFromBase256(prefix, bytes)
+}
+
 func (k Key) WriteToFile(filePath string) error {
 	bytes, err := json.Marshal(k)
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 
+	"filippo.io/edwards25519"
 	"github.com/fxamacker/cbor/v2"
 	"github.com/igorcrevar/go-cardano-tx/core/bech32"
 	"golang.org/x/crypto/blake2b"
@@ -25,8 +26,23 @@ func GenerateKeyPair() ([]byte, []byte, error) {
 	return seed, GetVerificationKeyFromSigningKey(seed), nil
 }
 
-// GetVerificationKeyFromSigningKey retrieves verification/public key from signing/private key
+// GetVerificationKeyFromSigningKey retrieves verification/public key from signing/private key.
+// Signing key is either ed25519 seed or extended (BIP32-Ed25519) key
 func GetVerificationKeyFromSigningKey(signingKey []byte) []byte {
+	if len(signingKey) > KeySize {
+		extendedKey, err := getExtendedSigningKey(signingKey)
+		if err != nil {
+			return nil
+		}
+
+		kL, err := getExtendedKeyScalar(extendedKey)
+		if err != nil {
+			return nil
+		}
+
+		return new(edwards25519.Point).ScalarBaseMult(kL).Bytes()
+	}
+
 	return ed25519.NewKeyFromSeed(signingKey).Public().(ed25519.PublicKey) //nolint:forcetypeassert
 }
 
@@ -45,8 +61,12 @@ func VerifyWitness(txHash string, witness []byte) error {
 	return VerifyMessage(txHashBytes, vKey, signature)
 }
 
-// SignMessage signs message
+// SignMessage signs message. Signing key is either ed25519 seed or extended (BIP32-Ed25519) key
 func SignMessage(signingKey, verificationKey, message []byte) (result []byte, err error) {
+	if len(signingKey) > KeySize {
+		return signMessageExtended(signingKey, verificationKey, message)
+	}
+
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("error: %v", r)
@@ -102,25 +122,19 @@ func GetKeyHash(bytes []byte) (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-// PadKeyToSize pads key to 32 bytes
+// PadKeyToSize pads key shorter than 32 bytes with leading zeroes. Longer keys (extended keys)
+// are returned as they are
 func PadKeyToSize(key []byte) []byte {
-	// If the key is already 32 bytes long, return it as is
-	if len(key) == KeySize {
+	if len(key) >= KeySize {
 		return key
 	}
 
-	// If the key is shorter than 32 bytes, pad with leading zeroes
-	if len(key) < KeySize {
-		return append(make([]byte, KeySize-len(key)), key...)
-	}
-
-	// If the key is longer than 32 bytes, truncate it to 32 bytes
-	return key[:KeySize]
+	return append(make([]byte, KeySize-len(key)), key...)
 }
 
 // GetKeyBytes extracts the original key bytes from a given string. Supported formats:
 // - Hex + CBOR encoded string: Attempts to decode the key assuming it is hex-encoded,
-// - Bech32 encoded keys: Handles formats like addr_vk, addr_sk, stake_vk, stake_sk
+// - Bech32 encoded keys: Handles formats like addr_vk, addr_sk, stake_vk, stake_sk, drep_vk, cc_hot_vk
 func GetKeyBytes(key string) (result []byte, err error) {
 	if bytes, err := hex.DecodeString(key); err == nil {
 		if err := cbor.Unmarshal(bytes, &result); err != nil {
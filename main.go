@@ -94,35 +94,16 @@ func createTx(
 		return nil, "", err
 	}
 
-	tokens, err := cardano.GetTokensFromSumMap(inputs.Sum)
-	if err != nil {
-		return nil, "", err
-	}
-
-	lovelaceInputsSum := inputs.Sum[cardano.AdaTokenName]
-	outputs := []cardano.TxOutput{
-		{
-			Addr:   receiverAddr,
-			Amount: lovelaceSendAmount,
-		},
-		{
-			Addr:   senderAddress,
-			Tokens: tokens,
-		},
-	}
-
 	builder.SetMetaData(metadataBytes).SetTestNetMagic(testNetMagic)
-	builder.AddInputs(inputs.Inputs...).AddOutputs(outputs...)
+	builder.AddUtxos(inputs.Utxos...).AddOutputs(cardano.TxOutput{
+		Addr:   receiverAddr,
+		Amount: lovelaceSendAmount,
+	})
 
-	fee, err := builder.CalculateFee(1)
-	if err != nil {
+	if _, err := builder.SetWitnessCount(1).Balance(senderAddress); err != nil {
 		return nil, "", err
 	}
 
-	builder.SetFee(fee)
-
-	builder.UpdateOutputAmount(-1, lovelaceInputsSum-lovelaceSendAmount-fee)
-
 	txRaw, txHash, err := builder.Build()
 	if err != nil {
 		return nil, "", err
@@ -238,56 +219,29 @@ func createMultiSigTx(
 		return nil, "", err
 	}
 
-	tokens, err := cardano.GetTokensFromSumMap(multiSigInputs.Sum)
+	multiSigChange, err := multiSigInputs.Value.Sub(cardano.Value{Coin: lovelaceSendAmount})
 	if err != nil {
 		return nil, "", err
 	}
 
-	tokensFee, err := cardano.GetTokensFromSumMap(multiSigFeeInputs.Sum)
-	if err != nil {
-		return nil, "", err
-	}
-
-	lovelaceInputsSum := multiSigInputs.Sum[cardano.AdaTokenName]
-	lovelaceInputsFeeSum := multiSigFeeInputs.Sum[cardano.AdaTokenName]
 	outputs := []cardano.TxOutput{
 		{
 			Addr:   receiverAddr,
 			Amount: lovelaceSendAmount,
 		},
-		{
-			Addr:   multiSigAddr.String(),
-			Amount: lovelaceInputsSum - lovelaceSendAmount,
-			Tokens: tokens,
-		},
-		{
-			Addr:   multiSigFeeAddr.String(),
-			Tokens: tokensFee,
-		},
+		cardano.NewTxOutputWithValue(multiSigAddr.String(), multiSigChange),
 	}
 
 	builder.SetMetaData(metadataBytes).SetTestNetMagic(testNetMagic)
 	builder.AddOutputs(outputs...)
-	builder.AddInputsWithScript(policyScriptMultiSig, multiSigInputs.Inputs...)
-	builder.AddInputsWithScript(policyScriptFeeMultiSig, multiSigFeeInputs.Inputs...)
+	builder.AddUtxosWithScript(policyScriptMultiSig, multiSigInputs.Utxos...)
+	builder.AddUtxosWithScript(policyScriptFeeMultiSig, multiSigFeeInputs.Utxos...)
 
-	fee, err := builder.CalculateFee(0)
-	if err != nil {
+	// change of the fee inputs goes back to the fee multisig address
+	if _, err := builder.Balance(multiSigFeeAddr.String()); err != nil {
 		return nil, "", err
 	}
 
-	builder.SetFee(fee)
-
-	if change := lovelaceInputsFeeSum - fee; change > 0 {
-		builder.UpdateOutputAmount(-1, change)
-	} else {
-		if cnt := len(tokens); cnt > 0 {
-			return nil, "", fmt.Errorf("fee address change is zero but there is %d tokens", cnt)
-		}
-
-		builder.RemoveOutput(-1)
-	}
-
 	txRaw, txHash, err := builder.Build()
 	if err != nil {
 		return nil, "", err
@@ -351,7 +305,10 @@ func loadWallets() ([]*cardano.Wallet, error) {
 			return nil, err
 		}
 
-		wallets[i] = cardano.NewWallet(verificationKey, signingKey)
+		wallets[i], err = cardano.NewWalletWithValidation(verificationKey, signingKey)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return wallets, nil